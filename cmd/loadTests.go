@@ -11,18 +11,17 @@ import (
 	"time"
 
 	"github.com/codeready-toolchain/toolchain-e2e/setup/auth"
-	"github.com/codeready-toolchain/toolchain-e2e/setup/configuration"
 	"github.com/codeready-toolchain/toolchain-e2e/setup/metrics"
 	"github.com/codeready-toolchain/toolchain-e2e/setup/metrics/queries"
 	"github.com/codeready-toolchain/toolchain-e2e/setup/terminal"
-	"github.com/codeready-toolchain/toolchain-e2e/setup/users"
-	"github.com/codeready-toolchain/toolchain-e2e/setup/wait"
 	"github.com/google/uuid"
 	"github.com/gosuri/uiprogress"
 	"github.com/gosuri/uitable/util/strutil"
+	kubeCl "github.com/redhat-appstudio/e2e-tests/pkg/apis/kubernetes"
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
 	"github.com/redhat-appstudio/e2e-tests/pkg/framework"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils/tekton"
 	"github.com/spf13/cobra"
 	k8swait "k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
@@ -39,6 +38,7 @@ var (
 	logConsole           bool
 	failFast             bool
 	disableMetrics       bool
+	clientOptions        = kubeCl.GetDefaultClientOptions()
 )
 
 var (
@@ -48,6 +48,7 @@ var (
 	FailedUserCreations                int64
 	FailedResourceCreations            int64
 	FailedPipelineRuns                 int64
+	PipelineRunPerformances            []*tekton.PipelineRunPerformance
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -88,6 +89,10 @@ func init() {
 	rootCmd.Flags().BoolVarP(&logConsole, "log-to-console", "l", false, "if you want to log to console in addition to the log file")
 	rootCmd.Flags().BoolVar(&failFast, "fail-fast", false, "if you want the test to fail fast at first failure")
 	rootCmd.Flags().BoolVar(&disableMetrics, "disable-metrics", false, "if you want to disable metrics gathering")
+	rootCmd.Flags().Float32Var(&clientOptions.QPS, "client-qps", clientOptions.QPS, "maximum queries per second of the kubernetes clients (0 keeps the client-go default)")
+	rootCmd.Flags().IntVar(&clientOptions.Burst, "client-burst", clientOptions.Burst, "maximum burst of the kubernetes clients (0 keeps the client-go default)")
+	rootCmd.Flags().DurationVar(&clientOptions.Timeout, "client-timeout", clientOptions.Timeout, "timeout of a single request of the kubernetes clients, watches and log streams are not limited (0 means no timeout)")
+	rootCmd.Flags().IntVar(&clientOptions.MaxRetries, "client-retries", clientOptions.MaxRetries, "how many times to retry kubernetes requests failed with 429, 5xx or connection reset")
 }
 
 func logError(errCode int, message string) {
@@ -129,6 +134,7 @@ func setup(cmd *cobra.Command, args []string) {
 	klog.Infof("Batch Size: %d", userBatches)
 
 	klog.Infof("🕖 initializing...\n")
+	kubeCl.SetDefaultClientOptions(clientOptions)
 	framework, err := framework.NewFramework("load-tests")
 	if err != nil {
 		klog.Fatalf("error creating client-go %v", err)
@@ -195,30 +201,15 @@ func setup(cmd *cobra.Command, args []string) {
 	}
 
 	go func() {
-	UserLoop:
-		for AppStudioUsersBar.Incr() {
-			userIndex := AppStudioUsersBar.Current()
-			startTime := time.Now()
-			username := fmt.Sprintf("%s-%04d", usernamePrefix, userIndex)
-			if err := users.Create(framework.AsKubeAdmin.CommonController.KubeRest(), username, constants.HostOperatorNamespace, constants.MemberOperatorNamespace); err != nil {
-				logError(1, fmt.Sprintf("Unable to provision user '%s': %v", username, err))
+		for user := range framework.SandboxController.ProvisionUsersAsync(usernamePrefix, numberOfUsers, userBatches) {
+			AppStudioUsersBar.Incr()
+			if user.Err != nil {
+				logError(1, fmt.Sprintf("Unable to provision user '%s': %v", user.UserName, user.Err))
 				atomic.StoreInt64(&FailedUserCreations, atomic.AddInt64(&FailedUserCreations, 1))
 				continue
 			}
-			if userIndex%userBatches == 0 {
-				for i := userIndex - userBatches + 1; i <= userIndex; i++ {
-					user := fmt.Sprintf("%s-%04d", usernamePrefix, i)
-					usernamespace := fmt.Sprintf("%s-tenant", user)
-					if err := wait.ForNamespace(framework.AsKubeAdmin.CommonController.KubeRest(), usernamespace); err != nil {
-						logError(2, fmt.Sprintf("Unable to find namespace '%s' within %v: %v", usernamespace, configuration.DefaultTimeout, err))
-						atomic.StoreInt64(&FailedUserCreations, atomic.AddInt64(&FailedUserCreations, 1))
-						continue UserLoop
-					}
-					chUsers <- i
-				}
-			}
-			UserCreationTime := time.Since(startTime)
-			AverageUserCreationTime += UserCreationTime
+			AverageUserCreationTime += user.Duration
+			chUsers <- user.Index
 		}
 		close(chUsers)
 		wg.Done()
@@ -257,17 +248,11 @@ func setup(cmd *cobra.Command, args []string) {
 			}
 			ComponentName := fmt.Sprintf("%s-component", username)
 			ComponentContainerImage := fmt.Sprintf("quay.io/%s/test-images:%s-%s", utils.GetQuayIOOrganization(), username, strings.Replace(uuid.New().String(), "-", "", -1))
-			component, err := framework.AsKubeAdmin.HasController.CreateComponent(
-				ApplicationName,
-				ComponentName,
-				usernamespace,
-				QuarkusDevfileSource,
-				"",
-				"",
-				ComponentContainerImage,
-				"",
-				true,
-			)
+			component, err := framework.AsKubeAdmin.HasController.NewComponentBuilder(ApplicationName, ComponentName, usernamespace).
+				WithGitSource(QuarkusDevfileSource, "").
+				WithOutputImage(ComponentContainerImage).
+				WithSkipInitialChecks(true).
+				Create()
 			if err != nil {
 				logError(6, fmt.Sprintf("Unable to create the Component %s: %v", ComponentName, err))
 				atomic.StoreInt64(&FailedResourceCreations, atomic.AddInt64(&FailedResourceCreations, 1))
@@ -312,6 +297,11 @@ func setup(cmd *cobra.Command, args []string) {
 					if pipelineRun.IsDone() {
 						AveragePipelineRunTimePerUser += time.Since(pipelineRun.GetCreationTimestamp().Time)
 						PipelinesBar.Incr()
+						if performance, err := framework.AsKubeAdmin.TektonController.GetPipelineRunPerformance(pipelineRun.Name, usernamespace); err == nil {
+							PipelineRunPerformances = append(PipelineRunPerformances, performance)
+						} else {
+							klog.Infof("Unable to analyze performance of pipeline run %s/%s: %v", usernamespace, pipelineRun.Name, err)
+						}
 					}
 					return pipelineRun.IsDone(), nil
 				})
@@ -325,7 +315,7 @@ func setup(cmd *cobra.Command, args []string) {
 		}()
 	}
 
-	// Todo add cleanup functions that will delete user signups
+	// Users are kept for inspection, remove them with `DRY_RUN=false USER_PREFIXES=<prefix>- mage local:cleanupSandboxUsers`
 
 	wg.Wait()
 	uip.Stop()
@@ -337,6 +327,11 @@ func setup(cmd *cobra.Command, args []string) {
 	klog.Infof("Number of times user creation failed: %d (%.2f %%)", FailedUserCreations, float64(FailedUserCreations)/float64(numberOfUsers))
 	klog.Infof("Number of times resource creation failed: %d (%.2f %%)", FailedResourceCreations, float64(FailedResourceCreations)/float64(numberOfUsers))
 	klog.Infof("Number of times pipeline run failed: %d (%.2f %%)", FailedPipelineRuns, float64(FailedPipelineRuns)/float64(numberOfUsers))
+	if waitPipelines {
+		storePipelineRunPerformances("load-tests-pipelineruns.csv")
+	}
+	tokenMetrics := framework.SandboxController.GetKeycloakTokenMetrics()
+	klog.Infof("Keycloak tokens issued: %d, refreshed: %d, failed refreshes: %d", tokenMetrics.Issued, tokenMetrics.Refreshed, tokenMetrics.RefreshFailures)
 	klog.StopFlushDaemon()
 	klog.Flush()
 	if !disableMetrics {
@@ -344,3 +339,18 @@ func setup(cmd *cobra.Command, args []string) {
 		metricsInstance.PrintResults()
 	}
 }
+
+// storePipelineRunPerformances writes the time breakdown of the pipeline runs of all users into a CSV file
+func storePipelineRunPerformances(path string) {
+	file, err := os.Create(path)
+	if err != nil {
+		klog.Errorf("Error creating pipeline runs performance file: %v", err)
+		return
+	}
+	defer file.Close()
+	if err := tekton.WritePipelineRunPerformanceCSV(file, PipelineRunPerformances...); err != nil {
+		klog.Errorf("Error writing pipeline runs performance file: %v", err)
+		return
+	}
+	klog.Infof("Time breakdown of %d pipeline runs stored in %s", len(PipelineRunPerformances), path)
+}
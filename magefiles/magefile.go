@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -9,26 +10,24 @@ import (
 	"strings"
 	"time"
 
-	"github.com/devfile/library/pkg/util"
-	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 
 	"k8s.io/klog/v2"
 
-	"sigs.k8s.io/yaml"
-
-	"github.com/google/go-containerregistry/pkg/authn"
-	"github.com/google/go-containerregistry/pkg/name"
-	remoteimg "github.com/google/go-containerregistry/pkg/v1/remote"
 	gh "github.com/google/go-github/v44/github"
 	"github.com/magefile/mage/sh"
+	buildservice "github.com/redhat-appstudio/build-service/api/v1alpha1"
 	"github.com/redhat-appstudio/e2e-tests/magefiles/installation"
 	"github.com/redhat-appstudio/e2e-tests/pkg/apis/github"
+	kubeCl "github.com/redhat-appstudio/e2e-tests/pkg/apis/kubernetes"
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
+	"github.com/redhat-appstudio/e2e-tests/pkg/sandbox"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
-	tektonapi "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils/has"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils/tekton/bundle"
 )
 
 var (
@@ -178,6 +177,196 @@ func (Local) CleanupGithubOrg() error {
 	return nil
 }
 
+// Deletes expired ephemeral repositories (created by github.CreateEphemeralRepository) from redhat-appstudio-qe Github org.
+// Unlike CleanupGithubOrg, repositories are selected by the expiry stored in their topics, not by name.
+// Env vars to configure this target: DRY_RUN (optional) - defaults to true
+func (Local) CleanupEphemeralRepositories() error {
+	githubToken := os.Getenv("GITHUB_TOKEN")
+	if githubToken == "" {
+		return fmt.Errorf("env var GITHUB_TOKEN is not set")
+	}
+	dryRun, err := strconv.ParseBool(utils.GetEnv("DRY_RUN", "true"))
+	if err != nil {
+		return fmt.Errorf("unable to parse DRY_RUN env var\n\t%s", err)
+	}
+
+	githubOrgName := utils.GetEnv(constants.GITHUB_E2E_ORGANIZATION_ENV, "redhat-appstudio-qe")
+	ghClient, err := github.NewGithubClient(githubToken, githubOrgName)
+	if err != nil {
+		return err
+	}
+	reposToDelete, err := ghClient.ListExpiredEphemeralRepositories(time.Now())
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		klog.Info("Dry run enabled. Listing repositories that would be deleted:")
+	}
+
+	for _, repo := range reposToDelete {
+		m, _ := github.ParseEphemeralRepositoryMetadata(repo)
+		if dryRun {
+			klog.Infof("\t%s (run: %s, expired: %s, spec: %s)", repo.GetName(), m.RunID, m.ExpiresAt, m.Spec)
+		} else {
+			if err := ghClient.DeleteRepository(repo); err != nil {
+				klog.Warningf("error deleting repository: %s\n", err)
+			}
+		}
+	}
+	if dryRun {
+		klog.Info("If you really want to delete these repositories, run `DRY_RUN=false mage local:cleanupEphemeralRepositories`")
+	}
+	return nil
+}
+
+// Deletes UserSignups (together with their tenant namespaces) and keycloak users left behind by failed e2e and load test runs.
+// Env vars to configure this target: USER_PREFIXES (optional) - comma separated prefixes of user names, defaults to prefixes used by the suites,
+// OLDER_THAN (optional) - defaults to 24h, DRY_RUN (optional) - defaults to true
+func (Local) CleanupSandboxUsers() error {
+	dryRun, err := strconv.ParseBool(utils.GetEnv("DRY_RUN", "true"))
+	if err != nil {
+		return fmt.Errorf("unable to parse DRY_RUN env var\n\t%s", err)
+	}
+	olderThan, err := time.ParseDuration(utils.GetEnv("OLDER_THAN", "24h"))
+	if err != nil {
+		return fmt.Errorf("unable to parse OLDER_THAN env var\n\t%s", err)
+	}
+	var prefixes []string
+	if p := os.Getenv("USER_PREFIXES"); p != "" {
+		prefixes = strings.Split(p, ",")
+	}
+
+	kubeClient, err := kubeCl.NewAdminKubernetesClient()
+	if err != nil {
+		return err
+	}
+	sandboxController, err := sandbox.NewDevSandboxController(kubeClient.KubeInterface(), kubeClient.KubeRest())
+	if err != nil {
+		return err
+	}
+
+	report, err := sandboxController.CleanupUsers(sandbox.UserCleanupOptions{Prefixes: prefixes, OlderThan: olderThan, DryRun: dryRun})
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		klog.Info("Dry run enabled. Listing users that would be deleted:")
+	}
+	klog.Infof("UserSignups (%d):", len(report.UserSignups))
+	for _, name := range report.UserSignups {
+		klog.Infof("\t%s", name)
+	}
+	klog.Infof("Keycloak users (%d):", len(report.KeycloakUsers))
+	for _, name := range report.KeycloakUsers {
+		klog.Infof("\t%s", name)
+	}
+	for _, err := range report.Errors {
+		klog.Warning(err)
+	}
+	if dryRun {
+		klog.Info("If you really want to delete these users, run `DRY_RUN=false [USER_PREFIXES=<prefixes>] [OLDER_THAN=<duration>] mage local:cleanupSandboxUsers`")
+	}
+	if len(report.Errors) > 0 {
+		return fmt.Errorf("failed to delete %d users", len(report.Errors))
+	}
+	return nil
+}
+
+// Exports an application with its related resources from a namespace into a YAML bundle
+func (Local) ExportApplication() error {
+	applicationName := os.Getenv("APPLICATION_NAME")
+	namespace := os.Getenv("NAMESPACE")
+	if applicationName == "" || namespace == "" {
+		return fmt.Errorf("APPLICATION_NAME and NAMESPACE env vars are required")
+	}
+	bundleFile := utils.GetEnv("BUNDLE_FILE", fmt.Sprintf("%s.yaml", applicationName))
+
+	kubeClient, err := kubeCl.NewAdminKubernetesClient()
+	if err != nil {
+		return err
+	}
+	bundle, err := has.ExportApplicationBundle(kubeClient.KubeRest(), applicationName, namespace)
+	if err != nil {
+		return err
+	}
+	if err := bundle.Save(bundleFile); err != nil {
+		return fmt.Errorf("error when saving application bundle to %s: %v", bundleFile, err)
+	}
+
+	klog.Infof("application %s exported to %s with %d components", applicationName, bundleFile, len(bundle.Components))
+	if len(bundle.Secrets) > 0 {
+		klog.Infof("secrets referenced by the application need to be created before importing it: %s", strings.Join(bundle.Secrets, ", "))
+	}
+	return nil
+}
+
+// Imports an application bundle created by local:exportApplication into a namespace
+func (Local) ImportApplication() error {
+	bundleFile := os.Getenv("BUNDLE_FILE")
+	namespace := os.Getenv("NAMESPACE")
+	if bundleFile == "" || namespace == "" {
+		return fmt.Errorf("BUNDLE_FILE and NAMESPACE env vars are required")
+	}
+
+	bundle, err := has.LoadApplicationBundle(bundleFile)
+	if err != nil {
+		return err
+	}
+	kubeClient, err := kubeCl.NewAdminKubernetesClient()
+	if err != nil {
+		return err
+	}
+	imported, err := has.ImportApplicationBundle(kubeClient.KubeRest(), bundle, has.ApplicationImportOptions{
+		Namespace:               namespace,
+		NameSuffix:              os.Getenv("NAME_SUFFIX"),
+		ApplicationReadyTimeout: 3 * time.Minute,
+	})
+	if err != nil {
+		return err
+	}
+
+	klog.Infof("application %s imported into %s namespace with %d components", imported.Application.Name, namespace, len(imported.Components))
+	return nil
+}
+
+// Prints the differences between a pipeline bundle in the cluster's build-pipeline-selector and a candidate bundle, including the task bundles they reference
+func (Local) DiffBuildPipelineBundle() error {
+	candidateBundle := os.Getenv("CANDIDATE_BUNDLE")
+	if candidateBundle == "" {
+		return fmt.Errorf("CANDIDATE_BUNDLE env var is required")
+	}
+	pipelineName := utils.GetEnv("PIPELINE_NAME", "docker-build")
+
+	kubeClient, err := kubeCl.NewAdminKubernetesClient()
+	if err != nil {
+		return err
+	}
+	selector := &buildservice.BuildPipelineSelector{}
+	if err := kubeClient.KubeRest().Get(context.TODO(), types.NamespacedName{Name: "build-pipeline-selector", Namespace: "build-service"}, selector); err != nil {
+		return fmt.Errorf("error when getting the build pipeline selector: %v", err)
+	}
+	var clusterBundle string
+	for _, s := range selector.Spec.Selectors {
+		if s.PipelineRef.Name == pipelineName && s.PipelineRef.Bundle != "" {
+			clusterBundle = s.PipelineRef.Bundle
+			break
+		}
+	}
+	if clusterBundle == "" {
+		return fmt.Errorf("could not find a bundle for pipeline %s in the build pipeline selector", pipelineName)
+	}
+
+	diff, err := bundle.NewInspector().Diff(clusterBundle, candidateBundle, pipelineName)
+	if err != nil {
+		return err
+	}
+	fmt.Print(diff.String())
+	klog.Infof("pipeline %s: %s", pipelineName, diff.Summary())
+	return nil
+}
+
 func (ci CI) TestE2E() error {
 	var testFailure bool
 
@@ -259,6 +448,8 @@ func (ci CI) setRequiredEnvVars() error {
 		return nil
 	}
 
+	var bundleOverrides []bundle.Override
+
 	if openshiftJobSpec.Refs.Repo != "e2e-tests" {
 
 		if strings.Contains(jobName, "-service") || strings.Contains(jobName, "image-controller") {
@@ -290,63 +481,12 @@ func (ci CI) setRequiredEnvVars() error {
 				os.Setenv(fmt.Sprintf("%s_REQPROCESSOR_IMAGE", envVarPrefix), os.Getenv("CI_JBS_REQPROCESSOR_IMAGE"))
 				os.Setenv(fmt.Sprintf("%s_CACHE_IMAGE", envVarPrefix), os.Getenv("CI_JBS_CACHE_IMAGE"))
 
-				klog.Infof("going to override default Tekton bundle for the purpose of testing jvm-build-service PR")
-				var err error
-				var defaultBundleRef string
-				var tektonObj runtime.Object
-
-				tag := fmt.Sprintf("%d-%s", time.Now().Unix(), util.GenerateRandomString(4))
-				var newS2iJavaTaskRef, _ = name.ParseReference(fmt.Sprintf("%s:task-bundle-%s", constants.DefaultImagePushRepo, tag))
-				var newJavaBuilderPipelineRef, _ = name.ParseReference(fmt.Sprintf("%s:pipeline-bundle-%s", constants.DefaultImagePushRepo, tag))
-				var newReqprocessorImage = os.Getenv("JVM_BUILD_SERVICE_REQPROCESSOR_IMAGE")
-				var newTaskYaml, newPipelineYaml []byte
-
-				if err = utils.CreateDockerConfigFile(os.Getenv("QUAY_TOKEN")); err != nil {
-					return fmt.Errorf("failed to create docker config file: %+v", err)
-				}
-				if defaultBundleRef, err = utils.GetDefaultPipelineBundleRef(constants.BuildPipelineSelectorYamlURL, "Java"); err != nil {
-					return fmt.Errorf("failed to get the pipeline bundle ref: %+v", err)
-				}
-				if tektonObj, err = utils.ExtractTektonObjectFromBundle(defaultBundleRef, "pipeline", "java-builder"); err != nil {
-					return fmt.Errorf("failed to extract the Tekton Pipeline from bundle: %+v", err)
-				}
-				javaPipelineObj := tektonObj.(tektonapi.PipelineObject)
-
-				var currentS2iJavaTaskRef string
-				for _, t := range javaPipelineObj.PipelineSpec().Tasks {
-					if t.TaskRef.Name == "s2i-java" {
-						currentS2iJavaTaskRef = t.TaskRef.Bundle
-						t.TaskRef.Bundle = newS2iJavaTaskRef.String()
-					}
-				}
-				if tektonObj, err = utils.ExtractTektonObjectFromBundle(currentS2iJavaTaskRef, "task", "s2i-java"); err != nil {
-					return fmt.Errorf("failed to extract the Tekton Task from bundle: %+v", err)
-				}
-				taskObj := tektonObj.(tektonapi.TaskObject)
-
-				for i, s := range taskObj.TaskSpec().Steps {
-					if s.Name == "analyse-dependencies-java-sbom" {
-						taskObj.TaskSpec().Steps[i].Image = newReqprocessorImage
-					}
-				}
-
-				if newTaskYaml, err = yaml.Marshal(taskObj); err != nil {
-					return fmt.Errorf("error when marshalling a new task to YAML: %v", err)
-				}
-				if newPipelineYaml, err = yaml.Marshal(javaPipelineObj); err != nil {
-					return fmt.Errorf("error when marshalling a new pipeline to YAML: %v", err)
-				}
-
-				keychain := authn.NewMultiKeychain(authn.DefaultKeychain)
-				authOption := remoteimg.WithAuthFromKeychain(keychain)
-
-				if err = utils.BuildAndPushTektonBundle(newTaskYaml, newS2iJavaTaskRef, authOption); err != nil {
-					return fmt.Errorf("error when building/pushing a tekton task bundle: %v", err)
-				}
-				if err = utils.BuildAndPushTektonBundle(newPipelineYaml, newJavaBuilderPipelineRef, authOption); err != nil {
-					return fmt.Errorf("error when building/pushing a tekton pipeline bundle: %v", err)
-				}
-				os.Setenv(constants.CUSTOM_JAVA_PIPELINE_BUILD_BUNDLE_ENV, newJavaBuilderPipelineRef.String())
+				bundleOverrides = append(bundleOverrides, bundle.Override{
+					Selector: "Java",
+					Task:     "s2i-java",
+					Step:     "analyse-dependencies-java-sbom",
+					Image:    os.Getenv("JVM_BUILD_SERVICE_REQPROCESSOR_IMAGE"),
+				})
 			case strings.Contains(jobName, "build-service"):
 				envVarPrefix = "BUILD_SERVICE"
 				imageTagSuffix = "build-service-image"
@@ -380,6 +520,41 @@ func (ci CI) setRequiredEnvVars() error {
 		}
 	}
 
+	return ci.overrideBuildPipelineBundles(bundleOverrides)
+}
+
+// overrideBuildPipelineBundles pushes build pipeline bundles with the given overrides, and the ones from
+// BUILD_PIPELINE_BUNDLE_OVERRIDES env var, and exposes them to the specs
+func (CI) overrideBuildPipelineBundles(overrides []bundle.Override) error {
+	if spec := os.Getenv("BUILD_PIPELINE_BUNDLE_OVERRIDES"); spec != "" {
+		parsed, err := bundle.ParseOverrides(spec)
+		if err != nil {
+			return err
+		}
+		overrides = append(overrides, parsed...)
+	}
+	if len(overrides) == 0 {
+		return nil
+	}
+	// Overrides set up for the repo under test, e.g. from an unset image env var, are validated like the parsed ones
+	if err := bundle.ValidateOverrides(overrides); err != nil {
+		return fmt.Errorf("invalid build pipeline bundle overrides: %+v", err)
+	}
+
+	klog.Infof("going to override default Tekton bundles for the purpose of testing %s PR", openshiftJobSpec.Refs.Repo)
+	if err := utils.CreateDockerConfigFile(os.Getenv("QUAY_TOKEN")); err != nil {
+		return fmt.Errorf("failed to create docker config file: %+v", err)
+	}
+	pipelines, err := bundle.ApplyOverrides(overrides, bundle.OverrideOptions{
+		Repo: utils.GetEnv("BUILD_PIPELINE_BUNDLE_REPO", constants.DefaultImagePushRepo),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to override build pipeline bundles: %+v", err)
+	}
+	bundle.SetPipelineBundleEnvs(pipelines)
+	for _, p := range pipelines {
+		klog.Infof("pipeline %s of selector %s overridden with bundle %s exported in %s env var", p.PipelineName, p.Selector, p.Bundle, bundle.PipelineBundleEnv(p.PipelineName))
+	}
 	return nil
 }
 
@@ -0,0 +1,164 @@
+package git
+
+import (
+	"time"
+
+	gh "github.com/google/go-github/v44/github"
+	"github.com/redhat-appstudio/e2e-tests/pkg/apis/github"
+)
+
+// GitHubProvider implements GitProvider on top of the GitHub client
+type GitHubProvider struct {
+	*github.Github
+}
+
+func NewGitHubProvider(client *github.Github) *GitHubProvider {
+	return &GitHubProvider{client}
+}
+
+func (p *GitHubProvider) Type() ProviderType {
+	return GitHubProviderType
+}
+
+func (p *GitHubProvider) Organization() string {
+	return p.GetOrganization()
+}
+
+func (p *GitHubProvider) GetAllRepositories() ([]*Repository, error) {
+	repos, err := p.Github.GetAllRepositories()
+	if err != nil {
+		return nil, err
+	}
+	var result []*Repository
+	for _, r := range repos {
+		result = append(result, fromGitHubRepository(r))
+	}
+	return result, nil
+}
+
+func (p *GitHubProvider) DeleteRepository(repository *Repository) error {
+	return p.Github.DeleteRepository(&gh.Repository{Name: gh.String(repository.Name)})
+}
+
+func (p *GitHubProvider) CreateFile(repository, pathToFile, fileContent, branchName string) (*RepositoryFile, error) {
+	resp, err := p.Github.CreateFile(repository, pathToFile, fileContent, branchName)
+	if err != nil {
+		return nil, err
+	}
+	return fromGitHubContentResponse(resp, fileContent), nil
+}
+
+func (p *GitHubProvider) GetFile(repository, pathToFile, branchName string) (*RepositoryFile, error) {
+	file, err := p.Github.GetFile(repository, pathToFile, branchName)
+	if err != nil {
+		return nil, err
+	}
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, err
+	}
+	return &RepositoryFile{
+		Path:    file.GetPath(),
+		SHA:     file.GetSHA(),
+		Content: content,
+	}, nil
+}
+
+func (p *GitHubProvider) UpdateFile(repository, pathToFile, newContent, branchName, fileSHA string) (*RepositoryFile, error) {
+	resp, err := p.Github.UpdateFile(repository, pathToFile, newContent, branchName, fileSHA)
+	if err != nil {
+		return nil, err
+	}
+	return fromGitHubContentResponse(resp, newContent), nil
+}
+
+func (p *GitHubProvider) ListPullRequests(repository string) ([]*PullRequest, error) {
+	prs, err := p.Github.ListPullRequests(repository)
+	if err != nil {
+		return nil, err
+	}
+	var result []*PullRequest
+	for _, pr := range prs {
+		result = append(result, &PullRequest{
+			Number:         pr.GetNumber(),
+			Title:          pr.GetTitle(),
+			SourceBranch:   pr.GetHead().GetRef(),
+			TargetBranch:   pr.GetBase().GetRef(),
+			State:          pr.GetState(),
+			URL:            pr.GetHTMLURL(),
+			HeadSHA:        pr.GetHead().GetSHA(),
+			MergeCommitSHA: pr.GetMergeCommitSHA(),
+			CreatedAt:      pr.GetCreatedAt(),
+		})
+	}
+	return result, nil
+}
+
+func (p *GitHubProvider) MergePullRequest(repository string, number int) (*PullRequest, error) {
+	mergeResult, err := p.Github.MergePullRequest(repository, number)
+	if err != nil {
+		return nil, err
+	}
+	return &PullRequest{
+		Number:         number,
+		State:          "merged",
+		MergeCommitSHA: mergeResult.GetSHA(),
+	}, nil
+}
+
+func (p *GitHubProvider) ListPullRequestCommentsSince(repository string, number int, since time.Time) ([]*Comment, error) {
+	comments, err := p.Github.ListPullRequestCommentsSince(repository, number, since)
+	if err != nil {
+		return nil, err
+	}
+	var result []*Comment
+	for _, c := range comments {
+		result = append(result, &Comment{
+			ID:        c.GetID(),
+			Body:      c.GetBody(),
+			Author:    c.GetUser().GetLogin(),
+			CreatedAt: c.GetCreatedAt(),
+		})
+	}
+	return result, nil
+}
+
+func (p *GitHubProvider) ListRepoWebhooks(repository string) ([]*Webhook, error) {
+	hooks, err := p.Github.ListRepoWebhooks(repository)
+	if err != nil {
+		return nil, err
+	}
+	var result []*Webhook
+	for _, h := range hooks {
+		url, _ := h.Config["url"].(string)
+		result = append(result, &Webhook{
+			ID:        h.GetID(),
+			URL:       url,
+			Events:    h.Events,
+			CreatedAt: h.GetCreatedAt(),
+		})
+	}
+	return result, nil
+}
+
+func fromGitHubRepository(r *gh.Repository) *Repository {
+	return &Repository{
+		ID:            r.GetID(),
+		Name:          r.GetName(),
+		FullName:      r.GetFullName(),
+		URL:           r.GetHTMLURL(),
+		DefaultBranch: r.GetDefaultBranch(),
+		Description:   r.GetDescription(),
+		Topics:        r.Topics,
+		CreatedAt:     r.GetCreatedAt().Time,
+	}
+}
+
+func fromGitHubContentResponse(resp *gh.RepositoryContentResponse, content string) *RepositoryFile {
+	return &RepositoryFile{
+		Path:      resp.GetContent().GetPath(),
+		SHA:       resp.GetContent().GetSHA(),
+		Content:   content,
+		CommitSHA: resp.Commit.GetSHA(),
+	}
+}
@@ -0,0 +1,211 @@
+package git
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	gl "github.com/xanzy/go-gitlab"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/apis/gitlab"
+)
+
+// GitLabProvider implements GitProvider on top of the GitLab client
+type GitLabProvider struct {
+	*gitlab.Gitlab
+}
+
+func NewGitLabProvider(client *gitlab.Gitlab) *GitLabProvider {
+	return &GitLabProvider{client}
+}
+
+func (p *GitLabProvider) Type() ProviderType {
+	return GitLabProviderType
+}
+
+func (p *GitLabProvider) Organization() string {
+	return p.GetGroup()
+}
+
+func (p *GitLabProvider) GetAllRepositories() ([]*Repository, error) {
+	projects, err := p.Gitlab.GetAllRepositories()
+	if err != nil {
+		return nil, err
+	}
+	var result []*Repository
+	for _, project := range projects {
+		result = append(result, fromGitLabProject(project))
+	}
+	return result, nil
+}
+
+func (p *GitLabProvider) DeleteRepository(repository *Repository) error {
+	fullName := repository.FullName
+	if fullName == "" {
+		fullName = fmt.Sprintf("%s/%s", p.GetGroup(), repository.Name)
+	}
+	return p.Gitlab.DeleteRepository(&gl.Project{ID: int(repository.ID), PathWithNamespace: fullName})
+}
+
+func (p *GitLabProvider) CreateFile(repository, pathToFile, fileContent, branchName string) (*RepositoryFile, error) {
+	if _, err := p.Gitlab.CreateFile(repository, pathToFile, fileContent, branchName); err != nil {
+		return nil, err
+	}
+	return p.fileAfterCommit(repository, pathToFile, branchName, fileContent)
+}
+
+func (p *GitLabProvider) GetFile(repository, pathToFile, branchName string) (*RepositoryFile, error) {
+	file, err := p.Gitlab.GetFile(repository, pathToFile, branchName)
+	if err != nil {
+		return nil, err
+	}
+	content := file.Content
+	if file.Encoding == "base64" {
+		decoded, err := base64.StdEncoding.DecodeString(file.Content)
+		if err != nil {
+			return nil, fmt.Errorf("error when decoding content of file %s: %v", pathToFile, err)
+		}
+		content = string(decoded)
+	}
+	return &RepositoryFile{
+		Path:      file.FilePath,
+		SHA:       file.LastCommitID,
+		Content:   content,
+		CommitSHA: file.CommitID,
+	}, nil
+}
+
+// UpdateFile updates a file on GitLab. The fileSHA is the last commit ID of the file (see GetFile),
+// which GitLab uses for optimistic locking
+func (p *GitLabProvider) UpdateFile(repository, pathToFile, newContent, branchName, fileSHA string) (*RepositoryFile, error) {
+	if _, err := p.Gitlab.UpdateFile(repository, pathToFile, newContent, branchName, fileSHA); err != nil {
+		return nil, err
+	}
+	return p.fileAfterCommit(repository, pathToFile, branchName, newContent)
+}
+
+// fileAfterCommit reads back the file metadata, since GitLab doesn't return the commit in create/update responses
+func (p *GitLabProvider) fileAfterCommit(repository, pathToFile, branchName, content string) (*RepositoryFile, error) {
+	file, err := p.Gitlab.GetFile(repository, pathToFile, branchName)
+	if err != nil {
+		return nil, err
+	}
+	return &RepositoryFile{
+		Path:      file.FilePath,
+		SHA:       file.LastCommitID,
+		Content:   content,
+		CommitSHA: file.LastCommitID,
+	}, nil
+}
+
+func (p *GitLabProvider) ListPullRequests(repository string) ([]*PullRequest, error) {
+	mrs, err := p.Gitlab.ListMergeRequests(repository)
+	if err != nil {
+		return nil, err
+	}
+	var result []*PullRequest
+	for _, mr := range mrs {
+		result = append(result, fromGitLabMergeRequest(mr))
+	}
+	return result, nil
+}
+
+func (p *GitLabProvider) MergePullRequest(repository string, number int) (*PullRequest, error) {
+	mr, err := p.Gitlab.AcceptMergeRequest(repository, number)
+	if err != nil {
+		return nil, err
+	}
+	return fromGitLabMergeRequest(mr), nil
+}
+
+func (p *GitLabProvider) ListPullRequestCommentsSince(repository string, number int, since time.Time) ([]*Comment, error) {
+	notes, err := p.Gitlab.ListMergeRequestNotesSince(repository, number, since)
+	if err != nil {
+		return nil, err
+	}
+	var result []*Comment
+	for _, n := range notes {
+		result = append(result, &Comment{
+			ID:        int64(n.ID),
+			Body:      n.Body,
+			Author:    n.Author.Username,
+			CreatedAt: timeOrZero(n.CreatedAt),
+		})
+	}
+	return result, nil
+}
+
+func (p *GitLabProvider) ListRepoWebhooks(repository string) ([]*Webhook, error) {
+	hooks, err := p.Gitlab.ListRepoWebhooks(repository)
+	if err != nil {
+		return nil, err
+	}
+	var result []*Webhook
+	for _, h := range hooks {
+		var events []string
+		if h.PushEvents {
+			events = append(events, "push")
+		}
+		if h.MergeRequestsEvents {
+			events = append(events, "merge_request")
+		}
+		if h.NoteEvents {
+			events = append(events, "note")
+		}
+		result = append(result, &Webhook{
+			ID:        int64(h.ID),
+			URL:       h.URL,
+			Events:    events,
+			CreatedAt: timeOrZero(h.CreatedAt),
+		})
+	}
+	return result, nil
+}
+
+func (p *GitLabProvider) CreateWebhook(repository, url string) (int64, error) {
+	id, err := p.Gitlab.CreateWebhook(repository, url)
+	return int64(id), err
+}
+
+func (p *GitLabProvider) DeleteWebhook(repository string, ID int64) error {
+	return p.Gitlab.DeleteWebhook(repository, int(ID))
+}
+
+// DescribeWebhookDeliveries doesn't report anything on GitLab, since its REST API doesn't expose webhook deliveries
+func (p *GitLabProvider) DescribeWebhookDeliveries(repository, hookURL string, since time.Time) string {
+	return fmt.Sprintf("webhook delivery reports are available only on GitHub, can't check deliveries of the webhook %q in the GitLab project %s/%s", hookURL, p.GetGroup(), repository)
+}
+
+func fromGitLabProject(project *gl.Project) *Repository {
+	return &Repository{
+		ID:            int64(project.ID),
+		Name:          project.Path,
+		FullName:      project.PathWithNamespace,
+		URL:           project.WebURL,
+		DefaultBranch: project.DefaultBranch,
+		Description:   project.Description,
+		Topics:        project.Topics,
+		CreatedAt:     timeOrZero(project.CreatedAt),
+	}
+}
+
+func fromGitLabMergeRequest(mr *gl.MergeRequest) *PullRequest {
+	return &PullRequest{
+		Number:         mr.IID,
+		Title:          mr.Title,
+		SourceBranch:   mr.SourceBranch,
+		TargetBranch:   mr.TargetBranch,
+		State:          mr.State,
+		URL:            mr.WebURL,
+		HeadSHA:        mr.SHA,
+		MergeCommitSHA: mr.MergeCommitSHA,
+		CreatedAt:      timeOrZero(mr.CreatedAt),
+	}
+}
+
+func timeOrZero(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
@@ -0,0 +1,162 @@
+package git
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/apis/github"
+	"github.com/redhat-appstudio/e2e-tests/pkg/apis/gitlab"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestGitLabProvider serves the given responses, keyed by method and unescaped path, from a fake GitLab API
+func newTestGitLabProvider(t *testing.T, responses map[string]string) (*GitLabProvider, *[]string) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, fmt.Sprintf("%s %s", key, body))
+		response, ok := responses[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "404 Not Found"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := gitlab.NewGitlabClient("token", server.URL+"/api/v4", "group")
+	assert.NoError(t, err)
+	return NewGitLabProvider(client), &requests
+}
+
+func TestGitLabProviderFiles(t *testing.T) {
+	p, requests := newTestGitLabProvider(t, map[string]string{
+		"POST /api/v4/projects/group/repo/repository/files/.tekton/readme.md": `{"file_path": ".tekton/readme.md", "branch": "main"}`,
+		"GET /api/v4/projects/group/repo/repository/files/.tekton/readme.md": `{"file_path": ".tekton/readme.md", "encoding": "base64",
+			"content": "aGVsbG8=", "commit_id": "commit", "last_commit_id": "last-commit"}`,
+	})
+
+	file, err := p.GetFile("repo", ".tekton/readme.md", "main")
+	assert.NoError(t, err)
+	assert.Equal(t, &RepositoryFile{Path: ".tekton/readme.md", SHA: "last-commit", Content: "hello", CommitSHA: "commit"}, file)
+
+	file, err = p.CreateFile("repo", ".tekton/readme.md", "hello", "main")
+	assert.NoError(t, err)
+	assert.Equal(t, "last-commit", file.CommitSHA)
+	assert.Contains(t, (*requests)[1], `"branch":"main"`)
+
+	_, err = p.GetFile("repo", "missing.md", "main")
+	assert.Error(t, err)
+}
+
+func TestGitLabProviderMergeRequests(t *testing.T) {
+	p, _ := newTestGitLabProvider(t, map[string]string{
+		"GET /api/v4/projects/group/repo/merge_requests": `[{"iid": 3, "title": "PaC", "source_branch": "appstudio-comp", "target_branch": "main",
+			"state": "opened", "sha": "head", "created_at": "2023-01-02T10:00:00Z"}]`,
+		"PUT /api/v4/projects/group/repo/merge_requests/3/merge": `{"iid": 3, "state": "merged", "merge_commit_sha": "merged"}`,
+		"GET /api/v4/projects/group/repo/merge_requests/3/notes": `[
+			{"id": 1, "body": "old", "author": {"username": "bot"}, "created_at": "2023-01-02T10:00:00Z"},
+			{"id": 2, "body": "success", "author": {"username": "bot"}, "created_at": "2023-01-02T12:00:00Z"}]`,
+	})
+
+	prs, err := p.ListPullRequests("repo")
+	assert.NoError(t, err)
+	assert.Len(t, prs, 1)
+	assert.Equal(t, 3, prs[0].Number)
+	assert.Equal(t, "appstudio-comp", prs[0].SourceBranch)
+	assert.Equal(t, "main", prs[0].TargetBranch)
+	assert.Equal(t, time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC), prs[0].CreatedAt.UTC())
+
+	merged, err := p.MergePullRequest("repo", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "merged", merged.MergeCommitSHA)
+
+	comments, err := p.ListPullRequestCommentsSince("repo", 3, time.Date(2023, 1, 2, 11, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, &Comment{ID: 2, Body: "success", Author: "bot", CreatedAt: comments[0].CreatedAt}, comments[0])
+}
+
+func TestGitLabProviderRefsAndWebhooks(t *testing.T) {
+	p, requests := newTestGitLabProvider(t, map[string]string{
+		"GET /api/v4/projects/group/repo/repository/branches/main": `{"name": "main"}`,
+		"GET /api/v4/projects/group/repo/hooks":                    `[{"id": 7, "url": "https://pac.example.com", "push_events": true, "merge_requests_events": true}]`,
+		"DELETE /api/v4/projects/group/repo/hooks/7":               ``,
+		"GET /api/v4/projects/group/repo":                          `{"id": 1, "path": "repo"}`,
+	})
+
+	exists, err := p.ExistsRef("repo", "main")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = p.ExistsRef("repo", "missing")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	hooks, err := p.ListRepoWebhooks("repo")
+	assert.NoError(t, err)
+	assert.Equal(t, []*Webhook{{ID: 7, URL: "https://pac.example.com", Events: []string{"push", "merge_request"}}}, hooks)
+	assert.NoError(t, p.DeleteWebhook("repo", 7))
+	assert.Contains(t, *requests, "DELETE /api/v4/projects/group/repo/hooks/7 ")
+
+	assert.True(t, p.CheckIfRepositoryExist("repo"))
+	assert.False(t, p.CheckIfRepositoryExist("missing"))
+	assert.Equal(t, "group", p.Organization())
+}
+
+func TestGitLabProviderRepositoryURL(t *testing.T) {
+	client, err := gitlab.NewGitlabClient("token", "https://gitlab.example.com/api/v4", "group/subgroup")
+	assert.NoError(t, err)
+	p := NewGitLabProvider(client)
+
+	assert.Equal(t, "https://gitlab.example.com/group/subgroup/repo", p.RepositoryURL("repo"))
+	assert.Contains(t, p.DescribeWebhookDeliveries("repo", "pac.example.com", time.Now()), "available only on GitHub")
+
+	gh, err := github.NewGithubClient("token", "org")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://github.com/org/repo", NewGitHubProvider(gh).RepositoryURL("repo"))
+}
+
+func TestGitLabProviderListsAllPages(t *testing.T) {
+	// Serves two pages of every list, the page number is used as the ID
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		if page == "" {
+			page = "1"
+			w.Header().Set("X-Next-Page", "2")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v4/projects/group/repo/merge_requests":
+			_, _ = fmt.Fprintf(w, `[{"iid": %s}]`, page)
+		case "/api/v4/projects/group/repo/merge_requests/3/notes":
+			_, _ = fmt.Fprintf(w, `[{"id": %s, "created_at": "2023-01-02T12:00:00Z"}]`, page)
+		case "/api/v4/projects/group/repo/hooks":
+			_, _ = fmt.Fprintf(w, `[{"id": %s}]`, page)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	client, err := gitlab.NewGitlabClient("token", server.URL+"/api/v4", "group")
+	assert.NoError(t, err)
+	p := NewGitLabProvider(client)
+
+	prs, err := p.ListPullRequests("repo")
+	assert.NoError(t, err)
+	assert.Len(t, prs, 2)
+
+	comments, err := p.ListPullRequestCommentsSince("repo", 3, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Len(t, comments, 2)
+
+	hooks, err := p.ListRepoWebhooks("repo")
+	assert.NoError(t, err)
+	assert.Len(t, hooks, 2)
+}
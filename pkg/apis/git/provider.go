@@ -0,0 +1,147 @@
+package git
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/apis/github"
+	"github.com/redhat-appstudio/e2e-tests/pkg/apis/gitlab"
+	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
+)
+
+type ProviderType string
+
+const (
+	GitHubProviderType ProviderType = "github"
+	GitLabProviderType ProviderType = "gitlab"
+)
+
+// GitProvider abstracts the git hosting service (GitHub, GitLab) used by the e2e tests.
+// All repositories are addressed by name and are expected to live in the organization (GitHub)
+// or group (GitLab) the provider was created for.
+type GitProvider interface {
+	// Type returns which git hosting service is behind the provider
+	Type() ProviderType
+	// Organization returns the GitHub organization or GitLab group the provider works with
+	Organization() string
+	// RepositoryURL returns the web URL of a repository, as used in the git source of components
+	RepositoryURL(repository string) string
+
+	CheckIfRepositoryExist(repository string) bool
+	GetAllRepositories() ([]*Repository, error)
+	DeleteRepository(repository *Repository) error
+
+	CreateFile(repository, pathToFile, fileContent, branchName string) (*RepositoryFile, error)
+	GetFile(repository, pathToFile, branchName string) (*RepositoryFile, error)
+	// UpdateFile updates a file on a branch. fileSHA is the SHA returned by GetFile
+	UpdateFile(repository, pathToFile, newContent, branchName, fileSHA string) (*RepositoryFile, error)
+	DeleteFile(repository, pathToFile, branchName string) error
+
+	CreateRef(repository, baseBranchName, newBranchName string) error
+	DeleteRef(repository, branchName string) error
+	ExistsRef(repository, branchName string) (bool, error)
+
+	ListPullRequests(repository string) ([]*PullRequest, error)
+	MergePullRequest(repository string, number int) (*PullRequest, error)
+	ListPullRequestCommentsSince(repository string, number int, since time.Time) ([]*Comment, error)
+
+	ListRepoWebhooks(repository string) ([]*Webhook, error)
+	CreateWebhook(repository, url string) (int64, error)
+	DeleteWebhook(repository string, ID int64) error
+	// DescribeWebhookDeliveries returns a human readable report of what happened with the events sent since
+	// a specified time by the webhook matching hookURL, meant to be used in failure messages of specs
+	DescribeWebhookDeliveries(repository, hookURL string, since time.Time) string
+}
+
+// Repository is a GitHub repository or a GitLab project
+type Repository struct {
+	ID            int64
+	Name          string
+	FullName      string
+	URL           string
+	DefaultBranch string
+	Description   string
+	Topics        []string
+	CreatedAt     time.Time
+}
+
+// RepositoryFile is a file stored in a repository. Content is decoded and may be empty
+// when the file was not read (e.g. right after creation).
+type RepositoryFile struct {
+	Path    string
+	SHA     string
+	Content string
+	// Commit SHA of the commit that created or updated the file, if known
+	CommitSHA string
+}
+
+// PullRequest is a GitHub pull request or a GitLab merge request.
+// Number holds the PR number on GitHub and the MR IID on GitLab.
+type PullRequest struct {
+	Number         int
+	Title          string
+	SourceBranch   string
+	TargetBranch   string
+	State          string
+	URL            string
+	HeadSHA        string
+	MergeCommitSHA string
+	CreatedAt      time.Time
+}
+
+// Comment is a GitHub issue comment or a GitLab merge request note
+type Comment struct {
+	ID        int64
+	Body      string
+	Author    string
+	CreatedAt time.Time
+}
+
+type Webhook struct {
+	ID        int64
+	URL       string
+	Events    []string
+	CreatedAt time.Time
+}
+
+// NewGitProvider returns a GitProvider of the requested type. For GitLab, apiURL is the REST API v4 endpoint,
+// for GitHub it is ignored.
+func NewGitProvider(providerType ProviderType, token, apiURL, organization string) (GitProvider, error) {
+	switch providerType {
+	case GitHubProviderType:
+		gh, err := github.NewGithubClient(token, organization)
+		if err != nil {
+			return nil, err
+		}
+		return NewGitHubProvider(gh), nil
+	case GitLabProviderType:
+		gl, err := gitlab.NewGitlabClient(token, apiURL, organization)
+		if err != nil {
+			return nil, err
+		}
+		return NewGitLabProvider(gl), nil
+	}
+	return nil, fmt.Errorf("unsupported git provider type %q", providerType)
+}
+
+// NewGitProviderFromEnv returns a GitProvider based on GIT_PROVIDER env var (github by default).
+// GitHub is configured by GITHUB_TOKEN and MY_GITHUB_ORG, GitLab by GITLAB_TOKEN, GITLAB_API_URL and MY_GITLAB_GROUP.
+func NewGitProviderFromEnv() (GitProvider, error) {
+	providerType := ProviderType(utils.GetEnv(constants.GIT_PROVIDER_ENV, string(GitHubProviderType)))
+	if providerType == GitLabProviderType {
+		return NewGitProvider(providerType,
+			utils.GetEnv(constants.GITLAB_TOKEN_ENV, ""),
+			utils.GetEnv(constants.GITLAB_API_URL_ENV, "https://gitlab.com/api/v4"),
+			utils.GetEnv(constants.GITLAB_E2E_GROUP_ENV, "redhat-appstudio-qe"))
+	}
+	return NewGitProvider(providerType,
+		utils.GetEnv(constants.GITHUB_TOKEN_ENV, ""),
+		"",
+		utils.GetEnv(constants.GITHUB_E2E_ORGANIZATION_ENV, "redhat-appstudio-qe"))
+}
+
+var (
+	_ GitProvider = &GitHubProvider{}
+	_ GitProvider = &GitLabProvider{}
+)
@@ -2,6 +2,7 @@ package github
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/gofri/go-github-ratelimit/github_ratelimit"
@@ -30,3 +31,13 @@ func NewGithubClient(token, organization string) (*Github, error) {
 
 	return githubClient, nil
 }
+
+// GetOrganization returns the GitHub organization the client works with
+func (g *Github) GetOrganization() string {
+	return g.organization
+}
+
+// RepositoryURL returns the web URL of a repository in the organization
+func (g *Github) RepositoryURL(repository string) string {
+	return fmt.Sprintf("https://github.com/%s/%s", g.organization, repository)
+}
@@ -0,0 +1,262 @@
+package github
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devfile/library/pkg/util"
+	"github.com/google/go-github/v44/github"
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
+)
+
+const (
+	// Topic marking a repository as created by the e2e tests and safe to delete once expired
+	EphemeralRepositoryTopic = "e2e-ephemeral"
+
+	ephemeralRunTopicPrefix     = "e2e-run-"
+	ephemeralExpiresTopicPrefix = "e2e-expires-"
+	ephemeralDescriptionPrefix  = "[e2e-ephemeral]"
+
+	DefaultEphemeralRepositoryTTL = 24 * time.Hour
+
+	// GitHub limits for topic and description length
+	maxTopicLength       = 50
+	maxDescriptionLength = 350
+)
+
+var (
+	invalidTopicCharsRegexp = regexp.MustCompile("[^a-z0-9-]+")
+	generatedRunID          = fmt.Sprintf("%d-%s", time.Now().Unix(), strings.ToLower(util.GenerateRandomString(4)))
+)
+
+// EphemeralRepositoryMetadata is stored in topics and description of a repository created for a spec
+type EphemeralRepositoryMetadata struct {
+	RunID     string
+	Spec      string
+	ExpiresAt time.Time
+}
+
+type EphemeralRepositoryOptions struct {
+	// Owner and name of the repository the ephemeral repository is created from
+	SourceOwner      string
+	SourceRepository string
+	// By default the source is a template repository and the new repository is generated from it.
+	// If true, the source is forked instead. GitHub keeps a single fork of a repository per organization,
+	// so forks can't be used by specs running in parallel
+	Fork bool
+	// Name of the new repository. Defaults to the source repository name with a random suffix
+	Name string
+	// Time after which the repository is considered expired by the cleanup. Defaults to DefaultEphemeralRepositoryTTL
+	TTL time.Duration
+	// Description of the spec using the repository. Defaults to the full text of the current Ginkgo spec
+	Spec string
+	// Defaults to the E2E_RUN_ID or BUILD_ID env var, or an ID generated for the current process
+	RunID string
+	// Keep the repository when the spec fails, so it can be inspected. It is still deleted by the cleanup once expired
+	KeepOnFailure bool
+}
+
+// EphemeralRepository is a handle to a repository created for a single spec
+type EphemeralRepository struct {
+	*github.Repository
+	Metadata EphemeralRepositoryMetadata
+
+	client *Github
+}
+
+// GetE2ERunID returns the ID of the current test run used to tag ephemeral repositories
+func GetE2ERunID() string {
+	if id := os.Getenv(constants.E2E_RUN_ID_ENV); id != "" {
+		return id
+	}
+	if id := os.Getenv("BUILD_ID"); id != "" {
+		return id
+	}
+	return generatedRunID
+}
+
+// Topics returns the repository topics encoding the metadata
+func (m EphemeralRepositoryMetadata) Topics() []string {
+	return []string{
+		EphemeralRepositoryTopic,
+		toTopic(ephemeralRunTopicPrefix + m.RunID),
+		toTopic(ephemeralExpiresTopicPrefix + strconv.FormatInt(m.ExpiresAt.Unix(), 10)),
+	}
+}
+
+// Description returns the human readable repository description encoding the metadata
+func (m EphemeralRepositoryMetadata) Description() string {
+	d := fmt.Sprintf("%s run: %s; expires: %s; spec: %s", ephemeralDescriptionPrefix, m.RunID, m.ExpiresAt.UTC().Format(time.RFC3339), m.Spec)
+	// The limit is in characters, the spec text may contain multi-byte ones
+	if r := []rune(d); len(r) > maxDescriptionLength {
+		d = string(r[:maxDescriptionLength])
+	}
+	return d
+}
+
+// IsExpired returns true if the expiry time of the repository has passed
+func (m EphemeralRepositoryMetadata) IsExpired(now time.Time) bool {
+	return now.After(m.ExpiresAt)
+}
+
+// ParseEphemeralRepositoryMetadata reads the metadata from topics and description of a repository.
+// The second return value is false if the repository is not an ephemeral e2e repository.
+func ParseEphemeralRepositoryMetadata(repo *github.Repository) (*EphemeralRepositoryMetadata, bool) {
+	isEphemeral := false
+	m := &EphemeralRepositoryMetadata{}
+	for _, t := range repo.Topics {
+		switch {
+		case t == EphemeralRepositoryTopic:
+			isEphemeral = true
+		case strings.HasPrefix(t, ephemeralRunTopicPrefix):
+			m.RunID = strings.TrimPrefix(t, ephemeralRunTopicPrefix)
+		case strings.HasPrefix(t, ephemeralExpiresTopicPrefix):
+			if ts, err := strconv.ParseInt(strings.TrimPrefix(t, ephemeralExpiresTopicPrefix), 10, 64); err == nil {
+				m.ExpiresAt = time.Unix(ts, 0)
+			}
+		}
+	}
+	if !isEphemeral {
+		return nil, false
+	}
+
+	// The description holds the original (not sanitized) run ID and the spec text
+	description := strings.TrimPrefix(repo.GetDescription(), ephemeralDescriptionPrefix+" ")
+	for _, field := range strings.SplitN(description, "; ", 3) {
+		key, value, found := strings.Cut(field, ": ")
+		if !found {
+			continue
+		}
+		switch key {
+		case "run":
+			m.RunID = value
+		case "expires":
+			if m.ExpiresAt.IsZero() {
+				if t, err := time.Parse(time.RFC3339, value); err == nil {
+					m.ExpiresAt = t
+				}
+			}
+		case "spec":
+			m.Spec = value
+		}
+	}
+	return m, true
+}
+
+// CreateEphemeralRepository generates (or forks) a repository from a source repository into the organization and tags it
+// with topics and description encoding the run ID, spec and expiry. The repository is deleted if it can't be tagged
+func (g *Github) CreateEphemeralRepository(opts EphemeralRepositoryOptions) (*EphemeralRepository, error) {
+	if opts.SourceOwner == "" || opts.SourceRepository == "" {
+		return nil, fmt.Errorf("source owner and repository need to be specified for an ephemeral repository")
+	}
+	if opts.Name == "" {
+		opts.Name = fmt.Sprintf("%s-%s", opts.SourceRepository, strings.ToLower(util.GenerateRandomString(6)))
+	}
+	if opts.TTL == 0 {
+		opts.TTL = DefaultEphemeralRepositoryTTL
+	}
+	if opts.RunID == "" {
+		opts.RunID = GetE2ERunID()
+	}
+	metadata := EphemeralRepositoryMetadata{
+		RunID:     opts.RunID,
+		Spec:      opts.Spec,
+		ExpiresAt: time.Now().Add(opts.TTL).Truncate(time.Second),
+	}
+
+	var repo *github.Repository
+	var err error
+	if opts.Fork {
+		repo, err = g.ForkRepository(opts.SourceOwner, opts.SourceRepository, opts.Name)
+		if err != nil {
+			return nil, err
+		}
+		err = g.UpdateRepositoryDescription(repo.GetName(), metadata.Description())
+	} else {
+		repo, err = g.CreateRepositoryFromTemplate(opts.SourceOwner, opts.SourceRepository, opts.Name, metadata.Description())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err == nil {
+		err = g.SetRepositoryTopics(repo.GetName(), metadata.Topics())
+	}
+	if err != nil {
+		// Without the topics the cleanup wouldn't recognize the repository, so don't leave it behind
+		if deleteErr := g.DeleteRepository(repo); deleteErr != nil {
+			return nil, fmt.Errorf("%v (deleting the untagged repository %s failed: %v)", err, repo.GetFullName(), deleteErr)
+		}
+		return nil, err
+	}
+
+	GinkgoWriter.Printf("created ephemeral repository %s (run: %s, expires: %s)\n", repo.GetFullName(), metadata.RunID, metadata.ExpiresAt)
+	return &EphemeralRepository{Repository: repo, Metadata: metadata, client: g}, nil
+}
+
+// NewEphemeralRepositoryForSpec creates an ephemeral repository for the current Ginkgo spec and registers its deletion
+// with DeferCleanup, so it has to be called from a setup node (e.g. BeforeAll) or from a spec
+func (g *Github) NewEphemeralRepositoryForSpec(opts EphemeralRepositoryOptions) (*EphemeralRepository, error) {
+	if opts.Spec == "" {
+		opts.Spec = CurrentSpecReport().FullText()
+	}
+	repo, err := g.CreateEphemeralRepository(opts)
+	if err != nil {
+		return nil, err
+	}
+	DeferCleanup(func() error {
+		if opts.KeepOnFailure && CurrentSpecReport().Failed() {
+			GinkgoWriter.Printf("keeping ephemeral repository %s of a failed spec until %s\n", repo.GetFullName(), repo.Metadata.ExpiresAt)
+			return nil
+		}
+		return repo.Delete()
+	})
+	return repo, nil
+}
+
+// Delete removes the ephemeral repository from the organization
+func (r *EphemeralRepository) Delete() error {
+	return r.client.DeleteRepository(r.Repository)
+}
+
+// ListEphemeralRepositories returns all repositories of the organization tagged as ephemeral together with their metadata
+func (g *Github) ListEphemeralRepositories() (map[*github.Repository]*EphemeralRepositoryMetadata, error) {
+	repos, err := g.GetAllRepositories()
+	if err != nil {
+		return nil, err
+	}
+	ephemeral := map[*github.Repository]*EphemeralRepositoryMetadata{}
+	for _, repo := range repos {
+		if m, ok := ParseEphemeralRepositoryMetadata(repo); ok {
+			ephemeral[repo] = m
+		}
+	}
+	return ephemeral, nil
+}
+
+// ListExpiredEphemeralRepositories returns ephemeral repositories of the organization which expired before the given time
+func (g *Github) ListExpiredEphemeralRepositories(now time.Time) ([]*github.Repository, error) {
+	ephemeral, err := g.ListEphemeralRepositories()
+	if err != nil {
+		return nil, err
+	}
+	var expired []*github.Repository
+	for repo, m := range ephemeral {
+		if m.IsExpired(now) {
+			expired = append(expired, repo)
+		}
+	}
+	return expired, nil
+}
+
+func toTopic(s string) string {
+	t := strings.Trim(invalidTopicCharsRegexp.ReplaceAllString(strings.ToLower(s), "-"), "-")
+	if len(t) > maxTopicLength {
+		t = strings.TrimRight(t[:maxTopicLength], "-")
+	}
+	return t
+}
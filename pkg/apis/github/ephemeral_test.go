@@ -0,0 +1,85 @@
+package github
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v44/github"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEphemeralRepositoryMetadataRoundTrip(t *testing.T) {
+	m := EphemeralRepositoryMetadata{
+		RunID:     "PR_123/Build#7",
+		Spec:      "Build service E2E tests test PaC component build; triggers a PipelineRun",
+		ExpiresAt: time.Unix(1700000000, 0),
+	}
+
+	assert.Equal(t, []string{"e2e-ephemeral", "e2e-run-pr-123-build-7", "e2e-expires-1700000000"}, m.Topics())
+
+	parsed, ok := ParseEphemeralRepositoryMetadata(&github.Repository{
+		Topics:      m.Topics(),
+		Description: github.String(m.Description()),
+	})
+	assert.True(t, ok)
+	assert.Equal(t, m.RunID, parsed.RunID)
+	assert.Equal(t, m.Spec, parsed.Spec)
+	assert.True(t, m.ExpiresAt.Equal(parsed.ExpiresAt))
+}
+
+func TestParseEphemeralRepositoryMetadataIgnoresOtherRepositories(t *testing.T) {
+	_, ok := ParseEphemeralRepositoryMetadata(&github.Repository{
+		Topics:      []string{"e2e-run-123"},
+		Description: github.String("devfile sample"),
+	})
+	assert.False(t, ok)
+}
+
+func TestEphemeralRepositoryMetadataIsExpired(t *testing.T) {
+	m := EphemeralRepositoryMetadata{ExpiresAt: time.Unix(1700000000, 0)}
+
+	assert.False(t, m.IsExpired(time.Unix(1600000000, 0)))
+	assert.True(t, m.IsExpired(time.Unix(1800000000, 0)))
+}
+
+func TestEphemeralRepositoryMetadataDescriptionIsTruncatedByCharacters(t *testing.T) {
+	m := EphemeralRepositoryMetadata{RunID: "1", Spec: strings.Repeat("ž", 400), ExpiresAt: time.Unix(1700000000, 0)}
+
+	d := m.Description()
+	assert.Len(t, []rune(d), maxDescriptionLength)
+	assert.True(t, strings.HasSuffix(d, "ž"))
+}
+
+// newTestGithub returns a client for the organization "org" sending its requests to the handler
+func newTestGithub(t *testing.T, handler http.HandlerFunc) *Github {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+	return &Github{client: client, organization: "org"}
+}
+
+func TestCreateEphemeralRepositoryDeletesUntaggedRepository(t *testing.T) {
+	var requests []string
+	g := newTestGithub(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method + " " + r.URL.Path {
+		case "POST /repos/templates/sample/generate":
+			_, _ = w.Write([]byte(`{"name": "sample-abc", "full_name": "org/sample-abc"}`))
+		case "PUT /repos/org/sample-abc/topics":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		case "DELETE /repos/org/sample-abc":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	_, err := g.CreateEphemeralRepository(EphemeralRepositoryOptions{SourceOwner: "templates", SourceRepository: "sample", Name: "sample-abc"})
+	assert.ErrorContains(t, err, "error when setting topics")
+	assert.Equal(t, []string{"POST /repos/templates/sample/generate", "PUT /repos/org/sample-abc/topics", "DELETE /repos/org/sample-abc"}, requests)
+}
@@ -43,3 +43,70 @@ func (g *Github) ExistsRef(repository, branchName string) (bool, error) {
 	}
 	return true, nil
 }
+
+// CommitFile is a change of a single file within a multi-file commit created by CreateCommit
+type CommitFile struct {
+	// Path of the file relative to the repository root
+	Path string
+	// New content of the file. Ignored when Delete is true
+	Content string
+	// Delete removes the file from the repository
+	Delete bool
+	// Executable sets the file mode to 100755 instead of 100644
+	Executable bool
+}
+
+// CreateCommit adds, modifies and deletes files in a single commit on top of the specified branch
+// using the Git Data API, so that services watching the repository (e.g. PaC) are triggered only once.
+// If force is true, the branch ref is force-updated to the new commit even if it was moved in the meantime.
+func (g *Github) CreateCommit(repository, branchName, message string, files []CommitFile, force bool) (*github.Commit, error) {
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files to commit to the branch '%s' of the repo '%s'", branchName, repository)
+	}
+	ctx := context.Background()
+	ref, _, err := g.client.Git.GetRef(ctx, g.organization, repository, fmt.Sprintf("heads/%s", branchName))
+	if err != nil {
+		return nil, fmt.Errorf("error when getting the branch '%s' for the repo '%s': %+v", branchName, repository, err)
+	}
+	parent, _, err := g.client.Git.GetCommit(ctx, g.organization, repository, ref.GetObject().GetSHA())
+	if err != nil {
+		return nil, fmt.Errorf("error when getting the head commit of the branch '%s' for the repo '%s': %+v", branchName, repository, err)
+	}
+
+	entries := make([]*github.TreeEntry, 0, len(files))
+	for _, f := range files {
+		mode := "100644"
+		if f.Executable {
+			mode = "100755"
+		}
+		entry := &github.TreeEntry{
+			Path: github.String(f.Path),
+			Mode: github.String(mode),
+			Type: github.String("blob"),
+		}
+		// a tree entry without both content and SHA deletes the file
+		if !f.Delete {
+			entry.Content = github.String(f.Content)
+		}
+		entries = append(entries, entry)
+	}
+	tree, _, err := g.client.Git.CreateTree(ctx, g.organization, repository, parent.GetTree().GetSHA(), entries)
+	if err != nil {
+		return nil, fmt.Errorf("error when creating a git tree for the repo '%s': %+v", repository, err)
+	}
+
+	commit, _, err := g.client.Git.CreateCommit(ctx, g.organization, repository, &github.Commit{
+		Message: github.String(message),
+		Tree:    tree,
+		Parents: []*github.Commit{{SHA: parent.SHA}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error when creating a commit for the repo '%s': %+v", repository, err)
+	}
+
+	ref.Object.SHA = commit.SHA
+	if _, _, err := g.client.Git.UpdateRef(ctx, g.organization, repository, ref, force); err != nil {
+		return nil, fmt.Errorf("error when updating the branch '%s' of the repo '%s' to commit %s: %+v", branchName, repository, commit.GetSHA(), err)
+	}
+	return commit, nil
+}
@@ -0,0 +1,68 @@
+package github
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateCommit(t *testing.T) {
+	var treeRequest, commitRequest, refRequest map[string]interface{}
+	decode := func(r *http.Request, into *map[string]interface{}) {
+		body, _ := io.ReadAll(r.Body)
+		assert.NoError(t, json.Unmarshal(body, into))
+	}
+	g := newTestGithub(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /repos/org/repo/git/ref/heads/main":
+			_, _ = w.Write([]byte(`{"ref": "refs/heads/main", "object": {"sha": "parent-sha"}}`))
+		case "GET /repos/org/repo/git/commits/parent-sha":
+			_, _ = w.Write([]byte(`{"sha": "parent-sha", "tree": {"sha": "base-tree-sha"}}`))
+		case "POST /repos/org/repo/git/trees":
+			decode(r, &treeRequest)
+			_, _ = w.Write([]byte(`{"sha": "tree-sha"}`))
+		case "POST /repos/org/repo/git/commits":
+			decode(r, &commitRequest)
+			_, _ = w.Write([]byte(`{"sha": "commit-sha", "message": "update files"}`))
+		case "PATCH /repos/org/repo/git/refs/heads/main":
+			decode(r, &refRequest)
+			_, _ = w.Write([]byte(`{"ref": "refs/heads/main", "object": {"sha": "commit-sha"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	commit, err := g.CreateCommit("repo", "main", "update files", []CommitFile{
+		{Path: ".tekton/push.yaml", Content: "kind: PipelineRun"},
+		{Path: "hack/build.sh", Content: "#!/bin/sh", Executable: true},
+		{Path: "README.md", Delete: true},
+	}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "commit-sha", commit.GetSHA())
+
+	assert.Equal(t, "base-tree-sha", treeRequest["base_tree"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"path": ".tekton/push.yaml", "mode": "100644", "type": "blob", "content": "kind: PipelineRun"},
+		map[string]interface{}{"path": "hack/build.sh", "mode": "100755", "type": "blob", "content": "#!/bin/sh"},
+		// GitHub deletes a file when its tree entry has a null SHA
+		map[string]interface{}{"path": "README.md", "mode": "100644", "type": "blob", "sha": nil},
+	}, treeRequest["tree"])
+
+	assert.Equal(t, "update files", commitRequest["message"])
+	assert.Equal(t, "tree-sha", commitRequest["tree"])
+	assert.Equal(t, []interface{}{"parent-sha"}, commitRequest["parents"])
+
+	assert.Equal(t, map[string]interface{}{"sha": "commit-sha", "force": true}, refRequest)
+}
+
+func TestCreateCommitWithoutFiles(t *testing.T) {
+	g := newTestGithub(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	})
+
+	_, err := g.CreateCommit("repo", "main", "nothing", nil, false)
+	assert.ErrorContains(t, err, "no files to commit")
+}
@@ -3,9 +3,11 @@ package github
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/go-github/v44/github"
 	. "github.com/onsi/ginkgo/v2"
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 func (g *Github) CheckIfRepositoryExist(repository string) bool {
@@ -120,3 +122,70 @@ func (g *Github) DeleteRepository(repository *github.Repository) error {
 	}
 	return nil
 }
+
+// ForkRepository forks the sourceOwner/sourceRepository into the organization and renames the fork to newName.
+// GitHub creates forks asynchronously, so it waits until the fork is available.
+// Note that GitHub keeps only one fork of a repository network per organization - forking the same source again
+// returns the already existing fork (under its new name), so specs running in parallel should prefer templates.
+func (g *Github) ForkRepository(sourceOwner, sourceRepository, newName string) (*github.Repository, error) {
+	ctx := context.Background()
+	fork, _, err := g.client.Repositories.CreateFork(ctx, sourceOwner, sourceRepository, &github.RepositoryCreateForkOptions{
+		Organization: g.organization,
+	})
+	if err != nil {
+		if _, ok := err.(*github.AcceptedError); !ok {
+			return nil, fmt.Errorf("error when forking repository %s/%s: %v", sourceOwner, sourceRepository, err)
+		}
+	}
+
+	forkName := sourceRepository
+	if fork != nil && fork.GetName() != "" {
+		forkName = fork.GetName()
+	}
+	err = wait.PollImmediate(5*time.Second, 2*time.Minute, func() (done bool, err error) {
+		fork, _, err = g.client.Repositories.Get(ctx, g.organization, forkName)
+		return err == nil, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error when waiting for the fork of %s/%s to be created: %v", sourceOwner, sourceRepository, err)
+	}
+
+	if newName != "" && newName != forkName {
+		fork, _, err = g.client.Repositories.Edit(ctx, g.organization, forkName, &github.Repository{Name: github.String(newName)})
+		if err != nil {
+			return nil, fmt.Errorf("error when renaming fork %s to %s: %v", forkName, newName, err)
+		}
+	}
+	return fork, nil
+}
+
+// CreateRepositoryFromTemplate generates a new repository in the organization from the templateOwner/templateRepository template repository
+func (g *Github) CreateRepositoryFromTemplate(templateOwner, templateRepository, newName, description string) (*github.Repository, error) {
+	repo, _, err := g.client.Repositories.CreateFromTemplate(context.Background(), templateOwner, templateRepository, &github.TemplateRepoRequest{
+		Name:        github.String(newName),
+		Owner:       github.String(g.organization),
+		Description: github.String(description),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error when creating repository %s from template %s/%s: %v", newName, templateOwner, templateRepository, err)
+	}
+	return repo, nil
+}
+
+// UpdateRepositoryDescription sets the description of a repository in the organization
+func (g *Github) UpdateRepositoryDescription(repository, description string) error {
+	_, _, err := g.client.Repositories.Edit(context.Background(), g.organization, repository, &github.Repository{Description: github.String(description)})
+	if err != nil {
+		return fmt.Errorf("error when updating description of repository %s: %v", repository, err)
+	}
+	return nil
+}
+
+// SetRepositoryTopics replaces all topics of a repository in the organization
+func (g *Github) SetRepositoryTopics(repository string, topics []string) error {
+	_, _, err := g.client.Repositories.ReplaceAllTopics(context.Background(), g.organization, repository, topics)
+	if err != nil {
+		return fmt.Errorf("error when setting topics %v of repository %s: %v", topics, repository, err)
+	}
+	return nil
+}
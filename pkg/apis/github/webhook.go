@@ -2,7 +2,10 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/go-github/v44/github"
 )
@@ -43,3 +46,126 @@ func (g *Github) DeleteWebhook(repository string, ID int64) error {
 	}
 	return nil
 }
+
+// ErrWebhookNotFound is returned by FindWebhookByURL when no webhook of the repository matches the URL
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+type WebhookDeliveryState string
+
+const (
+	// No webhook with the expected URL is configured in the repository
+	WebhookMissing WebhookDeliveryState = "webhook missing"
+	// GitHub didn't deliver any event, or the receiver didn't accept any of them
+	WebhookNotDelivered WebhookDeliveryState = "not delivered"
+	// At least one event was accepted by the receiver, but the expected effect didn't happen
+	WebhookDeliveredNotActedUpon WebhookDeliveryState = "delivered but not acted upon"
+)
+
+// WebhookDeliveryReport summarizes deliveries of a repository webhook since a point in time
+type WebhookDeliveryReport struct {
+	Repository string
+	HookURL    string
+	HookID     int64
+	Since      time.Time
+	State      WebhookDeliveryState
+	Deliveries []*github.HookDelivery
+}
+
+func (r *WebhookDeliveryReport) String() string {
+	s := fmt.Sprintf("webhook %q of repo %s: %s since %s", r.HookURL, r.Repository, r.State, r.Since.Format(time.RFC3339))
+	for _, d := range r.Deliveries {
+		s += fmt.Sprintf("\n  delivery %d (%s/%s, redelivery: %t) at %s: %s (status code %d)", d.GetID(), d.GetEvent(), d.GetAction(), d.GetRedelivery(), d.GetDeliveredAt().Format(time.RFC3339), d.GetStatus(), d.GetStatusCode())
+	}
+	return s
+}
+
+// FindWebhookByURL returns the first webhook of the repository whose URL contains the given string (e.g. the PaC controller host)
+func (g *Github) FindWebhookByURL(repository, url string) (*github.Hook, error) {
+	hooks, err := g.ListRepoWebhooks(repository)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range hooks {
+		if hookURL, ok := h.Config["url"].(string); ok && strings.Contains(hookURL, url) {
+			return h, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no webhook with url %q in the repo %s", ErrWebhookNotFound, url, repository)
+}
+
+// ListWebhookDeliveries returns deliveries (without request/response payloads) of a webhook, newest first, that happened after a specified time
+func (g *Github) ListWebhookDeliveries(repository string, hookID int64, since time.Time) ([]*github.HookDelivery, error) {
+	opts := &github.ListCursorOptions{PerPage: 100}
+	var deliveries []*github.HookDelivery
+	for {
+		page, resp, err := g.client.Repositories.ListHookDeliveries(context.Background(), g.organization, repository, hookID, opts)
+		if err != nil {
+			return nil, fmt.Errorf("error when listing deliveries of webhook %d in the repo %s: %v", hookID, repository, err)
+		}
+		for _, d := range page {
+			if d.GetDeliveredAt().Before(since) {
+				return deliveries, nil
+			}
+			deliveries = append(deliveries, d)
+		}
+		if resp.Cursor == "" {
+			return deliveries, nil
+		}
+		opts.Cursor = resp.Cursor
+	}
+}
+
+// GetWebhookDelivery returns a delivery of a webhook including headers and payloads of the request and the response
+func (g *Github) GetWebhookDelivery(repository string, hookID, deliveryID int64) (*github.HookDelivery, error) {
+	delivery, _, err := g.client.Repositories.GetHookDelivery(context.Background(), g.organization, repository, hookID, deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("error when getting delivery %d of webhook %d in the repo %s: %v", deliveryID, hookID, repository, err)
+	}
+	return delivery, nil
+}
+
+// RedeliverWebhookDelivery asks GitHub to send the same payload of a delivery again
+func (g *Github) RedeliverWebhookDelivery(repository string, hookID, deliveryID int64) (*github.HookDelivery, error) {
+	delivery, _, err := g.client.Repositories.RedeliverHookDelivery(context.Background(), g.organization, repository, hookID, deliveryID)
+	if err != nil {
+		if _, ok := err.(*github.AcceptedError); !ok {
+			return nil, fmt.Errorf("error when redelivering delivery %d of webhook %d in the repo %s: %v", deliveryID, hookID, repository, err)
+		}
+	}
+	return delivery, nil
+}
+
+// GetWebhookDeliveryReport checks what happened with deliveries of the webhook matching hookURL since a specified time
+func (g *Github) GetWebhookDeliveryReport(repository, hookURL string, since time.Time) (*WebhookDeliveryReport, error) {
+	report := &WebhookDeliveryReport{Repository: repository, HookURL: hookURL, Since: since, State: WebhookMissing}
+	hook, err := g.FindWebhookByURL(repository, hookURL)
+	if errors.Is(err, ErrWebhookNotFound) {
+		return report, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	report.HookID = hook.GetID()
+
+	if report.Deliveries, err = g.ListWebhookDeliveries(repository, hook.GetID(), since); err != nil {
+		return nil, err
+	}
+	report.State = WebhookNotDelivered
+	for _, d := range report.Deliveries {
+		if d.GetStatusCode() >= 200 && d.GetStatusCode() < 300 {
+			report.State = WebhookDeliveredNotActedUpon
+			break
+		}
+	}
+	return report, nil
+}
+
+// DescribeWebhookDeliveries returns a human readable webhook delivery report, meant to be used in failure messages of specs
+// waiting for an effect of a webhook (e.g. a PaC PipelineRun)
+func (g *Github) DescribeWebhookDeliveries(repository, hookURL string, since time.Time) string {
+	report, err := g.GetWebhookDeliveryReport(repository, hookURL, since)
+	if err != nil {
+		return fmt.Sprintf("unable to get webhook delivery report: %v", err)
+	}
+	return report.String()
+}
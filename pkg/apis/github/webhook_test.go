@@ -0,0 +1,39 @@
+package github
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetWebhookDeliveryReport(t *testing.T) {
+	hooksStatus := http.StatusOK
+	g := newTestGithub(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/org/repo/hooks":
+			w.WriteHeader(hooksStatus)
+			_, _ = w.Write([]byte(`[{"id": 1, "config": {"url": "https://pac.example.com"}}]`))
+		case "/repos/org/repo/hooks/1/deliveries":
+			_, _ = w.Write([]byte(`[{"id": 2, "status_code": 202, "delivered_at": "2023-01-02T12:00:00Z"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	since := time.Date(2023, 1, 2, 11, 0, 0, 0, time.UTC)
+
+	report, err := g.GetWebhookDeliveryReport("repo", "pac.example.com", since)
+	assert.NoError(t, err)
+	assert.Equal(t, WebhookDeliveredNotActedUpon, report.State)
+	assert.Equal(t, int64(1), report.HookID)
+
+	report, err = g.GetWebhookDeliveryReport("repo", "other.example.com", since)
+	assert.NoError(t, err)
+	assert.Equal(t, WebhookMissing, report.State)
+
+	// Failing to list the webhooks doesn't mean the webhook is missing
+	hooksStatus = http.StatusForbidden
+	_, err = g.GetWebhookDeliveryReport("repo", "pac.example.com", since)
+	assert.ErrorContains(t, err, "403")
+}
@@ -0,0 +1,45 @@
+package gitlab
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+type Gitlab struct {
+	client *gitlab.Client
+	group  string
+}
+
+// NewGitlabClient creates a client for the GitLab REST API v4 served at apiURL.
+// All repositories handled by the client are expected to live in the given group.
+func NewGitlabClient(token, apiURL, group string) (*Gitlab, error) {
+	client, err := gitlab.NewClient(token, gitlab.WithBaseURL(apiURL))
+	if err != nil {
+		return &Gitlab{}, err
+	}
+	gitlabClient := &Gitlab{
+		client: client,
+		group:  group,
+	}
+
+	return gitlabClient, nil
+}
+
+// projectID returns the full path of a project in the group, which GitLab accepts as a project ID
+func (g *Gitlab) projectID(repository string) string {
+	return fmt.Sprintf("%s/%s", g.group, repository)
+}
+
+// GetGroup returns the GitLab group the client works with
+func (g *Gitlab) GetGroup() string {
+	return g.group
+}
+
+// RepositoryURL returns the web URL of a project in the group. GitLab serves the web UI on the same host as the API.
+func (g *Gitlab) RepositoryURL(repository string) string {
+	webURL := *g.client.BaseURL()
+	webURL.Path = strings.TrimSuffix(strings.TrimSuffix(webURL.Path, "/"), "/api/v4")
+	return fmt.Sprintf("%s/%s", strings.TrimSuffix(webURL.String(), "/"), g.projectID(repository))
+}
@@ -0,0 +1,41 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+func (g *Gitlab) DeleteRef(repository, branchName string) error {
+	_, err := g.client.Branches.DeleteBranch(g.projectID(repository), branchName)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// CreateRef creates a new branch in a specified GitLab project,
+// that will be based on the latest commit from a specified branch name
+func (g *Gitlab) CreateRef(repository, baseBranchName, newBranchName string) error {
+	opts := &gitlab.CreateBranchOptions{
+		Branch: gitlab.String(newBranchName),
+		Ref:    gitlab.String(baseBranchName),
+	}
+	_, _, err := g.client.Branches.CreateBranch(g.projectID(repository), opts)
+	if err != nil {
+		return fmt.Errorf("error when creating a new branch '%s' from '%s' for the repo '%s': %+v", newBranchName, baseBranchName, repository, err)
+	}
+	return nil
+}
+
+func (g *Gitlab) ExistsRef(repository, branchName string) (bool, error) {
+	_, resp, err := g.client.Branches.GetBranch(g.projectID(repository), branchName)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return false, nil
+		}
+		return false, fmt.Errorf("error when getting the branch '%s' for the repo '%s': %+v", branchName, repository, err)
+	}
+	return true, nil
+}
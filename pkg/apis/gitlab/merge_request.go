@@ -0,0 +1,69 @@
+package gitlab
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+// ListMergeRequests returns all opened merge requests of a given project
+func (g *Gitlab) ListMergeRequests(repository string) ([]*gitlab.MergeRequest, error) {
+	opts := &gitlab.ListProjectMergeRequestsOptions{
+		ListOptions: gitlab.ListOptions{
+			PerPage: 100,
+		},
+		State: gitlab.String("opened"),
+	}
+	var allMrs []*gitlab.MergeRequest
+	for {
+		mrs, resp, err := g.client.MergeRequests.ListProjectMergeRequests(g.projectID(repository), opts)
+		if err != nil {
+			return nil, fmt.Errorf("error when listing merge requests for the repo %s: %v", repository, err)
+		}
+		allMrs = append(allMrs, mrs...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return allMrs, nil
+}
+
+// ListMergeRequestNotesSince returns merge request comments (notes) created after the given time, oldest first
+func (g *Gitlab) ListMergeRequestNotesSince(repository string, mrIID int, since time.Time) ([]*gitlab.Note, error) {
+	opts := &gitlab.ListMergeRequestNotesOptions{
+		ListOptions: gitlab.ListOptions{
+			PerPage: 100,
+		},
+		OrderBy: gitlab.String("created_at"),
+		Sort:    gitlab.String("asc"),
+	}
+	var filtered []*gitlab.Note
+	for {
+		notes, resp, err := g.client.Notes.ListMergeRequestNotes(g.projectID(repository), mrIID, opts)
+		if err != nil {
+			return nil, fmt.Errorf("error when listing merge request notes for the repo %s: %v", repository, err)
+		}
+		for _, n := range notes {
+			if n.CreatedAt != nil && n.CreatedAt.After(since) {
+				filtered = append(filtered, n)
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return filtered, nil
+}
+
+func (g *Gitlab) AcceptMergeRequest(repository string, mrIID int) (*gitlab.MergeRequest, error) {
+	mr, _, err := g.client.MergeRequests.AcceptMergeRequest(g.projectID(repository), mrIID, &gitlab.AcceptMergeRequestOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error when merging merge request number %d for the repo %s: %v", mrIID, repository, err)
+	}
+
+	return mr, nil
+}
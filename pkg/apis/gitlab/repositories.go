@@ -0,0 +1,120 @@
+package gitlab
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	"github.com/xanzy/go-gitlab"
+)
+
+func (g *Gitlab) CheckIfRepositoryExist(repository string) bool {
+	_, resp, err := g.client.Projects.GetProject(g.projectID(repository), &gitlab.GetProjectOptions{})
+	if err != nil {
+		GinkgoWriter.Printf("error when sending request to Gitlab API: %v\n", err)
+		return false
+	}
+	GinkgoWriter.Printf("repository %s status request to gitlab: %d\n", repository, resp.StatusCode)
+	return resp.StatusCode == 200
+}
+
+func (g *Gitlab) CreateFile(repository, pathToFile, fileContent, branchName string) (*gitlab.FileInfo, error) {
+	opts := &gitlab.CreateFileOptions{
+		Branch:        gitlab.String(branchName),
+		Content:       gitlab.String(fileContent),
+		CommitMessage: gitlab.String("e2e test commit message"),
+	}
+
+	file, _, err := g.client.RepositoryFiles.CreateFile(g.projectID(repository), pathToFile, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error when creating file contents: %v", err)
+	}
+
+	return file, nil
+}
+
+func (g *Gitlab) GetFile(repository, pathToFile, branchName string) (*gitlab.File, error) {
+	opts := &gitlab.GetFileOptions{Ref: gitlab.String("HEAD")}
+	if branchName != "" {
+		opts.Ref = gitlab.String(branchName)
+	}
+	file, _, err := g.client.RepositoryFiles.GetFile(g.projectID(repository), pathToFile, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error when listing file contents: %v", err)
+	}
+
+	return file, nil
+}
+
+// UpdateFile updates the content of a file. The lastCommitID is used for optimistic locking and can be left empty
+func (g *Gitlab) UpdateFile(repository, pathToFile, newContent, branchName, lastCommitID string) (*gitlab.FileInfo, error) {
+	opts := &gitlab.UpdateFileOptions{
+		Branch:        gitlab.String(branchName),
+		Content:       gitlab.String(newContent),
+		CommitMessage: gitlab.String("e2e test commit message"),
+	}
+	if lastCommitID != "" {
+		opts.LastCommitID = gitlab.String(lastCommitID)
+	}
+	updatedFile, _, err := g.client.RepositoryFiles.UpdateFile(g.projectID(repository), pathToFile, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error when updating a file on gitlab: %v", err)
+	}
+
+	return updatedFile, nil
+}
+
+func (g *Gitlab) DeleteFile(repository, pathToFile, branchName string) error {
+	if branchName == "" {
+		project, _, err := g.client.Projects.GetProject(g.projectID(repository), &gitlab.GetProjectOptions{})
+		if err != nil {
+			return fmt.Errorf("error when getting the default branch of the repo %s on gitlab: %v", repository, err)
+		}
+		branchName = project.DefaultBranch
+	}
+	opts := &gitlab.DeleteFileOptions{
+		Branch:        gitlab.String(branchName),
+		CommitMessage: gitlab.String("delete test files"),
+	}
+
+	_, err := g.client.RepositoryFiles.DeleteFile(g.projectID(repository), pathToFile, opts)
+	if err != nil {
+		return fmt.Errorf("error when deleting file on gitlab: %v", err)
+	}
+	return nil
+}
+
+// GetAllRepositories returns all projects of the group, including projects in subgroups
+func (g *Gitlab) GetAllRepositories() ([]*gitlab.Project, error) {
+	opt := &gitlab.ListGroupProjectsOptions{
+		ListOptions: gitlab.ListOptions{
+			PerPage: 100,
+		},
+		IncludeSubGroups: gitlab.Bool(true),
+	}
+	var allProjects []*gitlab.Project
+	for {
+		projects, resp, err := g.client.Groups.ListGroupProjects(g.group, opt)
+		if err != nil {
+			return nil, err
+		}
+		allProjects = append(allProjects, projects...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return allProjects, nil
+}
+
+func (g *Gitlab) DeleteRepository(project *gitlab.Project) error {
+	GinkgoWriter.Printf("Deleting repository %s\n", project.PathWithNamespace)
+	var pid interface{} = project.ID
+	if project.ID == 0 {
+		pid = project.PathWithNamespace
+	}
+	_, err := g.client.Projects.DeleteProject(pid)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package gitlab
+
+import (
+	"fmt"
+
+	"github.com/xanzy/go-gitlab"
+)
+
+func (g *Gitlab) ListRepoWebhooks(repository string) ([]*gitlab.ProjectHook, error) {
+	opts := &gitlab.ListProjectHooksOptions{PerPage: 100}
+	var allHooks []*gitlab.ProjectHook
+	for {
+		hooks, resp, err := g.client.Projects.ListProjectHooks(g.projectID(repository), opts)
+		if err != nil {
+			return nil, fmt.Errorf("error when listing webhooks: %v", err)
+		}
+		allHooks = append(allHooks, hooks...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return allHooks, nil
+}
+
+func (g *Gitlab) CreateWebhook(repository, url string) (int, error) {
+	newWebhook := &gitlab.AddProjectHookOptions{
+		URL:                   gitlab.String(url),
+		PushEvents:            gitlab.Bool(true),
+		EnableSSLVerification: gitlab.Bool(false),
+	}
+
+	hook, _, err := g.client.Projects.AddProjectHook(g.projectID(repository), newWebhook)
+	if err != nil {
+		return 0, fmt.Errorf("error when creating a webhook: %v", err)
+	}
+	return hook.ID, err
+}
+
+func (g *Gitlab) DeleteWebhook(repository string, ID int) error {
+	_, err := g.client.Projects.DeleteProjectHook(g.projectID(repository), ID)
+	if err != nil {
+		return fmt.Errorf("error when deleting webhook: %v", err)
+	}
+	return nil
+}
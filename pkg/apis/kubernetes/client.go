@@ -29,6 +29,7 @@ import (
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/transport"
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 )
@@ -99,8 +100,11 @@ func (c *CustomClient) DynamicClient() dynamic.Interface {
 	return c.dynamicClient
 }
 
-func NewDevSandboxProxyClient(userName string) (*K8SClient, error) {
-	asAdminClient, err := NewAdminKubernetesClient()
+// NewDevSandboxProxyClient returns the admin client and a client of a sandbox user. Options (or the defaults,
+// see GetDefaultClientOptions) are applied to both clients
+func NewDevSandboxProxyClient(userName string, opts ...ClientOptions) (*K8SClient, error) {
+	options := resolveClientOptions(opts)
+	asAdminClient, err := NewAdminKubernetesClient(options)
 	if err != nil {
 		return nil, err
 	}
@@ -125,7 +129,13 @@ func NewDevSandboxProxyClient(userName string) (*K8SClient, error) {
 		return nil, err
 	}
 
-	sandboxProxyClient, err := createCustomClient(*userCfg)
+	// The token stored in the kubeconfig expires during long suites, requests use a token renewed before its expiry instead
+	if userAuthInfo.TokenSource != nil {
+		userCfg.BearerToken = ""
+		userCfg.Wrap(transport.TokenSourceWrapTransport(userAuthInfo.TokenSource))
+	}
+
+	sandboxProxyClient, err := createCustomClient(*userCfg, options)
 	if err != nil {
 		return nil, err
 	}
@@ -138,16 +148,70 @@ func NewDevSandboxProxyClient(userName string) (*K8SClient, error) {
 	}, nil
 }
 
-func NewAdminKubernetesClient() (*CustomClient, error) {
+// NewImpersonatingClient returns the admin client and a developer client which performs all requests as the
+// impersonated user (or service account), so RBAC of arbitrary users can be tested without the sandbox proxy.
+// The admin (kubeconfig) user needs to be allowed to "impersonate" users, groups and extra fields.
+func NewImpersonatingClient(impersonate rest.ImpersonationConfig, opts ...ClientOptions) (*K8SClient, error) {
+	options := resolveClientOptions(opts)
+	if impersonate.UserName == "" {
+		return nil, fmt.Errorf("name of the user to impersonate needs to be specified")
+	}
+
+	adminKubeconfig, err := config.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	asAdminClient, err := createCustomClient(*adminKubeconfig, options)
+	if err != nil {
+		return nil, err
+	}
+
+	userCfg := rest.CopyConfig(adminKubeconfig)
+	userCfg.Impersonate = impersonate
+	asUserClient, err := createCustomClient(*userCfg, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &K8SClient{
+		AsKubeAdmin:     asAdminClient,
+		AsKubeDeveloper: asUserClient,
+		UserName:        impersonate.UserName,
+	}, nil
+}
+
+// NewImpersonatingUserClient returns clients where the developer client acts as the given user and member of the given groups
+func NewImpersonatingUserClient(userName string, groups []string, opts ...ClientOptions) (*K8SClient, error) {
+	// Real users are always member of the system:authenticated group
+	return NewImpersonatingClient(rest.ImpersonationConfig{
+		UserName: userName,
+		Groups:   append([]string{"system:authenticated"}, groups...),
+	}, opts...)
+}
+
+// NewImpersonatingServiceAccountClient returns clients where the developer client acts as the given service account
+func NewImpersonatingServiceAccountClient(namespace, serviceAccountName string, opts ...ClientOptions) (*K8SClient, error) {
+	return NewImpersonatingClient(rest.ImpersonationConfig{
+		UserName: fmt.Sprintf("system:serviceaccount:%s:%s", namespace, serviceAccountName),
+		Groups:   []string{"system:serviceaccounts", "system:serviceaccounts:" + namespace, "system:authenticated"},
+	}, opts...)
+}
+
+// NewAdminKubernetesClient returns a client for the default kubeconfig. If no options are passed, the defaults
+// (see GetDefaultClientOptions) are used
+func NewAdminKubernetesClient(opts ...ClientOptions) (*CustomClient, error) {
 	adminKubeconfig, err := config.GetConfig()
 	if err != nil {
 		return nil, err
 	}
 
-	return createCustomClient(*adminKubeconfig)
+	return createCustomClient(*adminKubeconfig, resolveClientOptions(opts))
 }
 
-func createCustomClient(cfg rest.Config) (*CustomClient, error) {
+func createCustomClient(c rest.Config, opts ClientOptions) (*CustomClient, error) {
+	cfg := *opts.apply(&c)
+
 	client, err := kubernetes.NewForConfig(&cfg)
 	if err != nil {
 		return nil, err
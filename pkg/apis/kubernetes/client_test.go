@@ -0,0 +1,116 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+// fakeAPIServer serves discovery and namespaces, and records the impersonation headers of namespace requests
+type fakeAPIServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	headers []http.Header
+}
+
+func newFakeAPIServer(t *testing.T) *fakeAPIServer {
+	s := &fakeAPIServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api":
+			_, _ = w.Write([]byte(`{"kind": "APIVersions", "versions": ["v1"]}`))
+		case "/apis":
+			_, _ = w.Write([]byte(`{"kind": "APIGroupList", "groups": []}`))
+		case "/api/v1":
+			_, _ = w.Write([]byte(`{"kind": "APIResourceList", "groupVersion": "v1", "resources": [{"name": "namespaces", "kind": "Namespace", "verbs": ["get"]}]}`))
+		case "/api/v1/namespaces/test":
+			s.mu.Lock()
+			s.headers = append(s.headers, http.Header{
+				"Impersonate-User":  r.Header.Values("Impersonate-User"),
+				"Impersonate-Group": r.Header.Values("Impersonate-Group"),
+			})
+			s.mu.Unlock()
+			_, _ = w.Write([]byte(`{"kind": "Namespace", "apiVersion": "v1", "metadata": {"name": "test"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(s.Close)
+
+	kubeconfig := filepath.Join(t.TempDir(), "kubeconfig")
+	content := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: fake
+  cluster:
+    server: %s
+users:
+- name: admin
+  user:
+    token: admin-token
+contexts:
+- name: fake
+  context:
+    cluster: fake
+    user: admin
+current-context: fake
+`, s.URL)
+	assert.NoError(t, os.WriteFile(kubeconfig, []byte(content), 0600))
+	t.Setenv("KUBECONFIG", kubeconfig)
+	return s
+}
+
+// getNamespace returns impersonation headers sent by the client when getting a namespace
+func (s *fakeAPIServer) getNamespace(t *testing.T, c *CustomClient) http.Header {
+	_, err := c.KubeInterface().CoreV1().Namespaces().Get(context.TODO(), "test", metav1.GetOptions{})
+	assert.NoError(t, err)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.headers[len(s.headers)-1]
+}
+
+func TestNewImpersonatingUserClient(t *testing.T) {
+	server := newFakeAPIServer(t)
+
+	k, err := NewImpersonatingUserClient("alice", []string{"qe"}, ClientOptions{QPS: 50, Burst: 100})
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", k.UserName)
+	assert.Nil(t, k.SandboxController)
+
+	headers := server.getNamespace(t, k.AsKubeDeveloper)
+	assert.Equal(t, []string{"alice"}, headers.Values("Impersonate-User"))
+	assert.Equal(t, []string{"system:authenticated", "qe"}, headers.Values("Impersonate-Group"))
+
+	headers = server.getNamespace(t, k.AsKubeAdmin)
+	assert.Empty(t, headers.Values("Impersonate-User"))
+	assert.Empty(t, headers.Values("Impersonate-Group"))
+}
+
+func TestNewImpersonatingServiceAccountClient(t *testing.T) {
+	server := newFakeAPIServer(t)
+
+	k, err := NewImpersonatingServiceAccountClient("tenant", "pipeline")
+	assert.NoError(t, err)
+	assert.Equal(t, "system:serviceaccount:tenant:pipeline", k.UserName)
+
+	headers := server.getNamespace(t, k.AsKubeDeveloper)
+	assert.Equal(t, []string{"system:serviceaccount:tenant:pipeline"}, headers.Values("Impersonate-User"))
+	assert.Equal(t, []string{"system:serviceaccounts", "system:serviceaccounts:tenant", "system:authenticated"}, headers.Values("Impersonate-Group"))
+}
+
+func TestNewImpersonatingClientRequiresUserName(t *testing.T) {
+	newFakeAPIServer(t)
+
+	_, err := NewImpersonatingClient(rest.ImpersonationConfig{Groups: []string{"qe"}})
+	assert.Error(t, err)
+}
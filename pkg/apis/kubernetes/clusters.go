@@ -0,0 +1,82 @@
+package client
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+// Name of the cluster the default kubeconfig points to
+const HostClusterName = "host"
+
+// ClusterConfig identifies a cluster by a kubeconfig file and a context within it
+type ClusterConfig struct {
+	Name string
+	// Path to the kubeconfig file. If empty, the default kubeconfig (KUBECONFIG env var, in-cluster config or ~/.kube/config) is used
+	Kubeconfig string
+	// Context of the kubeconfig. If empty, the current context is used
+	Context string
+}
+
+// NewClusterKubernetesClient returns a client for the cluster described by the config. If no options are passed,
+// the defaults (see GetDefaultClientOptions) are used
+func NewClusterKubernetesClient(cluster ClusterConfig, opts ...ClientOptions) (*CustomClient, error) {
+	options := resolveClientOptions(opts)
+	if cluster.Kubeconfig == "" {
+		cfg, err := config.GetConfigWithContext(cluster.Context)
+		if err != nil {
+			return nil, fmt.Errorf("error when loading config of cluster %s: %v", cluster.Name, err)
+		}
+		return createCustomClient(*cfg, options)
+	}
+
+	if _, err := os.Stat(cluster.Kubeconfig); err != nil {
+		return nil, fmt.Errorf("kubeconfig of cluster %s is not accessible: %v", cluster.Name, err)
+	}
+	cfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: cluster.Kubeconfig},
+		&clientcmd.ConfigOverrides{CurrentContext: cluster.Context},
+	).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error when loading config of cluster %s: %v", cluster.Name, err)
+	}
+	return createCustomClient(*cfg, options)
+}
+
+// GetClusterConfigsFromEnv parses additional clusters from E2E_CLUSTERS env var.
+// The format is a comma separated list of "<name>=<kubeconfig path>[@<context>]" items, the kubeconfig path can be
+// empty to use a context from the default kubeconfig, e.g. "member=/tmp/member.kubeconfig,managed=@managed-cluster"
+func GetClusterConfigsFromEnv() ([]ClusterConfig, error) {
+	return ParseClusterConfigs(os.Getenv(constants.E2E_CLUSTERS_ENV))
+}
+
+// ParseClusterConfigs parses clusters in the E2E_CLUSTERS env var format
+func ParseClusterConfigs(value string) ([]ClusterConfig, error) {
+	var clusters []ClusterConfig
+	names := map[string]bool{HostClusterName: true}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, location, found := strings.Cut(item, "=")
+		if !found || name == "" || location == "" {
+			return nil, fmt.Errorf("invalid cluster %q, expected <name>=<kubeconfig path>[@<context>]", item)
+		}
+		if names[name] {
+			return nil, fmt.Errorf("cluster %q is defined more than once", name)
+		}
+		names[name] = true
+		// The context follows the last "@", so the kubeconfig path may contain "@" (the context may not)
+		kubeconfig, context := location, ""
+		if i := strings.LastIndex(location, "@"); i >= 0 {
+			kubeconfig, context = location[:i], location[i+1:]
+		}
+		clusters = append(clusters, ClusterConfig{Name: name, Kubeconfig: kubeconfig, Context: context})
+	}
+	return clusters, nil
+}
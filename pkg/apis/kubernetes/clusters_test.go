@@ -0,0 +1,26 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseClusterConfigs(t *testing.T) {
+	clusters, err := ParseClusterConfigs(" member=/tmp/member.kubeconfig, managed=@managed-cluster,other=/home/user@example/kubeconfig@ctx,")
+	assert.NoError(t, err)
+	assert.Equal(t, []ClusterConfig{
+		{Name: "member", Kubeconfig: "/tmp/member.kubeconfig"},
+		{Name: "managed", Context: "managed-cluster"},
+		{Name: "other", Kubeconfig: "/home/user@example/kubeconfig", Context: "ctx"},
+	}, clusters)
+
+	clusters, err = ParseClusterConfigs("")
+	assert.NoError(t, err)
+	assert.Empty(t, clusters)
+
+	for _, value := range []string{"member", "=/tmp/member.kubeconfig", "member=", "host=/tmp/host.kubeconfig", "a=/tmp/a,a=/tmp/b"} {
+		_, err = ParseClusterConfigs(value)
+		assert.Error(t, err, value)
+	}
+}
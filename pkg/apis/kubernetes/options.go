@@ -0,0 +1,228 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+// ClientOptions tune the client side throttling and resiliency of the kubernetes clients
+type ClientOptions struct {
+	// Maximum queries per second to the API server. Zero keeps the client-go default (5)
+	QPS float32
+	// Maximum burst for throttling. Zero keeps the client-go default (10)
+	Burst int
+	// Timeout of a single request, including reading of the response. Watches, followed log streams and
+	// exec/attach/port-forward connections are long-running and not limited. Zero means no timeout
+	Timeout time.Duration
+	// How many times an idempotent (GET, HEAD, PUT, DELETE) request failed with 429, 5xx or a connection reset is retried
+	MaxRetries int
+	// Initial delay between retries, doubled with every attempt. Retry-After header of the response takes precedence
+	RetryBackoff time.Duration
+}
+
+const defaultRetryBackoff = 500 * time.Millisecond
+
+var defaultClientOptions = clientOptionsFromEnv()
+
+// SetDefaultClientOptions changes options used by all clients created without explicit options (e.g. by the framework)
+func SetDefaultClientOptions(opts ClientOptions) {
+	defaultClientOptions = opts
+}
+
+// GetDefaultClientOptions returns options used by clients created without explicit options.
+// They are initialized from E2E_CLIENT_QPS, E2E_CLIENT_BURST, E2E_CLIENT_TIMEOUT and E2E_CLIENT_MAX_RETRIES env vars
+func GetDefaultClientOptions() ClientOptions {
+	return defaultClientOptions
+}
+
+func clientOptionsFromEnv() ClientOptions {
+	opts := ClientOptions{RetryBackoff: defaultRetryBackoff}
+	if v := os.Getenv(constants.E2E_CLIENT_QPS_ENV); v != "" {
+		if qps, err := strconv.ParseFloat(v, 32); err == nil {
+			opts.QPS = float32(qps)
+		} else {
+			klog.Warningf("ignoring invalid %s value %q: %v", constants.E2E_CLIENT_QPS_ENV, v, err)
+		}
+	}
+	if v := os.Getenv(constants.E2E_CLIENT_BURST_ENV); v != "" {
+		if burst, err := strconv.Atoi(v); err == nil {
+			opts.Burst = burst
+		} else {
+			klog.Warningf("ignoring invalid %s value %q: %v", constants.E2E_CLIENT_BURST_ENV, v, err)
+		}
+	}
+	if v := os.Getenv(constants.E2E_CLIENT_TIMEOUT_ENV); v != "" {
+		if timeout, err := time.ParseDuration(v); err == nil {
+			opts.Timeout = timeout
+		} else {
+			klog.Warningf("ignoring invalid %s value %q: %v", constants.E2E_CLIENT_TIMEOUT_ENV, v, err)
+		}
+	}
+	if v := os.Getenv(constants.E2E_CLIENT_MAX_RETRIES_ENV); v != "" {
+		if retries, err := strconv.Atoi(v); err == nil {
+			opts.MaxRetries = retries
+		} else {
+			klog.Warningf("ignoring invalid %s value %q: %v", constants.E2E_CLIENT_MAX_RETRIES_ENV, v, err)
+		}
+	}
+	return opts
+}
+
+func resolveClientOptions(opts []ClientOptions) ClientOptions {
+	if len(opts) > 0 {
+		return opts[0]
+	}
+	return defaultClientOptions
+}
+
+// apply returns a copy of the config with the options set
+func (o ClientOptions) apply(cfg *rest.Config) *rest.Config {
+	cfg = rest.CopyConfig(cfg)
+	if o.QPS > 0 {
+		cfg.QPS = o.QPS
+	}
+	if o.Burst > 0 {
+		cfg.Burst = o.Burst
+	}
+	if o.Timeout > 0 {
+		// Not cfg.Timeout, which is the http.Client timeout and would cut watches and log streams too
+		cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return &timeoutRoundTripper{delegate: rt, timeout: o.Timeout}
+		})
+	}
+	if o.MaxRetries > 0 {
+		backoff := o.RetryBackoff
+		if backoff == 0 {
+			backoff = defaultRetryBackoff
+		}
+		cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return &retryRoundTripper{delegate: rt, maxRetries: o.MaxRetries, backoff: backoff}
+		})
+	}
+	return cfg
+}
+
+// timeoutRoundTripper cancels a request which didn't complete, including reading of the response body, within the timeout
+type timeoutRoundTripper struct {
+	delegate http.RoundTripper
+	timeout  time.Duration
+}
+
+func (t *timeoutRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if isLongRunning(req) {
+		return t.delegate.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.delegate.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return resp, err
+	}
+	resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnCloseBody releases the timeout context of a request once its response is read
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// isLongRunning returns true for watches, followed log streams and exec, attach and port-forward connections
+func isLongRunning(req *http.Request) bool {
+	query := req.URL.Query()
+	if query.Get("watch") == "true" || query.Get("watch") == "1" || query.Get("follow") == "true" {
+		return true
+	}
+	for _, suffix := range []string{"/exec", "/attach", "/portforward", "/proxy"} {
+		if strings.HasSuffix(req.URL.Path, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryRoundTripper retries idempotent requests which failed with a transient error. POST and PATCH requests are never
+// retried, the first attempt may have been applied by the API server even if the response was lost
+type retryRoundTripper struct {
+	delegate   http.RoundTripper
+	maxRetries int
+	backoff    time.Duration
+}
+
+func (r *retryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	delay := r.backoff
+	for attempt := 0; ; attempt++ {
+		resp, err := r.delegate.RoundTrip(req)
+		if attempt >= r.maxRetries || !isIdempotent(req.Method) || !isRetriable(resp, err) || !rewindBody(req) {
+			return resp, err
+		}
+
+		wait := delay
+		if resp != nil {
+			if seconds, convErr := strconv.Atoi(resp.Header.Get("Retry-After")); convErr == nil && seconds > 0 {
+				wait = time.Duration(seconds) * time.Second
+			}
+			resp.Body.Close()
+		}
+		klog.V(2).Infof("retrying %s %s (attempt %d/%d) in %s", req.Method, req.URL.Path, attempt+1, r.maxRetries, wait)
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+		delay *= 2
+	}
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func isRetriable(resp *http.Response, err error) bool {
+	if err != nil {
+		return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// rewindBody prepares the request body for another attempt. Requests with a body which can't be recreated are not retried
+func rewindBody(req *http.Request) bool {
+	if req.Body == nil || req.Body == http.NoBody {
+		return true
+	}
+	if req.GetBody == nil {
+		return false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return false
+	}
+	req.Body = body
+	return true
+}
@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryRoundTripper(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &retryRoundTripper{delegate: http.DefaultTransport, maxRetries: 3, backoff: time.Millisecond}}
+	req, _ := http.NewRequest(http.MethodPut, server.URL, strings.NewReader("{}"))
+	resp, err := client.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestRetryRoundTripperSkipsNonIdempotentRequests(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &retryRoundTripper{delegate: http.DefaultTransport, maxRetries: 3, backoff: time.Millisecond}}
+	for _, method := range []string{http.MethodPost, http.MethodPatch} {
+		attempts = 0
+		req, _ := http.NewRequest(method, server.URL, strings.NewReader("{}"))
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, 1, attempts, method)
+	}
+}
+
+func TestRetryRoundTripperGivesUp(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &retryRoundTripper{delegate: http.DefaultTransport, maxRetries: 2, backoff: time.Millisecond}}
+	resp, err := client.Get(server.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestTimeoutRoundTripperSkipsLongRunningRequests(t *testing.T) {
+	// Sends the first line of the response at once and the rest after a delay, like a log stream
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("first\n"))
+		w.(http.Flusher).Flush()
+		time.Sleep(200 * time.Millisecond)
+		_, _ = w.Write([]byte("second\n"))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &timeoutRoundTripper{delegate: http.DefaultTransport, timeout: 50 * time.Millisecond}}
+	for _, path := range []string{"/api/v1/namespaces/ns/pods/pod/log?follow=true", "/api/v1/namespaces/ns/pods?watch=true"} {
+		resp, err := client.Get(server.URL + path)
+		assert.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		assert.NoError(t, err, path)
+		assert.Equal(t, "first\nsecond\n", string(body))
+		resp.Body.Close()
+	}
+
+	resp, err := client.Get(server.URL + "/api/v1/namespaces/ns/pods")
+	assert.NoError(t, err)
+	_, err = io.ReadAll(resp.Body)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	resp.Body.Close()
+}
@@ -8,6 +8,34 @@ const (
 	// The github organization is used to create the gitops repositories in Red Hat Appstudio.
 	GITHUB_E2E_ORGANIZATION_ENV string = "MY_GITHUB_ORG" // #nosec
 
+	// Git hosting service used by provider agnostic tests and helpers: "github" (default) or "gitlab"
+	GIT_PROVIDER_ENV string = "GIT_PROVIDER"
+
+	// A gitlab token is required to run the tests against GitLab. The token need to have api permissions to the given gitlab group.
+	GITLAB_TOKEN_ENV string = "GITLAB_TOKEN" // #nosec
+
+	// GitLab REST API v4 endpoint. By default https://gitlab.com/api/v4 is used.
+	GITLAB_API_URL_ENV string = "GITLAB_API_URL"
+
+	// The gitlab group is used to host test repositories when running the tests against GitLab.
+	GITLAB_E2E_GROUP_ENV string = "MY_GITLAB_GROUP"
+
+	// ID grouping all resources (e.g. ephemeral GitHub repositories) created within a single test run. If not set, BUILD_ID env var (set by Prow) is used
+	E2E_RUN_ID_ENV string = "E2E_RUN_ID"
+
+	// Additional clusters the tests can act on besides the one from KUBECONFIG, e.g. "member=/tmp/member.kubeconfig,managed=@managed-context"
+	E2E_CLUSTERS_ENV string = "E2E_CLUSTERS"
+
+	// Client side throttling of the kubernetes clients used by the tests: maximum queries per second and burst
+	E2E_CLIENT_QPS_ENV   string = "E2E_CLIENT_QPS"
+	E2E_CLIENT_BURST_ENV string = "E2E_CLIENT_BURST"
+
+	// Timeout of a single request of the kubernetes clients (e.g. "30s")
+	E2E_CLIENT_TIMEOUT_ENV string = "E2E_CLIENT_TIMEOUT"
+
+	// How many times the kubernetes clients retry requests failed with 429, 5xx or connection reset
+	E2E_CLIENT_MAX_RETRIES_ENV string = "E2E_CLIENT_MAX_RETRIES"
+
 	// The quay organization is used to push container images using Red Hat Appstudio pipelines.
 	QUAY_E2E_ORGANIZATION_ENV string = "QUAY_E2E_ORGANIZATION" // #nosec
 
@@ -92,8 +120,30 @@ const (
 	ReleasePipelineImageRef = "quay.io/hacbs-release/pipeline-release:0.10"
 )
 
+// Prefixes the e2e suites pass to utils.GetGeneratedNamespace to name their users
+const (
+	BUILD_E2E_USER_PREFIX           = "build-e2e"
+	JVM_BUILD_E2E_USER_PREFIX       = "jvm-build"
+	E2E_DEMOS_USER_PREFIX           = "e2e-demos"
+	MULTI_COMPONENT_E2E_USER_PREFIX = "multi-comp-e2e"
+	SPI_DEMOS_USER_PREFIX           = "spi-demos"
+	MVP_DEMO_USER_PREFIX            = "mvp-demo-dev-namespace"
+	HAS_CDQ_USER_PREFIX             = "has-cdq"
+)
+
+// Users of the release suites
+const (
+	RELEASE_SERVICE_E2E_USER = "release-service-e2e"
+	RELEASE_E2E_PYXIS_USER   = "release-e2e-pyxis"
+	RELEASE_E2E_BUNDLE_USER  = "release-e2e-bundle"
+)
+
 var (
 	ComponentDefaultLabel         = map[string]string{"e2e-test": "true"}
 	ComponentDefaultAnnotation    = map[string]string{ComponentInitialBuildAnnotationKey: "processed"}
 	ComponentPaCRequestAnnotation = map[string]string{"appstudio.openshift.io/pac-provision": "request"}
+
+	// Used by the users cleanup to find users left behind by the suites
+	E2EGeneratedUserPrefixes = []string{BUILD_E2E_USER_PREFIX, JVM_BUILD_E2E_USER_PREFIX, E2E_DEMOS_USER_PREFIX, MULTI_COMPONENT_E2E_USER_PREFIX, SPI_DEMOS_USER_PREFIX, MVP_DEMO_USER_PREFIX, HAS_CDQ_USER_PREFIX}
+	E2EReleaseUsers          = []string{RELEASE_SERVICE_E2E_USER, RELEASE_E2E_PYXIS_USER, RELEASE_E2E_BUNDLE_USER}
 )
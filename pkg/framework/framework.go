@@ -2,6 +2,8 @@ package framework
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/avast/retry-go/v4"
@@ -17,6 +19,7 @@ import (
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils/release"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils/spi"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils/tekton"
+	"k8s.io/client-go/rest"
 )
 
 type ControllerHub struct {
@@ -37,6 +40,8 @@ type Framework struct {
 	SandboxController *sandbox.SandboxController
 	UserNamespace     string
 	UserName          string
+	// Admin controllers for every known cluster, including the host cluster (kubeCl.HostClusterName)
+	Clusters map[string]*ControllerHub
 }
 
 func NewFramework(userName string) (*Framework, error) {
@@ -75,13 +80,126 @@ func NewFramework(userName string) (*Framework, error) {
 		return nil, fmt.Errorf("'pipeline' service account wasn't created in %s namespace: %+v", userNamespace, err)
 	}
 
-	return &Framework{
+	f := &Framework{
 		AsKubeAdmin:       asAdmin,
 		AsKubeDeveloper:   asUser,
 		SandboxController: k.SandboxController,
 		UserNamespace:     userNamespace,
 		UserName:          k.UserName,
-	}, nil
+		Clusters:          map[string]*ControllerHub{kubeCl.HostClusterName: asAdmin},
+	}
+	if err := f.registerClustersFromEnv(); err != nil {
+		// Callers get no framework to clean up the already provisioned sandbox user with
+		if _, deleteErr := k.SandboxController.DeleteUserSignup(k.UserName); deleteErr != nil {
+			return nil, fmt.Errorf("%v (cleanup of user %s failed: %v)", err, k.UserName, deleteErr)
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+// NewFrameworkWithImpersonation returns a framework where AsKubeDeveloper impersonates the given user (or service account)
+// instead of using a sandbox user. No user namespace is provisioned, specs are expected to create namespaces and
+// RBAC they need for the impersonated user with the AsKubeAdmin controllers.
+func NewFrameworkWithImpersonation(impersonate rest.ImpersonationConfig) (*Framework, error) {
+	k, err := kubeCl.NewImpersonatingClient(impersonate)
+	if err != nil {
+		return nil, fmt.Errorf("error when initializing kubernetes clients: %v", err)
+	}
+
+	asAdmin, err := InitControllerHub(k.AsKubeAdmin)
+	if err != nil {
+		return nil, fmt.Errorf("error when initializing appstudio hub controllers for admin user: %v", err)
+	}
+
+	asUser, err := InitControllerHub(k.AsKubeDeveloper)
+	if err != nil {
+		return nil, fmt.Errorf("error when initializing appstudio hub controllers for impersonated user %s: %v", k.UserName, err)
+	}
+
+	f := &Framework{
+		AsKubeAdmin:     asAdmin,
+		AsKubeDeveloper: asUser,
+		UserName:        k.UserName,
+		Clusters:        map[string]*ControllerHub{kubeCl.HostClusterName: asAdmin},
+	}
+	if err := f.registerClustersFromEnv(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// RegisterCluster creates admin controllers for an additional cluster, so a spec can e.g. create resources
+// on the host cluster and verify the result on a member cluster
+func (f *Framework) RegisterCluster(cluster kubeCl.ClusterConfig) (*ControllerHub, error) {
+	if _, ok := f.Clusters[cluster.Name]; ok {
+		return nil, fmt.Errorf("cluster %s is already registered", cluster.Name)
+	}
+	cc, err := kubeCl.NewClusterKubernetesClient(cluster)
+	if err != nil {
+		return nil, err
+	}
+	hub, err := InitControllerHub(cc)
+	if err != nil {
+		return nil, fmt.Errorf("error when initializing appstudio hub controllers for cluster %s: %v", cluster.Name, err)
+	}
+	f.Clusters[cluster.Name] = hub
+	return hub, nil
+}
+
+// OnCluster returns admin controllers of a registered cluster
+func (f *Framework) OnCluster(name string) (*ControllerHub, error) {
+	hub, ok := f.Clusters[name]
+	if !ok {
+		return nil, fmt.Errorf("cluster %s is not registered, known clusters are: %v", name, f.ClusterNames())
+	}
+	return hub, nil
+}
+
+// ClusterNames returns sorted names of all registered clusters
+func (f *Framework) ClusterNames() []string {
+	names := make([]string, 0, len(f.Clusters))
+	for name := range f.Clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StorePipelineRuns stores logs and yamls of all pipelineRuns in the namespace from every registered cluster
+// under ARTIFACT_DIR/<testName>/<cluster name>
+func (f *Framework) StorePipelineRuns(testName, namespace string) error {
+	var errs []string
+	for _, name := range f.ClusterNames() {
+		hub := f.Clusters[name]
+		prs, err := hub.TektonController.ListAllPipelineRuns(namespace)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("cluster %s: %v", name, err))
+			continue
+		}
+		for i := range prs.Items {
+			if err := tekton.StoreClusterPipelineRun(&prs.Items[i], testName, name, hub.CommonController); err != nil {
+				errs = append(errs, fmt.Sprintf("cluster %s: %v", name, err))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("error when storing pipelineRuns: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (f *Framework) registerClustersFromEnv() error {
+	clusters, err := kubeCl.GetClusterConfigsFromEnv()
+	if err != nil {
+		return err
+	}
+	for _, c := range clusters {
+		if _, err := f.RegisterCluster(c); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func InitControllerHub(cc *kubeCl.CustomClient) (*ControllerHub, error) {
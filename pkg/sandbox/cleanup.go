@@ -0,0 +1,244 @@
+package sandbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	toolchainApi "github.com/codeready-toolchain/api/api/v1alpha1"
+	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Prefixes of user names created by the e2e suites and the load test
+var DefaultE2EUserPrefixes = defaultE2EUserPrefixes()
+
+func defaultE2EUserPrefixes() []string {
+	var prefixes []string
+	// utils.GetGeneratedNamespace appends "-" and a random suffix to the prefix
+	for _, p := range constants.E2EGeneratedUserPrefixes {
+		prefixes = append(prefixes, p+"-")
+	}
+	prefixes = append(prefixes, constants.E2EReleaseUsers...)
+	return append(prefixes, "testuser-")
+}
+
+// KeycloakUserRepresentation is a subset of user fields returned by keycloak admin API
+type KeycloakUserRepresentation struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	// Creation time in milliseconds since epoch
+	CreatedTimestamp int64 `json:"createdTimestamp"`
+}
+
+type UserCleanupOptions struct {
+	// Only users with name starting with one of the prefixes are deleted. Defaults to DefaultE2EUserPrefixes
+	Prefixes []string
+	// Only users created before this duration are deleted
+	OlderThan time.Duration
+	// Only list users which would be deleted
+	DryRun bool
+	// How many users are deleted at once. Defaults to 10
+	Concurrency int
+}
+
+// UserCleanupReport lists users which were (or would be, in the dry run mode) deleted
+type UserCleanupReport struct {
+	UserSignups   []string
+	KeycloakUsers []string
+	Errors        []error
+}
+
+// CleanupUsers deletes UserSignups (and so their tenant namespaces) and keycloak users left behind by the e2e suites
+func (s *SandboxController) CleanupUsers(opts UserCleanupOptions) (*UserCleanupReport, error) {
+	if len(opts.Prefixes) == 0 {
+		opts.Prefixes = DefaultE2EUserPrefixes
+	}
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 10
+	}
+	if _, err := s.initRoutes(); err != nil {
+		return nil, err
+	}
+
+	userSignups, err := s.ListStaleUserSignups(opts.Prefixes, opts.OlderThan)
+	if err != nil {
+		return nil, err
+	}
+	adminToken, err := s.getKeycloakAdminToken()
+	if err != nil {
+		return nil, err
+	}
+	keycloakUsers, err := s.ListStaleKeycloakUsers(adminToken, opts.Prefixes, opts.OlderThan)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &UserCleanupReport{}
+	var mu sync.Mutex
+	record := func(list *[]string, name string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			report.Errors = append(report.Errors, err)
+			return
+		}
+		*list = append(*list, name)
+	}
+
+	parallelize(len(userSignups), opts.Concurrency, func(i int) {
+		name := userSignups[i].GetName()
+		if opts.DryRun {
+			record(&report.UserSignups, name, nil)
+			return
+		}
+		if _, err := s.DeleteUserSignup(name); err != nil {
+			record(&report.UserSignups, name, fmt.Errorf("failed to delete UserSignup %s: %v", name, err))
+			return
+		}
+		record(&report.UserSignups, name, nil)
+	})
+	parallelize(len(keycloakUsers), opts.Concurrency, func(i int) {
+		user := keycloakUsers[i]
+		if opts.DryRun {
+			record(&report.KeycloakUsers, user.Username, nil)
+			return
+		}
+		if err := s.DeleteKeycloakUser(DEFAULT_KEYCLOAK_TESTING_REALM, adminToken, user.ID); err != nil {
+			record(&report.KeycloakUsers, user.Username, fmt.Errorf("failed to delete keycloak user %s: %v", user.Username, err))
+			return
+		}
+		record(&report.KeycloakUsers, user.Username, nil)
+	})
+	return report, nil
+}
+
+// ListStaleUserSignups returns UserSignups with names matching one of the prefixes and created before olderThan
+func (s *SandboxController) ListStaleUserSignups(prefixes []string, olderThan time.Duration) ([]toolchainApi.UserSignup, error) {
+	list := &toolchainApi.UserSignupList{}
+	if err := s.KubeRest.List(context.Background(), list, crclient.InNamespace(DEFAULT_TOOLCHAIN_NAMESPACE)); err != nil {
+		return nil, fmt.Errorf("failed to list UserSignups: %v", err)
+	}
+	var stale []toolchainApi.UserSignup
+	for _, us := range list.Items {
+		if hasAnyPrefix(us.GetName(), prefixes) && time.Since(us.GetCreationTimestamp().Time) > olderThan {
+			stale = append(stale, us)
+		}
+	}
+	return stale, nil
+}
+
+// ListStaleKeycloakUsers returns users of the testing realm with names matching one of the prefixes and created before olderThan
+func (s *SandboxController) ListStaleKeycloakUsers(adminToken string, prefixes []string, olderThan time.Duration) ([]KeycloakUserRepresentation, error) {
+	var stale []KeycloakUserRepresentation
+	for _, prefix := range prefixes {
+		users, err := s.searchKeycloakUsers(DEFAULT_KEYCLOAK_TESTING_REALM, adminToken, prefix)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			created := time.UnixMilli(u.CreatedTimestamp)
+			// Search matches substrings anywhere in user fields, so check the prefix again
+			if strings.HasPrefix(u.Username, prefix) && time.Since(created) > olderThan {
+				stale = append(stale, u)
+			}
+		}
+	}
+	return stale, nil
+}
+
+// DeleteKeycloakUser removes a user from a keycloak realm
+func (s *SandboxController) DeleteKeycloakUser(realm, adminToken, userID string) error {
+	request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/auth/admin/realms/%s/users/%s", s.KeycloakUrl, realm, userID), nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", adminToken))
+
+	response, err := s.HttpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusNoContent && response.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("unexpected status code %d", response.StatusCode)
+	}
+	return nil
+}
+
+func (s *SandboxController) searchKeycloakUsers(realm, adminToken, search string) ([]KeycloakUserRepresentation, error) {
+	const pageSize = 500
+	var users []KeycloakUserRepresentation
+	for first := 0; ; first += pageSize {
+		request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/auth/admin/realms/%s/users?search=%s&first=%d&max=%d", s.KeycloakUrl, realm, url.QueryEscape(search), first, pageSize), nil)
+		if err != nil {
+			return nil, err
+		}
+		request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", adminToken))
+
+		response, err := s.HttpClient.Do(request)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search keycloak users: %v", err)
+		}
+		var page []KeycloakUserRepresentation
+		if response.StatusCode == http.StatusOK {
+			err = json.NewDecoder(response.Body).Decode(&page)
+		} else {
+			err = fmt.Errorf("failed to search keycloak users, statusCode: %d", response.StatusCode)
+		}
+		response.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, page...)
+		if len(page) < pageSize {
+			return users, nil
+		}
+	}
+}
+
+func (s *SandboxController) getKeycloakAdminToken() (string, error) {
+	adminSecret, err := s.GetKeycloakAdminSecret()
+	if err != nil {
+		return "", err
+	}
+	adminToken, err := s.GetKeycloakTokenSource(DEFAULT_KEYCLOAK_ADMIN_CLIENT_ID, DEFAULT_KEYCLOAK_ADMIN_USERNAME, adminSecret, DEFAULT_KEYCLOAK_MASTER_REALM).KeycloakAuth()
+	if err != nil {
+		return "", err
+	}
+	return adminToken.AccessToken, nil
+}
+
+func hasAnyPrefix(name string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(name, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// parallelize calls f for 0..n-1 with at most concurrency calls running at once
+func parallelize(n, concurrency int, f func(i int)) {
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				f(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
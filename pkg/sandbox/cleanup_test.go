@@ -0,0 +1,95 @@
+package sandbox
+
+import (
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	toolchainApi "github.com/codeready-toolchain/api/api/v1alpha1"
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCleanupUsersDryRun(t *testing.T) {
+	old := time.Now().Add(-48 * time.Hour)
+	keycloak := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/token") {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "token", "expires_in": 300})
+			return
+		}
+		if r.URL.Query().Get("search") != "build-e2e-" {
+			_ = json.NewEncoder(w).Encode([]KeycloakUserRepresentation{})
+			return
+		}
+		_ = json.NewEncoder(w).Encode([]KeycloakUserRepresentation{
+			{ID: "1", Username: "build-e2e-old", CreatedTimestamp: old.UnixMilli()},
+			{ID: "2", Username: "build-e2e-new", CreatedTimestamp: time.Now().UnixMilli()},
+			{ID: "3", Username: "my-build-e2e-old", CreatedTimestamp: old.UnixMilli()},
+		})
+	}))
+	defer keycloak.Close()
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, routev1.AddToScheme(scheme))
+	assert.NoError(t, toolchainApi.AddToScheme(scheme))
+
+	oldSignup := getUserSignupSpecs("build-e2e-old")
+	oldSignup.CreationTimestamp = metav1.NewTime(old)
+	newSignup := getUserSignupSpecs("build-e2e-new")
+	newSignup.CreationTimestamp = metav1.Now()
+	otherSignup := getUserSignupSpecs("developer")
+	otherSignup.CreationTimestamp = metav1.NewTime(old)
+
+	s := &SandboxController{
+		HttpClient: keycloak.Client(),
+		KubeClient: fake.NewSimpleClientset(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: DEFAULT_KEYCLOAK_ADMIN_SECRET, Namespace: DEFAULT_KEYCLOAK_NAMESPACE},
+			Data:       map[string][]byte{SECRET_KEY: []byte("admin")},
+		}),
+		KubeRest: crfake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(
+			&routev1.Route{ObjectMeta: metav1.ObjectMeta{Name: DEFAULT_TOOLCHAIN_INSTANCE_NAME, Namespace: DEFAULT_TOOLCHAIN_NAMESPACE}, Spec: routev1.RouteSpec{Host: "api.example.com"}},
+			&routev1.Route{ObjectMeta: metav1.ObjectMeta{Name: DEFAULT_KEYCLOAK_INSTANCE_NAME, Namespace: DEFAULT_KEYCLOAK_NAMESPACE}, Spec: routev1.RouteSpec{Host: strings.TrimPrefix(keycloak.URL, "https://")}},
+			oldSignup, newSignup, otherSignup,
+		).Build(),
+	}
+
+	report, err := s.CleanupUsers(UserCleanupOptions{OlderThan: 24 * time.Hour, DryRun: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"build-e2e-old"}, report.UserSignups)
+	assert.Equal(t, []string{"build-e2e-old"}, report.KeycloakUsers)
+	assert.Empty(t, report.Errors)
+}
+
+func TestDefaultE2EUserPrefixesCoverSuites(t *testing.T) {
+	// Suites have to name generated users with one of the constants.E2EGeneratedUserPrefixes, so the cleanup finds them
+	generatedUser := regexp.MustCompile(`NewFramework\(utils\.GetGeneratedNamespace\(([^)]*)\)\)`)
+	err := filepath.WalkDir("../../tests", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, m := range generatedUser.FindAllStringSubmatch(string(content), -1) {
+			assert.Regexp(t, `^constants\.[A-Z0-9_]+_USER_PREFIX$`, m[1], "user prefix in %s", path)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+
+	assert.Contains(t, DefaultE2EUserPrefixes, "has-cdq-")
+	assert.Contains(t, DefaultE2EUserPrefixes, "release-e2e-bundle")
+}
@@ -0,0 +1,275 @@
+// Package fakekeycloak provides an in-process stand-in of the Keycloak endpoints used by the sandbox controller,
+// so user provisioning can be tested without a cluster.
+package fakekeycloak
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devfile/library/pkg/util"
+)
+
+const (
+	MasterRealm   = "master"
+	AdminClientID = "admin-cli"
+
+	DefaultTokenLifetime   = 5 * time.Minute
+	DefaultRefreshLifetime = 30 * time.Minute
+)
+
+// User is a keycloak user, as accepted and returned by the admin API
+type User struct {
+	ID               string       `json:"id,omitempty"`
+	FirstName        string       `json:"firstName,omitempty"`
+	LastName         string       `json:"lastName,omitempty"`
+	Username         string       `json:"username"`
+	Enabled          interface{}  `json:"enabled,omitempty"`
+	Email            string       `json:"email,omitempty"`
+	CreatedTimestamp int64        `json:"createdTimestamp,omitempty"`
+	Credentials      []Credential `json:"credentials,omitempty"`
+}
+
+type Credential struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type token struct {
+	realm     string
+	username  string
+	expiresAt time.Time
+}
+
+// Server serves token, refresh and admin users endpoints of the configured realms over TLS
+type Server struct {
+	*httptest.Server
+
+	mu sync.Mutex
+	// Lifetime of issued access and refresh tokens
+	tokenLifetime   time.Duration
+	refreshLifetime time.Duration
+	realms          map[string]map[string]*User
+	passwords       map[string]map[string]string
+	tokens          map[string]token
+	refresh         map[string]token
+	// Number of handled requests per "<method> <endpoint>", e.g. "POST token"
+	requests map[string]int
+}
+
+// NewServer starts a stand-in with the master realm holding an admin user with the given password, and the additional realms
+func NewServer(adminPassword string, realms ...string) *Server {
+	s := &Server{
+		tokenLifetime:   DefaultTokenLifetime,
+		refreshLifetime: DefaultRefreshLifetime,
+		realms:          map[string]map[string]*User{},
+		passwords:       map[string]map[string]string{},
+		tokens:          map[string]token{},
+		refresh:         map[string]token{},
+		requests:        map[string]int{},
+	}
+	for _, r := range append([]string{MasterRealm}, realms...) {
+		s.realms[r] = map[string]*User{}
+		s.passwords[r] = map[string]string{}
+	}
+	s.AddUser(MasterRealm, "admin", adminPassword)
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// AddUser creates a user directly in the realm
+func (s *Server) AddUser(realm, username, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addUser(realm, &User{Username: username}, password)
+}
+
+// SetTokenLifetimes changes lifetimes of the access and refresh tokens issued from now on
+func (s *Server) SetTokenLifetimes(access, refresh time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokenLifetime = access
+	s.refreshLifetime = refresh
+}
+
+// Users returns names of the users in the realm
+func (s *Server) Users(realm string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var names []string
+	for name := range s.realms[realm] {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Requests returns how many times an endpoint was called, e.g. Requests("POST", "token")
+func (s *Server) Requests(method, endpoint string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[method+" "+endpoint]
+}
+
+func (s *Server) addUser(realm string, u *User, password string) {
+	u.ID = util.GenerateRandomString(16)
+	if u.CreatedTimestamp == 0 {
+		u.CreatedTimestamp = time.Now().UnixMilli()
+	}
+	u.Credentials = nil
+	s.realms[realm][u.Username] = u
+	s.passwords[realm][u.Username] = password
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	// auth/realms/{realm}/protocol/openid-connect/token
+	case len(parts) == 6 && parts[0] == "auth" && parts[1] == "realms" && parts[5] == "token" && r.Method == http.MethodPost:
+		s.requests["POST token"]++
+		s.handleToken(w, r, parts[2])
+	// auth/admin/realms/{realm}/users[/{id}]
+	case len(parts) >= 5 && parts[0] == "auth" && parts[1] == "admin" && parts[2] == "realms" && parts[4] == "users":
+		s.requests[r.Method+" users"]++
+		if !s.isAdmin(r) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if _, ok := s.realms[parts[3]]; !ok {
+			writeError(w, http.StatusNotFound, "Realm not found.")
+			return
+		}
+		s.handleUsers(w, r, parts[3], parts[5:])
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request, realm string) {
+	if _, ok := s.realms[realm]; !ok {
+		writeError(w, http.StatusNotFound, "Realm does not exist")
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var username string
+	switch r.Form.Get("grant_type") {
+	case "password":
+		username = r.Form.Get("username")
+		password, ok := s.passwords[realm][username]
+		if !ok || password != r.Form.Get("password") {
+			writeError(w, http.StatusUnauthorized, "Invalid user credentials")
+			return
+		}
+	case "refresh_token":
+		t, ok := s.refresh[r.Form.Get("refresh_token")]
+		if !ok || t.realm != realm || time.Now().After(t.expiresAt) {
+			writeError(w, http.StatusBadRequest, "Invalid refresh token")
+			return
+		}
+		delete(s.refresh, r.Form.Get("refresh_token"))
+		username = t.username
+	default:
+		writeError(w, http.StatusBadRequest, "Unsupported grant_type")
+		return
+	}
+
+	now := time.Now()
+	accessToken := util.GenerateRandomString(32)
+	refreshToken := util.GenerateRandomString(32)
+	s.tokens[accessToken] = token{realm: realm, username: username, expiresAt: now.Add(s.tokenLifetime)}
+	s.refresh[refreshToken] = token{realm: realm, username: username, expiresAt: now.Add(s.refreshLifetime)}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token":       accessToken,
+		"refresh_token":      refreshToken,
+		"token_type":         "Bearer",
+		"expires_in":         int(s.tokenLifetime.Seconds()),
+		"refresh_expires_in": int(s.refreshLifetime.Seconds()),
+	})
+}
+
+func (s *Server) handleUsers(w http.ResponseWriter, r *http.Request, realm string, rest []string) {
+	users := s.realms[realm]
+	switch {
+	case r.Method == http.MethodGet && len(rest) == 0:
+		search := r.URL.Query().Get("search")
+		first, _ := strconv.Atoi(r.URL.Query().Get("first"))
+		max, err := strconv.Atoi(r.URL.Query().Get("max"))
+		if err != nil {
+			max = 100
+		}
+		result := []User{}
+		for _, u := range users {
+			if strings.Contains(u.Username, search) || strings.Contains(u.Email, search) {
+				result = append(result, *u)
+			}
+		}
+		if first > len(result) {
+			first = len(result)
+		}
+		if first+max < len(result) {
+			result = result[first : first+max]
+		} else {
+			result = result[first:]
+		}
+		writeJSON(w, http.StatusOK, result)
+	case r.Method == http.MethodPost && len(rest) == 0:
+		u := &User{}
+		if err := json.NewDecoder(r.Body).Decode(u); err != nil || u.Username == "" {
+			writeError(w, http.StatusBadRequest, "invalid user")
+			return
+		}
+		if _, exists := users[u.Username]; exists {
+			writeError(w, http.StatusConflict, "User exists with same username")
+			return
+		}
+		password := ""
+		for _, c := range u.Credentials {
+			if c.Type == "password" {
+				password = c.Value
+			}
+		}
+		s.addUser(realm, u, password)
+		w.Header().Set("Location", fmt.Sprintf("%s%s/%s", s.URL, r.URL.Path, u.ID))
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodDelete && len(rest) == 1:
+		for name, u := range users {
+			if u.ID == rest[0] {
+				delete(users, name)
+				delete(s.passwords[realm], name)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "User not found")
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// isAdmin checks the request carries a valid token of a master realm user
+func (s *Server) isAdmin(r *http.Request) bool {
+	bearer := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	t, ok := s.tokens[bearer]
+	return ok && t.realm == MasterRealm && time.Now().Before(t.expiresAt)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"strings"
 	"time"
 
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
@@ -26,6 +25,14 @@ type KeycloakAuth struct {
 
 	//refresh token is subject to SSO Session Idle timeout (30mn -default) and SSO Session Max lifespan (10hours-default) whereas offline token never expires
 	RefreshToken string `json:"refresh_token"`
+
+	// Lifetime of the access and refresh tokens in seconds
+	ExpiresIn        int `json:"expires_in"`
+	RefreshExpiresIn int `json:"refresh_expires_in"`
+
+	// Computed from the lifetimes when the token is obtained
+	ExpiresAt        time.Time `json:"-"`
+	RefreshExpiresAt time.Time `json:"-"`
 }
 
 // GetKeycloakToken return a token for admins
@@ -37,24 +44,12 @@ func (k *SandboxController) GetKeycloakToken(clientID string, userName string, p
 		"grant_type": {"password"},
 	}
 
-	request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/auth/realms/%s/protocol/openid-connect/token", k.KeycloakUrl, realm), strings.NewReader(data.Encode()))
-
+	keycloakAuth, err = k.requestKeycloakToken(realm, data)
 	if err != nil {
-		klog.Errorf("failed to get token from keycloak: %v", err)
+		klog.Errorf("failed to get token from keycloak for user %s: %v", userName, err)
 		return nil, err
 	}
-	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-
-	response, err := k.HttpClient.Do(request)
-
-	if err != nil || response.StatusCode != 200 {
-		return nil, fmt.Errorf("failed to get keycloak token, realm: %s, userName: %s, client-id: %s statusCode: %d, url: %s", realm, userName, clientID, response.StatusCode, k.KeycloakUrl)
-	}
-	defer response.Body.Close()
-
-	err = json.NewDecoder(response.Body).Decode(&keycloakAuth)
-
-	return keycloakAuth, err
+	return keycloakAuth, nil
 }
 
 /*
@@ -93,10 +88,13 @@ func (k *SandboxController) RegisterKeyclokUser(userName string, keycloakToken s
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", keycloakToken))
 
 	resp, err := k.HttpClient.Do(req)
-	if err != nil || resp.StatusCode != 201 {
-		return user, fmt.Errorf("failed to create keycloak users. Status code %d", resp.StatusCode)
+	if err != nil {
+		return user, fmt.Errorf("failed to create keycloak user: %v", err)
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return user, fmt.Errorf("failed to create keycloak users. Status code %d", resp.StatusCode)
+	}
 
 	return user, err
 }
@@ -155,24 +153,17 @@ func (s *SandboxController) GetKeycloakAdminSecret() (adminPassword string, err
 	return adminPassword, nil
 }
 
+// KeycloakUserExists returns true if a user with exactly the given username is registered in the realm
 func (s *SandboxController) KeycloakUserExists(realm string, token string, username string) bool {
-	///{realm}/users?username=toto
-	///admin/realms/{my-realm}/users?search={username}
-	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/auth/admin/realms/%s/users?search=%s", s.KeycloakUrl, realm, username), strings.NewReader(""))
-
+	users, err := s.searchKeycloakUsers(realm, token, username)
 	if err != nil {
 		klog.Errorf("failed to get user: %v", err)
 		return false
 	}
-	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-	request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
-
-	response, err := s.HttpClient.Do(request)
-
-	if err != nil || response.StatusCode != 200 {
-		return false
+	for _, u := range users {
+		if u.Username == username {
+			return true
+		}
 	}
-	defer response.Body.Close()
-
-	return true
+	return false
 }
@@ -0,0 +1,157 @@
+package sandbox
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	toolchainApi "github.com/codeready-toolchain/api/api/v1alpha1"
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
+	"github.com/redhat-appstudio/e2e-tests/pkg/sandbox/fakekeycloak"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testAdminPassword = "admin-password"
+
+// newTestSandboxController returns a controller backed by fake kubernetes clients with the toolchain route
+// and keycloak admin secret, talking to the keycloak stand-in
+func newTestSandboxController(t *testing.T, keycloak *fakekeycloak.Server, objects []runtime.Object, kubeObjects ...runtime.Object) *SandboxController {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, routev1.AddToScheme(scheme))
+	assert.NoError(t, toolchainApi.AddToScheme(scheme))
+
+	objects = append(objects, &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{Name: DEFAULT_TOOLCHAIN_INSTANCE_NAME, Namespace: DEFAULT_TOOLCHAIN_NAMESPACE},
+		Spec:       routev1.RouteSpec{Host: "api.example.com"},
+	})
+	kubeObjects = append(kubeObjects, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: DEFAULT_KEYCLOAK_ADMIN_SECRET, Namespace: DEFAULT_KEYCLOAK_NAMESPACE},
+		Data:       map[string][]byte{SECRET_KEY: []byte(testAdminPassword)},
+	})
+
+	s, err := NewDevSandboxController(fake.NewSimpleClientset(kubeObjects...), crfake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build())
+	assert.NoError(t, err)
+	s.UseKeycloak(keycloak.URL, keycloak.Client())
+	return s
+}
+
+func TestKeycloakUsers(t *testing.T) {
+	keycloak := fakekeycloak.NewServer(testAdminPassword, DEFAULT_KEYCLOAK_TESTING_REALM)
+	defer keycloak.Close()
+	s := newTestSandboxController(t, keycloak, nil)
+
+	_, err := s.GetKeycloakToken(DEFAULT_KEYCLOAK_ADMIN_CLIENT_ID, DEFAULT_KEYCLOAK_ADMIN_USERNAME, "wrong", DEFAULT_KEYCLOAK_MASTER_REALM)
+	assert.Error(t, err)
+	adminToken, err := s.GetKeycloakToken(DEFAULT_KEYCLOAK_ADMIN_CLIENT_ID, DEFAULT_KEYCLOAK_ADMIN_USERNAME, testAdminPassword, DEFAULT_KEYCLOAK_MASTER_REALM)
+	assert.NoError(t, err)
+
+	assert.False(t, s.KeycloakUserExists(DEFAULT_KEYCLOAK_TESTING_REALM, adminToken.AccessToken, "user1"))
+	_, err = s.RegisterKeyclokUser("user1", adminToken.AccessToken, DEFAULT_KEYCLOAK_TESTING_REALM)
+	assert.NoError(t, err)
+	assert.True(t, s.KeycloakUserExists(DEFAULT_KEYCLOAK_TESTING_REALM, adminToken.AccessToken, "user1"))
+	assert.False(t, s.KeycloakUserExists(DEFAULT_KEYCLOAK_TESTING_REALM, adminToken.AccessToken, "user"))
+
+	_, err = s.RegisterKeyclokUser("user1", adminToken.AccessToken, DEFAULT_KEYCLOAK_TESTING_REALM)
+	assert.Error(t, err, "registering the same user twice should fail")
+	_, err = s.RegisterKeyclokUser("user2", adminToken.AccessToken, "unknown-realm")
+	assert.Error(t, err)
+
+	// Registered users log in with their name as a password
+	userToken, err := s.GetKeycloakToken(DEFAULT_KEYCLOAK_TEST_CLIENT_ID, "user1", "user1", DEFAULT_KEYCLOAK_TESTING_REALM)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, userToken.AccessToken)
+	assert.False(t, s.KeycloakUserExists(DEFAULT_KEYCLOAK_TESTING_REALM, userToken.AccessToken, "user1"), "a non-admin token can't use the admin API")
+}
+
+func TestReconcileUserCreation(t *testing.T) {
+	keycloak := fakekeycloak.NewServer(testAdminPassword, DEFAULT_KEYCLOAK_TESTING_REALM)
+	defer keycloak.Close()
+	s := newTestSandboxController(t, keycloak, nil)
+
+	kubeconfigPath := filepath.Join(t.TempDir(), "user.kubeconfig")
+	t.Setenv(constants.USER_USER_KUBE_CONFIG_PATH_ENV, kubeconfigPath)
+
+	// Act as the host operator, which approves the UserSignup
+	done := make(chan struct{})
+	defer close(done)
+	go completeUserSignup(s, "new-user", done)
+
+	authInfo, err := s.ReconcileUserCreation("new-user")
+	assert.NoError(t, err)
+	assert.Equal(t, "new-user", authInfo.UserName)
+	assert.Equal(t, []string{"new-user"}, keycloak.Users(DEFAULT_KEYCLOAK_TESTING_REALM))
+
+	kubeconfig, err := os.ReadFile(kubeconfigPath)
+	assert.NoError(t, err)
+	assert.True(t, strings.Contains(string(kubeconfig), "https://api.example.com"))
+
+	// Reconciling an existing user doesn't register it again
+	_, err = s.ReconcileUserCreation("new-user")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, keycloak.Requests("POST", "users"))
+}
+
+func TestKeycloakTokenSourceRefresh(t *testing.T) {
+	keycloak := fakekeycloak.NewServer(testAdminPassword, DEFAULT_KEYCLOAK_TESTING_REALM)
+	defer keycloak.Close()
+	keycloak.AddUser(DEFAULT_KEYCLOAK_TESTING_REALM, "user", "user")
+	s := newTestSandboxController(t, keycloak, nil)
+
+	// Access tokens expire within the refresh skew, so every call renews them with the refresh token
+	keycloak.SetTokenLifetimes(time.Second, fakekeycloak.DefaultRefreshLifetime)
+	ts := s.GetKeycloakTokenSource(DEFAULT_KEYCLOAK_TEST_CLIENT_ID, "user", "user", DEFAULT_KEYCLOAK_TESTING_REALM)
+	first, err := ts.Token()
+	assert.NoError(t, err)
+	refreshed, err := ts.Token()
+	assert.NoError(t, err)
+	assert.NotEqual(t, first.AccessToken, refreshed.AccessToken)
+
+	assert.Equal(t, KeycloakTokenMetrics{Issued: 1, Refreshed: 1}, s.GetKeycloakTokenMetrics())
+	assert.Equal(t, 2, keycloak.Requests("POST", "token"))
+}
+
+func TestCleanupUsersDeletesKeycloakUsers(t *testing.T) {
+	keycloak := fakekeycloak.NewServer(testAdminPassword, DEFAULT_KEYCLOAK_TESTING_REALM)
+	defer keycloak.Close()
+	for _, name := range []string{"build-e2e-old", "developer"} {
+		keycloak.AddUser(DEFAULT_KEYCLOAK_TESTING_REALM, name, name)
+	}
+	us := getUserSignupSpecs("build-e2e-old")
+	us.CreationTimestamp = metav1.NewTime(time.Now().Add(-48 * time.Hour))
+	s := newTestSandboxController(t, keycloak, []runtime.Object{us})
+
+	report, err := s.CleanupUsers(UserCleanupOptions{Prefixes: []string{"build-e2e-"}})
+	assert.NoError(t, err)
+	assert.Empty(t, report.Errors)
+	assert.Equal(t, []string{"build-e2e-old"}, report.UserSignups)
+	assert.Equal(t, []string{"build-e2e-old"}, report.KeycloakUsers)
+	assert.Equal(t, []string{"developer"}, keycloak.Users(DEFAULT_KEYCLOAK_TESTING_REALM))
+}
+
+func completeUserSignup(s *SandboxController, name string, done chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case <-time.After(100 * time.Millisecond):
+		}
+		us := &toolchainApi.UserSignup{}
+		if err := s.KubeRest.Get(context.Background(), types.NamespacedName{Name: name, Namespace: DEFAULT_TOOLCHAIN_NAMESPACE}, us); err != nil {
+			continue
+		}
+		us.Status.Conditions = []toolchainApi.Condition{{Type: toolchainApi.UserSignupComplete, Status: corev1.ConditionTrue}}
+		if err := s.KubeRest.Update(context.Background(), us); err == nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,354 @@
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	toolchainApi "github.com/codeready-toolchain/api/api/v1alpha1"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+const userLifecycleTimeout = 5 * time.Minute
+
+// lifecycleStates are the UserSignup states which are mutually exclusive when driving a user through its lifecycle
+var lifecycleStates = []toolchainApi.UserSignupState{
+	toolchainApi.UserSignupStateApproved,
+	toolchainApi.UserSignupStateVerificationRequired,
+	toolchainApi.UserSignupStateDeactivating,
+	toolchainApi.UserSignupStateDeactivated,
+	toolchainApi.UserSignupStateBanned,
+}
+
+// GetUserSignup returns the UserSignup of a given user from the toolchain host namespace
+func (s *SandboxController) GetUserSignup(userName string) (*toolchainApi.UserSignup, error) {
+	userSignup := &toolchainApi.UserSignup{}
+	if err := s.KubeRest.Get(context.TODO(), types.NamespacedName{Namespace: DEFAULT_TOOLCHAIN_NAMESPACE, Name: userName}, userSignup); err != nil {
+		return nil, err
+	}
+	return userSignup, nil
+}
+
+// GetMasterUserRecord returns the MasterUserRecord with a given name from the toolchain host namespace
+func (s *SandboxController) GetMasterUserRecord(name string) (*toolchainApi.MasterUserRecord, error) {
+	mur := &toolchainApi.MasterUserRecord{}
+	if err := s.KubeRest.Get(context.TODO(), types.NamespacedName{Namespace: DEFAULT_TOOLCHAIN_NAMESPACE, Name: name}, mur); err != nil {
+		return nil, err
+	}
+	return mur, nil
+}
+
+// GetSpace returns the Space with a given name from the toolchain host namespace
+func (s *SandboxController) GetSpace(name string) (*toolchainApi.Space, error) {
+	space := &toolchainApi.Space{}
+	if err := s.KubeRest.Get(context.TODO(), types.NamespacedName{Namespace: DEFAULT_TOOLCHAIN_NAMESPACE, Name: name}, space); err != nil {
+		return nil, err
+	}
+	return space, nil
+}
+
+// SetUserSignupState replaces the lifecycle state (approved, verification-required, deactivating, deactivated, banned)
+// of a UserSignup with the given one, keeping any other state untouched
+func (s *SandboxController) SetUserSignupState(userName string, state toolchainApi.UserSignupState) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		userSignup, err := s.GetUserSignup(userName)
+		if err != nil {
+			return err
+		}
+		userSignup.Spec.States = withLifecycleState(userSignup.Spec.States, state)
+		return s.KubeRest.Update(context.TODO(), userSignup)
+	})
+}
+
+// DeactivateUser deactivates a user and waits until the toolchain removes its MasterUserRecord and Space
+func (s *SandboxController) DeactivateUser(userName string) error {
+	compliantUserName, err := s.getCompliantUserName(userName)
+	if err != nil {
+		return err
+	}
+	klog.Infof("deactivating user %s", userName)
+	if err := s.SetUserSignupState(userName, toolchainApi.UserSignupStateDeactivated); err != nil {
+		return fmt.Errorf("error when deactivating user %s: %v", userName, err)
+	}
+	if err := s.WaitForUserSignupReason(userName, toolchainApi.UserSignupUserDeactivatedReason); err != nil {
+		return err
+	}
+	if err := s.WaitForMasterUserRecordDeleted(compliantUserName); err != nil {
+		return err
+	}
+	return s.WaitForSpaceDeleted(compliantUserName)
+}
+
+// ReactivateUser approves a deactivated (or verification-required) user again and waits until it is provisioned
+func (s *SandboxController) ReactivateUser(userName string) error {
+	klog.Infof("reactivating user %s", userName)
+	if err := s.SetUserSignupState(userName, toolchainApi.UserSignupStateApproved); err != nil {
+		return fmt.Errorf("error when reactivating user %s: %v", userName, err)
+	}
+	return s.WaitForUserProvisioned(userName)
+}
+
+// RequireVerification puts a user into the verification-required state and waits until the toolchain reflects it
+func (s *SandboxController) RequireVerification(userName string) error {
+	klog.Infof("requiring verification of user %s", userName)
+	if err := s.SetUserSignupState(userName, toolchainApi.UserSignupStateVerificationRequired); err != nil {
+		return fmt.Errorf("error when requiring verification of user %s: %v", userName, err)
+	}
+	return s.WaitForUserSignupReason(userName, toolchainApi.UserSignupVerificationRequiredReason)
+}
+
+// BanUser creates a BannedUser for the email of a given user and waits until the toolchain removes its MasterUserRecord and Space
+func (s *SandboxController) BanUser(userName string) error {
+	userSignup, err := s.GetUserSignup(userName)
+	if err != nil {
+		return fmt.Errorf("error when getting UserSignup %s: %v", userName, err)
+	}
+	email := userSignup.Annotations[toolchainApi.UserSignupUserEmailAnnotationKey]
+	if email == "" {
+		return fmt.Errorf("UserSignup %s has no %s annotation", userName, toolchainApi.UserSignupUserEmailAnnotationKey)
+	}
+
+	klog.Infof("banning user %s", userName)
+	if err := s.KubeRest.Create(context.TODO(), getBannedUserSpecs(userName, email)); err != nil && !k8sErrors.IsAlreadyExists(err) {
+		return fmt.Errorf("error when banning user %s: %v", userName, err)
+	}
+	if err := s.WaitForUserSignupReason(userName, toolchainApi.UserSignupUserBannedReason); err != nil {
+		return err
+	}
+	if userSignup.Status.CompliantUsername == "" {
+		return nil
+	}
+	if err := s.WaitForMasterUserRecordDeleted(userSignup.Status.CompliantUsername); err != nil {
+		return err
+	}
+	return s.WaitForSpaceDeleted(userSignup.Status.CompliantUsername)
+}
+
+// UnbanUser deletes the BannedUser of a given user. The user needs to be reactivated afterwards to get provisioned again
+func (s *SandboxController) UnbanUser(userName string) error {
+	bannedUser := &toolchainApi.BannedUser{ObjectMeta: metav1.ObjectMeta{Name: userName, Namespace: DEFAULT_TOOLCHAIN_NAMESPACE}}
+	if err := s.KubeRest.Delete(context.TODO(), bannedUser); err != nil && !k8sErrors.IsNotFound(err) {
+		return fmt.Errorf("error when unbanning user %s: %v", userName, err)
+	}
+	return nil
+}
+
+// ChangeUserTier moves a user to a different tier through a ChangeTierRequest and waits until its Space is ready in the new tier
+func (s *SandboxController) ChangeUserTier(userName, tierName string) error {
+	compliantUserName, err := s.getCompliantUserName(userName)
+	if err != nil {
+		return err
+	}
+
+	klog.Infof("changing tier of user %s to %s", userName, tierName)
+	changeTierRequest := &toolchainApi.ChangeTierRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-", compliantUserName),
+			Namespace:    DEFAULT_TOOLCHAIN_NAMESPACE,
+		},
+		Spec: toolchainApi.ChangeTierRequestSpec{
+			MurName:  compliantUserName,
+			TierName: tierName,
+		},
+	}
+	if err := s.KubeRest.Create(context.TODO(), changeTierRequest); err != nil {
+		return fmt.Errorf("error when creating ChangeTierRequest for user %s: %v", userName, err)
+	}
+
+	err = utils.WaitUntil(func() (done bool, err error) {
+		if err := s.KubeRest.Get(context.TODO(), types.NamespacedName{Namespace: DEFAULT_TOOLCHAIN_NAMESPACE, Name: changeTierRequest.Name}, changeTierRequest); err != nil {
+			return false, err
+		}
+		condition, found := findCondition(changeTierRequest.Status.Conditions, toolchainApi.ChangeTierRequestComplete)
+		if found && condition.Status == corev1.ConditionFalse && condition.Reason == toolchainApi.ChangeTierRequestChangeFailedReason {
+			return false, fmt.Errorf("ChangeTierRequest %s failed: %s", changeTierRequest.Name, condition.Message)
+		}
+		return found && condition.Status == corev1.ConditionTrue, nil
+	}, userLifecycleTimeout)
+	if err != nil {
+		return fmt.Errorf("error when waiting for ChangeTierRequest %s to complete: %v", changeTierRequest.Name, err)
+	}
+	return s.WaitForSpaceTier(compliantUserName, tierName)
+}
+
+// WaitForUserSignupReason waits until the Complete condition of a UserSignup has the given reason
+func (s *SandboxController) WaitForUserSignupReason(userName, reason string) error {
+	err := utils.WaitUntil(func() (done bool, err error) {
+		userSignup, err := s.GetUserSignup(userName)
+		if err != nil {
+			return false, err
+		}
+		condition, found := findCondition(userSignup.Status.Conditions, toolchainApi.UserSignupComplete)
+		return found && condition.Reason == reason, nil
+	}, userLifecycleTimeout)
+	if err != nil {
+		return fmt.Errorf("error when waiting for UserSignup %s to get to %s: %v", userName, reason, err)
+	}
+	return nil
+}
+
+// WaitForUserProvisioned waits until the UserSignup is complete and both its MasterUserRecord and Space are ready
+func (s *SandboxController) WaitForUserProvisioned(userName string) error {
+	var userSignup *toolchainApi.UserSignup
+	err := utils.WaitUntil(func() (done bool, err error) {
+		userSignup, err = s.GetUserSignup(userName)
+		if err != nil {
+			return false, err
+		}
+		condition, found := findCondition(userSignup.Status.Conditions, toolchainApi.UserSignupComplete)
+		return found && condition.Status == corev1.ConditionTrue && userSignup.Status.CompliantUsername != "", nil
+	}, userLifecycleTimeout)
+	if err != nil {
+		return fmt.Errorf("error when waiting for UserSignup %s to complete: %v", userName, err)
+	}
+	if err := s.WaitForMasterUserRecordReady(userSignup.Status.CompliantUsername); err != nil {
+		return err
+	}
+	return s.WaitForSpaceTier(userSignup.Status.CompliantUsername, "")
+}
+
+// WaitForMasterUserRecordReady waits until the MasterUserRecord exists and is ready
+func (s *SandboxController) WaitForMasterUserRecordReady(name string) error {
+	err := utils.WaitUntil(func() (done bool, err error) {
+		mur, err := s.GetMasterUserRecord(name)
+		if err != nil {
+			if k8sErrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		condition, found := findCondition(mur.Status.Conditions, toolchainApi.MasterUserRecordReady)
+		return found && condition.Status == corev1.ConditionTrue, nil
+	}, userLifecycleTimeout)
+	if err != nil {
+		return fmt.Errorf("error when waiting for MasterUserRecord %s to be ready: %v", name, err)
+	}
+	return nil
+}
+
+// WaitForMasterUserRecordDeleted waits until the MasterUserRecord doesn't exist anymore
+func (s *SandboxController) WaitForMasterUserRecordDeleted(name string) error {
+	err := utils.WaitUntil(func() (done bool, err error) {
+		_, err = s.GetMasterUserRecord(name)
+		if k8sErrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}, userLifecycleTimeout)
+	if err != nil {
+		return fmt.Errorf("error when waiting for MasterUserRecord %s to be deleted: %v", name, err)
+	}
+	return nil
+}
+
+// WaitForSpaceTier waits until the Space is ready in the given tier. An empty tier name matches any tier
+func (s *SandboxController) WaitForSpaceTier(name, tierName string) error {
+	err := utils.WaitUntil(func() (done bool, err error) {
+		space, err := s.GetSpace(name)
+		if err != nil {
+			if k8sErrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		if tierName != "" && space.Spec.TierName != tierName {
+			return false, nil
+		}
+		condition, found := findCondition(space.Status.Conditions, toolchainApi.ConditionReady)
+		return found && condition.Status == corev1.ConditionTrue, nil
+	}, userLifecycleTimeout)
+	if err != nil {
+		return fmt.Errorf("error when waiting for Space %s to be ready in tier %q: %v", name, tierName, err)
+	}
+	return nil
+}
+
+// WaitForSpaceDeleted waits until the Space doesn't exist anymore
+func (s *SandboxController) WaitForSpaceDeleted(name string) error {
+	err := utils.WaitUntil(func() (done bool, err error) {
+		_, err = s.GetSpace(name)
+		if k8sErrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}, userLifecycleTimeout)
+	if err != nil {
+		return fmt.Errorf("error when waiting for Space %s to be deleted: %v", name, err)
+	}
+	return nil
+}
+
+// WaitForUserNamespaceDeleted waits until the given namespace of a user is removed, e.g. after deactivation or banning
+func (s *SandboxController) WaitForUserNamespaceDeleted(namespace string) error {
+	err := utils.WaitUntil(func() (done bool, err error) {
+		_, err = s.KubeClient.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+		if k8sErrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}, userLifecycleTimeout)
+	if err != nil {
+		return fmt.Errorf("error when waiting for namespace %s to be deleted: %v", namespace, err)
+	}
+	return nil
+}
+
+func (s *SandboxController) getCompliantUserName(userName string) (string, error) {
+	userSignup, err := s.GetUserSignup(userName)
+	if err != nil {
+		return "", fmt.Errorf("error when getting UserSignup %s: %v", userName, err)
+	}
+	if userSignup.Status.CompliantUsername == "" {
+		return "", fmt.Errorf("UserSignup %s is not provisioned yet", userName)
+	}
+	return userSignup.Status.CompliantUsername, nil
+}
+
+func getBannedUserSpecs(userName, email string) *toolchainApi.BannedUser {
+	return &toolchainApi.BannedUser{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      userName,
+			Namespace: DEFAULT_TOOLCHAIN_NAMESPACE,
+			Labels: map[string]string{
+				toolchainApi.BannedUserEmailHashLabelKey: calcMd5(email),
+			},
+		},
+		Spec: toolchainApi.BannedUserSpec{
+			Email: email,
+		},
+	}
+}
+
+func withLifecycleState(states []toolchainApi.UserSignupState, state toolchainApi.UserSignupState) []toolchainApi.UserSignupState {
+	result := []toolchainApi.UserSignupState{}
+	for _, s := range states {
+		if !isLifecycleState(s) {
+			result = append(result, s)
+		}
+	}
+	return append(result, state)
+}
+
+func isLifecycleState(state toolchainApi.UserSignupState) bool {
+	for _, s := range lifecycleStates {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+func findCondition(conditions []toolchainApi.Condition, conditionType toolchainApi.ConditionType) (toolchainApi.Condition, bool) {
+	for _, c := range conditions {
+		if c.Type == conditionType {
+			return c, true
+		}
+	}
+	return toolchainApi.Condition{}, false
+}
@@ -0,0 +1,106 @@
+package sandbox
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	toolchainApi "github.com/codeready-toolchain/api/api/v1alpha1"
+	"github.com/redhat-appstudio/e2e-tests/pkg/sandbox/fakekeycloak"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestWithLifecycleState(t *testing.T) {
+	states := withLifecycleState([]toolchainApi.UserSignupState{"custom", toolchainApi.UserSignupStateApproved}, toolchainApi.UserSignupStateDeactivated)
+	assert.Equal(t, []toolchainApi.UserSignupState{"custom", toolchainApi.UserSignupStateDeactivated}, states)
+
+	states = withLifecycleState(nil, toolchainApi.UserSignupStateApproved)
+	assert.Equal(t, []toolchainApi.UserSignupState{toolchainApi.UserSignupStateApproved}, states)
+}
+
+func TestDeactivateAndBanUser(t *testing.T) {
+	keycloak := fakekeycloak.NewServer(testAdminPassword, DEFAULT_KEYCLOAK_TESTING_REALM)
+	defer keycloak.Close()
+	s := newTestSandboxController(t, keycloak, provisionedUserObjects("user1"))
+
+	// Act as the host operator, which deprovisions the user once it is deactivated
+	done := make(chan struct{})
+	defer close(done)
+	go deprovisionUserSignup(s, "user1", toolchainApi.UserSignupUserDeactivatedReason, done)
+
+	assert.NoError(t, s.DeactivateUser("user1"))
+	userSignup, err := s.GetUserSignup("user1")
+	assert.NoError(t, err)
+	assert.Equal(t, []toolchainApi.UserSignupState{toolchainApi.UserSignupStateDeactivated}, userSignup.Spec.States)
+
+	s = newTestSandboxController(t, keycloak, provisionedUserObjects("user2"))
+	go deprovisionUserSignup(s, "user2", toolchainApi.UserSignupUserBannedReason, done)
+
+	assert.NoError(t, s.BanUser("user2"))
+	bannedUser := &toolchainApi.BannedUser{}
+	assert.NoError(t, s.KubeRest.Get(context.Background(), types.NamespacedName{Name: "user2", Namespace: DEFAULT_TOOLCHAIN_NAMESPACE}, bannedUser))
+	assert.Equal(t, "user2@user.us", bannedUser.Spec.Email)
+	assert.Equal(t, calcMd5("user2@user.us"), bannedUser.Labels[toolchainApi.BannedUserEmailHashLabelKey])
+
+	assert.NoError(t, s.UnbanUser("user2"))
+	assert.NoError(t, s.UnbanUser("user2"), "unbanning a user twice shouldn't fail")
+}
+
+func provisionedUserObjects(name string) []runtime.Object {
+	ready := []toolchainApi.Condition{{Type: toolchainApi.ConditionReady, Status: corev1.ConditionTrue}}
+	userSignup := getUserSignupSpecs(name)
+	userSignup.Status = toolchainApi.UserSignupStatus{
+		CompliantUsername: name,
+		Conditions:        []toolchainApi.Condition{{Type: toolchainApi.UserSignupComplete, Status: corev1.ConditionTrue}},
+	}
+	return []runtime.Object{
+		userSignup,
+		&toolchainApi.MasterUserRecord{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: DEFAULT_TOOLCHAIN_NAMESPACE},
+			Status:     toolchainApi.MasterUserRecordStatus{Conditions: ready},
+		},
+		&toolchainApi.Space{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: DEFAULT_TOOLCHAIN_NAMESPACE},
+			Spec:       toolchainApi.SpaceSpec{TierName: "appstudio"},
+			Status:     toolchainApi.SpaceStatus{Conditions: ready},
+		},
+	}
+}
+
+func deprovisionUserSignup(s *SandboxController, name, reason string, done chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case <-time.After(100 * time.Millisecond):
+		}
+		userSignup, err := s.GetUserSignup(name)
+		if err != nil {
+			continue
+		}
+		if reason == toolchainApi.UserSignupUserDeactivatedReason && !isInState(userSignup, toolchainApi.UserSignupStateDeactivated) {
+			continue
+		}
+		userSignup.Status.Conditions = []toolchainApi.Condition{{Type: toolchainApi.UserSignupComplete, Status: corev1.ConditionTrue, Reason: reason}}
+		if err := s.KubeRest.Update(context.Background(), userSignup); err != nil {
+			continue
+		}
+		meta := metav1.ObjectMeta{Name: name, Namespace: DEFAULT_TOOLCHAIN_NAMESPACE}
+		_ = s.KubeRest.Delete(context.Background(), &toolchainApi.MasterUserRecord{ObjectMeta: meta})
+		_ = s.KubeRest.Delete(context.Background(), &toolchainApi.Space{ObjectMeta: meta})
+		return
+	}
+}
+
+func isInState(userSignup *toolchainApi.UserSignup, state toolchainApi.UserSignupState) bool {
+	for _, s := range userSignup.Spec.States {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
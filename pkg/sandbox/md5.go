@@ -0,0 +1,13 @@
+package sandbox
+
+import (
+	"crypto/md5" //nolint:gosec
+	"encoding/hex"
+)
+
+// calcMd5 returns the hex-encoded md5 hash of the value, as the host operator does for email hash labels
+func calcMd5(value string) string {
+	md5hash := md5.New() //nolint:gosec
+	_, _ = md5hash.Write([]byte(value))
+	return hex.EncodeToString(md5hash.Sum(nil))
+}
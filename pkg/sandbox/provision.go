@@ -0,0 +1,111 @@
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const userNamespaceTimeout = 5 * time.Minute
+
+// ProvisionedUser is the result of provisioning a single user
+type ProvisionedUser struct {
+	// Index of the user within the provisioned set, starting from 1
+	Index     int
+	UserName  string
+	Namespace string
+	AuthInfo  *SandboxUserAuthInfo
+	// How long it took to provision the user, including waiting for the namespace
+	Duration time.Duration
+	Err      error
+}
+
+// GetProvisionedUserName returns the name of the n-th user provisioned with the prefix
+func GetProvisionedUserName(prefix string, index int) string {
+	return fmt.Sprintf("%s-%04d", prefix, index)
+}
+
+// ProvisionUsers registers <prefix>-0001 .. <prefix>-<count> users in keycloak and sandbox and waits for their
+// namespaces, with at most concurrency users provisioned at once. Users which already exist are reused, so the
+// function can be re-run after a partial failure. Results are ordered by the user index.
+func (s *SandboxController) ProvisionUsers(prefix string, count, concurrency int) []ProvisionedUser {
+	results := make([]ProvisionedUser, count)
+	for u := range s.ProvisionUsersAsync(prefix, count, concurrency) {
+		results[u.Index-1] = u
+	}
+	return results
+}
+
+// ProvisionUsersAsync works as ProvisionUsers, but sends every user to the returned channel as soon as it's provisioned
+// (or failed), so callers can start working with the users before all of them are ready. The channel is closed at the end.
+func (s *SandboxController) ProvisionUsersAsync(prefix string, count, concurrency int) <-chan ProvisionedUser {
+	results := make(chan ProvisionedUser, count)
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	toolchainApiUrl, err := s.initRoutes()
+	if err != nil {
+		for i := 1; i <= count; i++ {
+			results <- ProvisionedUser{Index: i, UserName: GetProvisionedUserName(prefix, i), Err: err}
+		}
+		close(results)
+		return results
+	}
+
+	wd, _ := os.Getwd()
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results <- s.provisionUser(toolchainApiUrl, GetProvisionedUserName(prefix, i), i, wd)
+			}
+		}()
+	}
+	go func() {
+		for i := 1; i <= count; i++ {
+			indexes <- i
+		}
+		close(indexes)
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
+func (s *SandboxController) provisionUser(toolchainApiUrl, userName string, index int, wd string) ProvisionedUser {
+	start := time.Now()
+	u := ProvisionedUser{Index: index, UserName: userName, Namespace: fmt.Sprintf("%s-tenant", userName)}
+
+	u.AuthInfo, u.Err = s.reconcileUserCreation(toolchainApiUrl, userName, fmt.Sprintf("%s/tmp/%s.kubeconfig", wd, userName))
+	if u.Err == nil {
+		u.Err = s.waitForNamespace(u.Namespace)
+	}
+	if u.Err != nil {
+		u.Err = fmt.Errorf("failed to provision user %s: %v", userName, u.Err)
+	}
+	u.Duration = time.Since(start)
+	return u
+}
+
+func (s *SandboxController) waitForNamespace(namespace string) error {
+	return utils.WaitUntil(func() (done bool, err error) {
+		_, err = s.KubeClient.CoreV1().Namespaces().Get(context.Background(), namespace, metav1.GetOptions{})
+		if err != nil {
+			if k8sErrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	}, userNamespaceTimeout)
+}
@@ -0,0 +1,59 @@
+package sandbox
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	toolchainApi "github.com/codeready-toolchain/api/api/v1alpha1"
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestProvisionUsersReusesExistingUsers(t *testing.T) {
+	keycloak := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "token", "expires_in": 300})
+	}))
+	defer keycloak.Close()
+
+	wd, _ := os.Getwd()
+	assert.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { _ = os.Chdir(wd) }()
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, routev1.AddToScheme(scheme))
+	assert.NoError(t, toolchainApi.AddToScheme(scheme))
+
+	objects := []runtime.Object{
+		&routev1.Route{ObjectMeta: metav1.ObjectMeta{Name: DEFAULT_TOOLCHAIN_INSTANCE_NAME, Namespace: DEFAULT_TOOLCHAIN_NAMESPACE}, Spec: routev1.RouteSpec{Host: "api.example.com"}},
+		&routev1.Route{ObjectMeta: metav1.ObjectMeta{Name: DEFAULT_KEYCLOAK_INSTANCE_NAME, Namespace: DEFAULT_KEYCLOAK_NAMESPACE}, Spec: routev1.RouteSpec{Host: strings.TrimPrefix(keycloak.URL, "https://")}},
+	}
+	var namespaces []runtime.Object
+	for i := 1; i <= 3; i++ {
+		objects = append(objects, getUserSignupSpecs(GetProvisionedUserName("e2e", i)))
+		namespaces = append(namespaces, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: GetProvisionedUserName("e2e", i) + "-tenant"}})
+	}
+
+	s := &SandboxController{
+		HttpClient: keycloak.Client(),
+		KubeClient: fake.NewSimpleClientset(namespaces...),
+		KubeRest:   crfake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build(),
+	}
+
+	users := s.ProvisionUsers("e2e", 3, 2)
+	assert.Len(t, users, 3)
+	for i, u := range users {
+		assert.NoError(t, u.Err)
+		assert.Equal(t, i+1, u.Index)
+		assert.Equal(t, GetProvisionedUserName("e2e", i+1)+"-tenant", u.Namespace)
+		assert.FileExists(t, u.AuthInfo.KubeconfigPath)
+	}
+}
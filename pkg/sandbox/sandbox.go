@@ -7,10 +7,10 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"sync"
 	"time"
 
 	toolchainApi "github.com/codeready-toolchain/api/api/v1alpha1"
-	"github.com/codeready-toolchain/toolchain-e2e/testsupport/md5"
 	routev1 "github.com/openshift/api/route/v1"
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
@@ -58,6 +58,14 @@ type SandboxController struct {
 
 	// Wrapper of valid kubernetes with admin access to the cluster
 	KubeRest crclient.Client
+
+	// Set when KeycloakUrl points to an external (or stand-in) keycloak, instead of the dev-sso route
+	keycloakOverridden bool
+
+	// Cached keycloak tokens per realm, client and user
+	tokens       map[string]*KeycloakTokenSource
+	tokensMu     sync.Mutex
+	tokenMetrics KeycloakTokenMetrics
 }
 
 // Return specs to authenticate with toolchain proxy
@@ -67,6 +75,9 @@ type SandboxUserAuthInfo struct {
 
 	// Add a description about kubeconfigpath
 	KubeconfigPath string
+
+	// Source of valid user tokens. The token stored in the kubeconfig expires, clients should use this token source instead
+	TokenSource *KeycloakTokenSource
 }
 
 // Values to create a valid user for testing purposes
@@ -113,23 +124,49 @@ func NewDevSandboxController(kube kubernetes.Interface, kubeRest crclient.Client
 
 // ReconcileUserCreation create a user in sandbox and return a valid kubeconfig for user to be used for the tests
 func (s *SandboxController) ReconcileUserCreation(userName string) (*SandboxUserAuthInfo, error) {
-	userSignup := &toolchainApi.UserSignup{}
 	wd, err := os.Getwd()
 	if err != nil {
 		return nil, err
 	}
 	kubeconfigPath := utils.GetEnv(constants.USER_USER_KUBE_CONFIG_PATH_ENV, fmt.Sprintf("%s/tmp/%s.kubeconfig", wd, userName))
 
-	toolchainApiUrl, err := s.GetOpenshiftRouteHost(DEFAULT_TOOLCHAIN_NAMESPACE, DEFAULT_TOOLCHAIN_INSTANCE_NAME)
+	toolchainApiUrl, err := s.initRoutes()
 	if err != nil {
 		return nil, err
 	}
 
+	return s.reconcileUserCreation(toolchainApiUrl, userName, kubeconfigPath)
+}
+
+// initRoutes sets the keycloak url and returns the url of the toolchain api (proxy)
+func (s *SandboxController) initRoutes() (string, error) {
+	toolchainApiUrl, err := s.GetOpenshiftRouteHost(DEFAULT_TOOLCHAIN_NAMESPACE, DEFAULT_TOOLCHAIN_INSTANCE_NAME)
+	if err != nil {
+		return "", err
+	}
+
+	if s.keycloakOverridden {
+		return toolchainApiUrl, nil
+	}
 	if s.KeycloakUrl, err = s.GetOpenshiftRouteHost(DEFAULT_KEYCLOAK_NAMESPACE, DEFAULT_KEYCLOAK_INSTANCE_NAME); err != nil {
-		return nil, err
+		return "", err
 	}
+	return toolchainApiUrl, nil
+}
 
-	err = s.KubeRest.Get(context.Background(), types.NamespacedName{
+// UseKeycloak points the controller to a keycloak instance (e.g. fakekeycloak.Server) instead of the one deployed
+// in the dev-sso namespace. The http client is used for all keycloak calls, if not nil.
+func (s *SandboxController) UseKeycloak(keycloakUrl string, httpClient *http.Client) {
+	s.KeycloakUrl = keycloakUrl
+	s.keycloakOverridden = true
+	if httpClient != nil {
+		s.HttpClient = httpClient
+	}
+}
+
+func (s *SandboxController) reconcileUserCreation(toolchainApiUrl, userName, kubeconfigPath string) (*SandboxUserAuthInfo, error) {
+	userSignup := &toolchainApi.UserSignup{}
+	err := s.KubeRest.Get(context.Background(), types.NamespacedName{
 		Name:      userName,
 		Namespace: DEFAULT_TOOLCHAIN_NAMESPACE,
 	}, userSignup)
@@ -138,34 +175,26 @@ func (s *SandboxController) ReconcileUserCreation(userName string) (*SandboxUser
 			klog.Infof("user %s don't exists... recreating", userName)
 		}
 	} else {
-		userToken, err := s.GetKeycloakToken(DEFAULT_KEYCLOAK_TEST_CLIENT_ID, userName, userName, DEFAULT_KEYCLOAK_TESTING_REALM)
-		if err != nil {
-			return nil, err
-		}
 		if err := s.RegisterSandboxUser(userName); err != nil {
 			return nil, err
 		}
 
-		return s.GetKubeconfigPathForSpecificUser(toolchainApiUrl, userName, kubeconfigPath, userToken)
+		return s.getUserAuthInfo(toolchainApiUrl, userName, kubeconfigPath)
 	}
 
-	if err := s.IsKeycloakRunning(); err != nil {
-		return nil, err
-	}
-
-	adminSecret, err := s.GetKeycloakAdminSecret()
-	if err != nil {
-		return nil, err
+	if !s.keycloakOverridden {
+		if err := s.IsKeycloakRunning(); err != nil {
+			return nil, err
+		}
 	}
 
-	adminToken, err := s.GetKeycloakToken(DEFAULT_KEYCLOAK_ADMIN_CLIENT_ID, DEFAULT_KEYCLOAK_ADMIN_USERNAME, adminSecret, DEFAULT_KEYCLOAK_MASTER_REALM)
+	adminToken, err := s.getKeycloakAdminToken()
 	if err != nil {
 		return nil, err
 	}
 
-	if s.KeycloakUserExists(DEFAULT_KEYCLOAK_TESTING_REALM, adminToken.AccessToken, userName) {
-		registerUser, err := s.RegisterKeyclokUser(userName, adminToken.AccessToken, DEFAULT_KEYCLOAK_TESTING_REALM)
-		if err != nil && registerUser.Username == "" {
+	if !s.KeycloakUserExists(DEFAULT_KEYCLOAK_TESTING_REALM, adminToken, userName) {
+		if _, err := s.RegisterKeyclokUser(userName, adminToken, DEFAULT_KEYCLOAK_TESTING_REALM); err != nil {
 			return nil, errors.New("failed to register user in keycloak: " + err.Error())
 		}
 	}
@@ -174,12 +203,23 @@ func (s *SandboxController) ReconcileUserCreation(userName string) (*SandboxUser
 		return nil, err
 	}
 
-	userToken, err := s.GetKeycloakToken(DEFAULT_KEYCLOAK_TEST_CLIENT_ID, userName, userName, DEFAULT_KEYCLOAK_TESTING_REALM)
+	return s.getUserAuthInfo(toolchainApiUrl, userName, kubeconfigPath)
+}
+
+// getUserAuthInfo writes the kubeconfig of the user with the current token and attaches the cached token source
+func (s *SandboxController) getUserAuthInfo(toolchainApiUrl, userName, kubeconfigPath string) (*SandboxUserAuthInfo, error) {
+	tokenSource := s.GetKeycloakTokenSource(DEFAULT_KEYCLOAK_TEST_CLIENT_ID, userName, userName, DEFAULT_KEYCLOAK_TESTING_REALM)
+	userToken, err := tokenSource.KeycloakAuth()
 	if err != nil {
 		return nil, err
 	}
 
-	return s.GetKubeconfigPathForSpecificUser(toolchainApiUrl, userName, kubeconfigPath, userToken)
+	authInfo, err := s.GetKubeconfigPathForSpecificUser(toolchainApiUrl, userName, kubeconfigPath, userToken)
+	if err != nil {
+		return nil, err
+	}
+	authInfo.TokenSource = tokenSource
+	return authInfo, nil
 }
 
 func (s *SandboxController) GetKubeconfigPathForSpecificUser(toolchainApiUrl string, userName string, kubeconfigPath string, keycloakAuth *KeycloakAuth) (*SandboxUserAuthInfo, error) {
@@ -248,7 +288,7 @@ func getUserSignupSpecs(username string) *toolchainApi.UserSignup {
 				"toolchain.dev.openshift.com/user-email": fmt.Sprintf("%s@user.us", username),
 			},
 			Labels: map[string]string{
-				"toolchain.dev.openshift.com/email-hash": md5.CalcMd5(fmt.Sprintf("%s@user.us", username)),
+				"toolchain.dev.openshift.com/email-hash": calcMd5(fmt.Sprintf("%s@user.us", username)),
 			},
 		},
 		Spec: toolchainApi.UserSignupSpec{
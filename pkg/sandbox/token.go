@@ -0,0 +1,159 @@
+package sandbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/oauth2"
+	"k8s.io/klog"
+)
+
+// Tokens are refreshed this long before they expire, so requests in flight don't fail with 401
+const tokenExpirySkew = 30 * time.Second
+
+// KeycloakTokenMetrics counts how tokens of all users were obtained by the sandbox controller
+type KeycloakTokenMetrics struct {
+	// Tokens obtained with user credentials
+	Issued int64
+	// Tokens obtained with a refresh token
+	Refreshed int64
+	// Failed refreshes (a new token was requested with user credentials instead)
+	RefreshFailures int64
+}
+
+// KeycloakTokenSource caches a keycloak token of a user and renews it before it expires: with the refresh token
+// if it's still valid, otherwise with user credentials. It implements oauth2.TokenSource, so it can be used
+// for rewriting the bearer token of kubernetes clients.
+type KeycloakTokenSource struct {
+	sandbox  *SandboxController
+	clientID string
+	userName string
+	password string
+	realm    string
+
+	mu   sync.Mutex
+	auth *KeycloakAuth
+}
+
+// Token returns a valid access token of the user
+func (t *KeycloakTokenSource) Token() (*oauth2.Token, error) {
+	auth, err := t.KeycloakAuth()
+	if err != nil {
+		return nil, err
+	}
+	return &oauth2.Token{AccessToken: auth.AccessToken, TokenType: "Bearer", Expiry: auth.ExpiresAt}, nil
+}
+
+// KeycloakAuth returns the cached token if it's not about to expire, otherwise it renews it
+func (t *KeycloakTokenSource) KeycloakAuth() (*KeycloakAuth, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	if t.auth != nil && !t.auth.expiresBefore(now.Add(tokenExpirySkew)) {
+		return t.auth, nil
+	}
+
+	if t.auth != nil && t.auth.RefreshToken != "" && !t.auth.refreshExpiresBefore(now.Add(tokenExpirySkew)) {
+		auth, err := t.sandbox.RefreshKeycloakToken(t.clientID, t.auth.RefreshToken, t.realm)
+		if err == nil {
+			atomic.AddInt64(&t.sandbox.tokenMetrics.Refreshed, 1)
+			t.auth = auth
+			return auth, nil
+		}
+		atomic.AddInt64(&t.sandbox.tokenMetrics.RefreshFailures, 1)
+		klog.Warningf("failed to refresh keycloak token of user %s, requesting a new one: %v", t.userName, err)
+	}
+
+	auth, err := t.sandbox.GetKeycloakToken(t.clientID, t.userName, t.password, t.realm)
+	if err != nil {
+		return nil, err
+	}
+	atomic.AddInt64(&t.sandbox.tokenMetrics.Issued, 1)
+	t.auth = auth
+	return auth, nil
+}
+
+// GetKeycloakTokenSource returns a cached token source of the user, creating it on the first call
+func (s *SandboxController) GetKeycloakTokenSource(clientID, userName, password, realm string) *KeycloakTokenSource {
+	s.tokensMu.Lock()
+	defer s.tokensMu.Unlock()
+
+	key := strings.Join([]string{realm, clientID, userName}, "/")
+	if s.tokens == nil {
+		s.tokens = map[string]*KeycloakTokenSource{}
+	}
+	if ts, ok := s.tokens[key]; ok {
+		return ts
+	}
+	ts := &KeycloakTokenSource{sandbox: s, clientID: clientID, userName: userName, password: password, realm: realm}
+	s.tokens[key] = ts
+	return ts
+}
+
+// GetKeycloakTokenMetrics returns counts of issued and refreshed tokens
+func (s *SandboxController) GetKeycloakTokenMetrics() KeycloakTokenMetrics {
+	return KeycloakTokenMetrics{
+		Issued:          atomic.LoadInt64(&s.tokenMetrics.Issued),
+		Refreshed:       atomic.LoadInt64(&s.tokenMetrics.Refreshed),
+		RefreshFailures: atomic.LoadInt64(&s.tokenMetrics.RefreshFailures),
+	}
+}
+
+// RefreshKeycloakToken exchanges a refresh token for a new access token
+func (k *SandboxController) RefreshKeycloakToken(clientID string, refreshToken string, realm string) (*KeycloakAuth, error) {
+	data := url.Values{
+		"client_id":     {clientID},
+		"refresh_token": {refreshToken},
+		"grant_type":    {"refresh_token"},
+	}
+	return k.requestKeycloakToken(realm, data)
+}
+
+func (k *SandboxController) requestKeycloakToken(realm string, data url.Values) (*KeycloakAuth, error) {
+	request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/auth/realms/%s/protocol/openid-connect/token", k.KeycloakUrl, realm), strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	response, err := k.HttpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get keycloak token, realm: %s, client-id: %s, grant type: %s, url: %s: %v", realm, data.Get("client_id"), data.Get("grant_type"), k.KeycloakUrl, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get keycloak token, realm: %s, client-id: %s, grant type: %s, statusCode: %d, url: %s", realm, data.Get("client_id"), data.Get("grant_type"), response.StatusCode, k.KeycloakUrl)
+	}
+
+	keycloakAuth := &KeycloakAuth{}
+	if err := json.NewDecoder(response.Body).Decode(keycloakAuth); err != nil {
+		return nil, err
+	}
+	keycloakAuth.setExpiry(time.Now())
+	return keycloakAuth, nil
+}
+
+func (a *KeycloakAuth) setExpiry(issuedAt time.Time) {
+	if a.ExpiresIn > 0 {
+		a.ExpiresAt = issuedAt.Add(time.Duration(a.ExpiresIn) * time.Second)
+	}
+	if a.RefreshExpiresIn > 0 {
+		a.RefreshExpiresAt = issuedAt.Add(time.Duration(a.RefreshExpiresIn) * time.Second)
+	}
+}
+
+// expiresBefore returns true if the access token is not valid at the given time. Tokens without expiry never expire
+func (a *KeycloakAuth) expiresBefore(t time.Time) bool {
+	return !a.ExpiresAt.IsZero() && a.ExpiresAt.Before(t)
+}
+
+func (a *KeycloakAuth) refreshExpiresBefore(t time.Time) bool {
+	return !a.RefreshExpiresAt.IsZero() && a.RefreshExpiresAt.Before(t)
+}
@@ -0,0 +1,42 @@
+package sandbox
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeycloakTokenSource(t *testing.T) {
+	grants := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		grant := r.Form.Get("grant_type")
+		grants[grant]++
+		// Access tokens expire immediately (within the refresh skew), so every call renews them
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":       grant,
+			"refresh_token":      "refresh",
+			"expires_in":         1,
+			"refresh_expires_in": 1800,
+		})
+	}))
+	defer server.Close()
+
+	s := &SandboxController{HttpClient: server.Client(), KeycloakUrl: server.URL}
+	ts := s.GetKeycloakTokenSource("client", "user", "user", "realm")
+	assert.Same(t, ts, s.GetKeycloakTokenSource("client", "user", "user", "realm"))
+
+	token, err := ts.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, "password", token.AccessToken)
+
+	token, err = ts.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, "refresh_token", token.AccessToken)
+
+	assert.Equal(t, map[string]int{"password": 1, "refresh_token": 1}, grants)
+	assert.Equal(t, KeycloakTokenMetrics{Issued: 1, Refreshed: 1}, s.GetKeycloakTokenMetrics())
+}
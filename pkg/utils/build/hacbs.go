@@ -7,38 +7,33 @@ import (
 	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
-	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils/tekton"
 )
 
-func FetchTaskRunResult(pr *v1beta1.PipelineRun, pipelineTaskName string, result string) (string, error) {
-	for _, tr := range pr.Status.TaskRuns {
-		if tr.PipelineTaskName != pipelineTaskName {
-			continue
-		}
-		for _, trResult := range tr.Status.TaskRunResults {
-			if trResult.Name == result {
-				return strings.TrimSuffix(trResult.Value.StringVal, "\n"), nil
-			}
-		}
+// FetchTaskRunResult returns a result of the TaskRun of a pipeline task, whichever Tekton API version the PipelineRun is served as
+func FetchTaskRunResult(c *tekton.SuiteController, pr *tekton.PipelineRunAccessor, pipelineTaskName string, result string) (string, error) {
+	value, err := c.GetPipelineTaskRunResult(pr, pipelineTaskName, result)
+	if err != nil {
+		return "", fmt.Errorf(
+			"result %q not found in TaskRuns of PipelineRun %s/%s for pipeline task name %s: %v", result, pr.Namespace(), pr.Name(), pipelineTaskName, err)
 	}
-	return "", fmt.Errorf(
-		"result %q not found in TaskRuns of PipelineRun %s/%s for pipeline task name %s", result, pr.ObjectMeta.Namespace, pr.ObjectMeta.Name, pipelineTaskName)
+	return strings.TrimSuffix(value, "\n"), nil
 }
 
-func FetchImageTaskRunResult(pr *v1beta1.PipelineRun, pipelineTaskName string, result string) (string, error) {
-	for _, tr := range pr.Status.TaskRuns {
-		if tr.PipelineTaskName != pipelineTaskName {
-			continue
+// FetchImageTaskRunResult returns a result of the TaskRun of a pipeline task, or its BASE_IMAGE_REPOSITORY result if not present
+func FetchImageTaskRunResult(c *tekton.SuiteController, pr *tekton.PipelineRunAccessor, pipelineTaskName string, result string) (string, error) {
+	taskRun, err := c.GetPipelineTaskRun(pr, pipelineTaskName)
+	if err == nil {
+		results := taskRun.Results()
+		if value, ok := results[result]; ok {
+			return value, nil
 		}
-		for _, trResult := range tr.Status.TaskRunResults {
-
-			if trResult.Name == "BASE_IMAGE_REPOSITORY" || trResult.Name == result {
-				return trResult.Value.StringVal, nil
-			}
+		if value, ok := results["BASE_IMAGE_REPOSITORY"]; ok {
+			return value, nil
 		}
 	}
 	return "", fmt.Errorf(
-		"result %q not found in TaskRuns of PipelineRun %s/%s", result, pr.ObjectMeta.Namespace, pr.ObjectMeta.Name)
+		"result %q not found in TaskRuns of PipelineRun %s/%s", result, pr.Namespace(), pr.Name())
 }
 
 func ValidateImageTaskRunResults(taskname string, result string) bool {
@@ -1,6 +1,7 @@
 package common
 
 import (
+	"github.com/redhat-appstudio/e2e-tests/pkg/apis/git"
 	"github.com/redhat-appstudio/e2e-tests/pkg/apis/github"
 	kubeCl "github.com/redhat-appstudio/e2e-tests/pkg/apis/kubernetes"
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
@@ -14,6 +15,9 @@ type SuiteController struct {
 
 	// Github client to interact with GH apis
 	Github *github.Github
+
+	// Git provider (GitHub or GitLab, based on GIT_PROVIDER env) for provider agnostic tests
+	GitProvider git.GitProvider
 }
 
 /*
@@ -25,8 +29,13 @@ func NewSuiteController(kubeC *kubeCl.CustomClient) (*SuiteController, error) {
 	if err != nil {
 		return nil, err
 	}
+	gp, err := git.NewGitProviderFromEnv()
+	if err != nil {
+		return nil, err
+	}
 	return &SuiteController{
 		kubeC,
 		gh,
+		gp,
 	}, nil
 }
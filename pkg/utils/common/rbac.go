@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -120,3 +121,22 @@ func (s *SuiteController) CreateRoleBinding(roleBindingName, namespace, subjectK
 	}
 	return createdRoleBinding, nil
 }
+
+// CanI checks (with a SelfSubjectAccessReview) whether the user of the client is allowed to perform the verb on the resource in the namespace
+func (s *SuiteController) CanI(verb, group, resource, namespace string) (bool, error) {
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      verb,
+				Group:     group,
+				Resource:  resource,
+			},
+		},
+	}
+	review, err := s.KubeInterface().AuthorizationV1().SelfSubjectAccessReviews().Create(context.TODO(), review, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return review.Status.Allowed, nil
+}
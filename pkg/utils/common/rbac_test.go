@@ -0,0 +1,69 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	kubeCl "github.com/redhat-appstudio/e2e-tests/pkg/apis/kubernetes"
+	"github.com/stretchr/testify/assert"
+	authorizationv1 "k8s.io/api/authorization/v1"
+)
+
+// newTestController returns a controller talking to a fake API server, which allows only "get pods" in the "allowed" namespace
+func newTestController(t *testing.T, impersonatedUsers *[]string) *SuiteController {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api":
+			_, _ = w.Write([]byte(`{"kind": "APIVersions", "versions": ["v1"]}`))
+		case "/apis":
+			_, _ = w.Write([]byte(`{"kind": "APIGroupList", "groups": []}`))
+		case "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews":
+			*impersonatedUsers = append(*impersonatedUsers, r.Header.Get("Impersonate-User"))
+			review := &authorizationv1.SelfSubjectAccessReview{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(review))
+			attrs := review.Spec.ResourceAttributes
+			review.Status.Allowed = attrs.Verb == "get" && attrs.Group == "" && attrs.Resource == "pods" && attrs.Namespace == "allowed"
+			review.APIVersion, review.Kind = "authorization.k8s.io/v1", "SelfSubjectAccessReview"
+			w.WriteHeader(http.StatusCreated)
+			assert.NoError(t, json.NewEncoder(w).Encode(review))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	kubeconfig := filepath.Join(t.TempDir(), "kubeconfig")
+	content := fmt.Sprintf("apiVersion: v1\nkind: Config\nclusters:\n- name: fake\n  cluster:\n    server: %s\n"+
+		"users:\n- name: admin\n  user:\n    token: admin-token\n"+
+		"contexts:\n- name: fake\n  context:\n    cluster: fake\n    user: admin\ncurrent-context: fake\n", server.URL)
+	assert.NoError(t, os.WriteFile(kubeconfig, []byte(content), 0600))
+	t.Setenv("KUBECONFIG", kubeconfig)
+
+	k, err := kubeCl.NewImpersonatingUserClient("alice", nil)
+	assert.NoError(t, err)
+	return &SuiteController{CustomClient: k.AsKubeDeveloper}
+}
+
+func TestCanI(t *testing.T) {
+	var impersonatedUsers []string
+	c := newTestController(t, &impersonatedUsers)
+
+	allowed, err := c.CanI("get", "", "pods", "allowed")
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+
+	allowed, err = c.CanI("delete", "", "pods", "allowed")
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+
+	allowed, err = c.CanI("get", "", "pods", "other")
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, []string{"alice", "alice", "alice"}, impersonatedUsers)
+}
@@ -0,0 +1,205 @@
+package fixtures
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"time"
+
+	kubeCl "github.com/redhat-appstudio/e2e-tests/pkg/apis/kubernetes"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+)
+
+const (
+	FieldManager = "e2e-tests"
+
+	DefaultReadyTimeout = 5 * time.Minute
+)
+
+// KindsWithoutStatus are the kinds which don't report readiness in their status, so they are ready as soon as they exist.
+// Suites can add kinds of their own custom resources
+var KindsWithoutStatus = map[string]bool{
+	"Namespace":                true,
+	"ServiceAccount":           true,
+	"Secret":                   true,
+	"ConfigMap":                true,
+	"PersistentVolumeClaim":    true,
+	"ClusterRole":              true,
+	"Role":                     true,
+	"ClusterRoleBinding":       true,
+	"RoleBinding":              true,
+	"Service":                  true,
+	"Pipeline":                 true,
+	"Task":                     true,
+	"BuildPipelineSelector":    true,
+	"EnterpriseContractPolicy": true,
+	"ReleasePlan":              true,
+	"ReleasePlanAdmission":     true,
+	"ReleaseStrategy":          true,
+}
+
+// Applier applies rendered fixtures to a cluster
+type Applier struct {
+	client *kubeCl.CustomClient
+	mapper *restmapper.DeferredDiscoveryRESTMapper
+	// How long to wait for every applied object to become ready
+	ReadyTimeout time.Duration
+}
+
+// Fixture is a handle to objects applied to the cluster
+type Fixture struct {
+	Objects []*unstructured.Unstructured
+
+	applier *Applier
+}
+
+func NewApplier(client *kubeCl.CustomClient) *Applier {
+	discovery := memory.NewMemCacheClient(client.KubeInterface().Discovery())
+	return &Applier{
+		client:       client,
+		mapper:       restmapper.NewDeferredDiscoveryRESTMapper(discovery),
+		ReadyTimeout: DefaultReadyTimeout,
+	}
+}
+
+// ApplyDir renders the manifests from the directory, applies them and waits until they are ready
+func (a *Applier) ApplyDir(dir string, values Values) (*Fixture, error) {
+	objects, err := LoadDir(dir, values)
+	if err != nil {
+		return nil, err
+	}
+	return a.Apply(objects, values.Namespace)
+}
+
+// ApplyFS renders the manifests from a directory of the file system (e.g. an embed.FS), applies them and waits until they are ready
+func (a *Applier) ApplyFS(fsys fs.FS, dir string, values Values) (*Fixture, error) {
+	objects, err := LoadFS(fsys, dir, values)
+	if err != nil {
+		return nil, err
+	}
+	return a.Apply(objects, values.Namespace)
+}
+
+// Apply creates or updates the objects in the given order using server-side apply and waits until they are ready.
+// Objects without a namespace get the default namespace if their kind is namespaced. On failure, the returned fixture
+// holds the objects applied so far, so they can be deleted.
+func (a *Applier) Apply(objects []*unstructured.Unstructured, namespace string) (*Fixture, error) {
+	f := &Fixture{applier: a}
+	for _, obj := range objects {
+		ri, err := a.resourceFor(obj, namespace)
+		if err != nil {
+			return f, err
+		}
+		applied, err := ri.Apply(context.TODO(), obj.GetName(), obj, metav1.ApplyOptions{FieldManager: FieldManager, Force: true})
+		if err != nil {
+			return f, fmt.Errorf("error when applying %s: %v", describe(obj), err)
+		}
+		f.Objects = append(f.Objects, applied)
+		if err := wait.PollImmediate(time.Second, a.ReadyTimeout, a.isReady(ri, applied.GetName())); err != nil {
+			return f, fmt.Errorf("%s didn't become ready: %v", describe(applied), err)
+		}
+		// The discovery is cached, the kinds of an established CRD are only found once the cache is refreshed
+		if applied.GetKind() == "CustomResourceDefinition" {
+			a.mapper.Reset()
+		}
+	}
+	return f, nil
+}
+
+// Delete removes the applied objects in the reverse order. Objects which don't exist anymore are skipped
+func (f *Fixture) Delete() error {
+	for i := len(f.Objects) - 1; i >= 0; i-- {
+		obj := f.Objects[i]
+		ri, err := f.applier.resourceFor(obj, obj.GetNamespace())
+		if err != nil {
+			return err
+		}
+		if err := ri.Delete(context.TODO(), obj.GetName(), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("error when deleting %s: %v", describe(obj), err)
+		}
+	}
+	return nil
+}
+
+// Get returns the applied object of the given kind and name
+func (f *Fixture) Get(kind, name string) (*unstructured.Unstructured, error) {
+	for _, obj := range f.Objects {
+		if obj.GetKind() == kind && obj.GetName() == name {
+			return obj, nil
+		}
+	}
+	return nil, fmt.Errorf("%s %s is not part of the fixture", kind, name)
+}
+
+func (a *Applier) resourceFor(obj *unstructured.Unstructured, namespace string) (dynamic.ResourceInterface, error) {
+	gvk := obj.GroupVersionKind()
+	mapping, err := a.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, fmt.Errorf("error when getting resource of %s: %v", describe(obj), err)
+	}
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return a.client.DynamicClient().Resource(mapping.Resource), nil
+	}
+	if obj.GetNamespace() == "" {
+		if namespace == "" {
+			return nil, fmt.Errorf("namespace of %s is not specified", describe(obj))
+		}
+		obj.SetNamespace(namespace)
+	}
+	return a.client.DynamicClient().Resource(mapping.Resource).Namespace(obj.GetNamespace()), nil
+}
+
+// isReady returns a condition which is met once the object reports it is ready, see IsReady
+func (a *Applier) isReady(ri dynamic.ResourceInterface, name string) wait.ConditionFunc {
+	return func() (bool, error) {
+		obj, err := ri.Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return IsReady(obj), nil
+	}
+}
+
+// IsReady checks the status of an object in a kind agnostic way: all replicas are ready, or "Ready", "Succeeded",
+// "Available" and "Established" conditions are true. Objects without conditions aren't ready (the controller didn't
+// reconcile them yet), unless their kind is one of KindsWithoutStatus
+func IsReady(obj *unstructured.Unstructured) bool {
+	if KindsWithoutStatus[obj.GetKind()] {
+		return true
+	}
+	if replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas"); found {
+		ready, _, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+		return ready >= replicas
+	}
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if len(conditions) == 0 {
+		return false
+	}
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch condition["type"] {
+		case "Ready", "Succeeded", "Available", "Established":
+			if condition["status"] != "True" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func describe(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return fmt.Sprintf("%s %s", obj.GetKind(), obj.GetName())
+	}
+	return fmt.Sprintf("%s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+}
@@ -0,0 +1,135 @@
+package fixtures
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/devfile/library/pkg/util"
+	sprig "github.com/go-task/slim-sprig"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Values are available in the manifest templates, e.g. {{ .Namespace }}, {{ .Suffix }}, {{ index .Images "release-pipeline" }}
+// or {{ .Config.extraConfigPath }}. Sprig functions (default, quote, b64enc, ...) and {{ env "NAME" }} can be used as well.
+type Values struct {
+	// Namespace used for namespaced objects which don't specify one
+	Namespace string
+	// Random suffix for names of objects, generated when empty
+	Suffix string
+	// Image references, e.g. bundles or container images
+	Images map[string]string
+	// Any other values of the scenario
+	Config map[string]interface{}
+}
+
+// Order in which kinds are applied, kinds not listed here are applied after the listed ones (in the order they are loaded)
+var kindOrder = []string{
+	"Namespace",
+	"CustomResourceDefinition",
+	"ServiceAccount",
+	"Secret",
+	"ConfigMap",
+	"PersistentVolumeClaim",
+	"ClusterRole",
+	"Role",
+	"ClusterRoleBinding",
+	"RoleBinding",
+}
+
+// Render executes the manifest as a Go template and decodes all YAML documents in it
+func Render(name string, manifest []byte, values Values) ([]*unstructured.Unstructured, error) {
+	if values.Suffix == "" {
+		values.Suffix = strings.ToLower(util.GenerateRandomString(4))
+	}
+
+	tmpl, err := template.New(name).Funcs(sprig.TxtFuncMap()).Option("missingkey=error").Parse(string(manifest))
+	if err != nil {
+		return nil, fmt.Errorf("error when parsing fixture %s: %v", name, err)
+	}
+	var rendered bytes.Buffer
+	if err := tmpl.Execute(&rendered, values); err != nil {
+		return nil, fmt.Errorf("error when rendering fixture %s: %v", name, err)
+	}
+
+	var objects []*unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(&rendered, 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("error when decoding fixture %s: %v", name, err)
+		}
+		// Empty documents (e.g. a trailing "---")
+		if len(obj.Object) == 0 {
+			continue
+		}
+		if obj.GetKind() == "" || obj.GetAPIVersion() == "" || obj.GetName() == "" {
+			return nil, fmt.Errorf("object in fixture %s is missing apiVersion, kind or metadata.name: %v", name, obj.Object)
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// LoadDir renders all *.yaml/*.yml files of the directory (in alphabetical order) and returns the objects
+// sorted in the order they need to be applied
+func LoadDir(dir string, values Values) ([]*unstructured.Unstructured, error) {
+	return LoadFS(os.DirFS(dir), ".", values)
+}
+
+// LoadFS is like LoadDir, for a directory of a file system, e.g. of fixtures embedded in a test suite
+func LoadFS(fsys fs.FS, dir string, values Values) ([]*unstructured.Unstructured, error) {
+	if values.Suffix == "" {
+		values.Suffix = strings.ToLower(util.GenerateRandomString(4))
+	}
+
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("error when reading fixtures directory %s: %v", dir, err)
+	}
+	var objects []*unstructured.Unstructured
+	for _, e := range entries {
+		ext := path.Ext(e.Name())
+		if e.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		manifest, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		objs, err := Render(e.Name(), manifest, values)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, objs...)
+	}
+	SortForApply(objects)
+	return objects, nil
+}
+
+// SortForApply sorts objects so dependencies (namespaces, service accounts, secrets, RBAC, ...) come first.
+// The order of objects of the same priority is kept.
+func SortForApply(objects []*unstructured.Unstructured) {
+	sort.SliceStable(objects, func(i, j int) bool {
+		return kindPriority(objects[i].GetKind()) < kindPriority(objects[j].GetKind())
+	})
+}
+
+func kindPriority(kind string) int {
+	for i, k := range kindOrder {
+		if k == kind {
+			return i
+		}
+	}
+	return len(kindOrder)
+}
@@ -0,0 +1,80 @@
+package fixtures
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const testManifest = `
+apiVersion: appstudio.redhat.com/v1alpha1
+kind: ReleasePlan
+metadata:
+  name: source-releaseplan-{{ .Suffix }}
+spec:
+  target: {{ .Config.managedNamespace }}
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: release-service-account
+  namespace: {{ .Namespace }}
+---
+`
+
+func TestRenderAndSort(t *testing.T) {
+	objects, err := Render("test.yaml", []byte(testManifest), Values{
+		Namespace: "user-tenant",
+		Suffix:    "abcd",
+		Config:    map[string]interface{}{"managedNamespace": "managed"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, objects, 2)
+	assert.Equal(t, "source-releaseplan-abcd", objects[0].GetName())
+
+	target, _, _ := unstructured.NestedString(objects[0].Object, "spec", "target")
+	assert.Equal(t, "managed", target)
+
+	SortForApply(objects)
+	assert.Equal(t, "ServiceAccount", objects[0].GetKind())
+	assert.Equal(t, "user-tenant", objects[0].GetNamespace())
+}
+
+func TestLoadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"fixtures/release.yaml": {Data: []byte(testManifest)},
+		"fixtures/README.md":    {Data: []byte("not a manifest")},
+	}
+	objects, err := LoadFS(fsys, "fixtures", Values{Namespace: "user-tenant", Config: map[string]interface{}{"managedNamespace": "managed"}})
+	assert.NoError(t, err)
+	assert.Len(t, objects, 2)
+	assert.Equal(t, "ServiceAccount", objects[0].GetKind())
+	assert.Regexp(t, "^source-releaseplan-[a-z0-9]{4}$", objects[1].GetName())
+}
+
+func TestRenderMissingValue(t *testing.T) {
+	_, err := Render("test.yaml", []byte(testManifest), Values{Config: map[string]interface{}{}})
+	assert.Error(t, err)
+}
+
+func TestIsReady(t *testing.T) {
+	// Not reconciled yet
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"kind": "Environment"}}
+	assert.False(t, IsReady(obj))
+	_ = unstructured.SetNestedSlice(obj.Object, []interface{}{}, "status", "conditions")
+	assert.False(t, IsReady(obj))
+
+	_ = unstructured.SetNestedSlice(obj.Object, []interface{}{map[string]interface{}{"type": "Ready", "status": "False"}}, "status", "conditions")
+	assert.False(t, IsReady(obj))
+	_ = unstructured.SetNestedSlice(obj.Object, []interface{}{map[string]interface{}{"type": "Ready", "status": "True"}}, "status", "conditions")
+	assert.True(t, IsReady(obj))
+
+	assert.True(t, IsReady(&unstructured.Unstructured{Object: map[string]interface{}{"kind": "ReleasePlan"}}))
+
+	deployment := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	_ = unstructured.SetNestedField(deployment.Object, int64(2), "spec", "replicas")
+	_ = unstructured.SetNestedField(deployment.Object, int64(1), "status", "readyReplicas")
+	assert.False(t, IsReady(deployment))
+}
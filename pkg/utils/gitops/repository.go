@@ -0,0 +1,309 @@
+package gitops
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	routev1 "github.com/openshift/api/route/v1"
+	appservice "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// Directory of the gitops repository containing a kustomize base and environment overlays per component
+const gitOpsComponentsDir = "components"
+
+// GitOpsRepository is a local clone of the gitops repository HAS generates for an application, with the layout
+//
+//	components/<component>/base
+//	components/<component>/overlays/<environment>
+type GitOpsRepository struct {
+	// URL the repository was cloned from
+	URL string
+
+	// Local directory of the clone
+	Dir string
+
+	repo *git.Repository
+	auth transport.AuthMethod
+}
+
+// ComponentManifests holds the typed objects a kustomize base or environment overlay of a component renders into
+type ComponentManifests struct {
+	Deployments []appsv1.Deployment
+	Services    []corev1.Service
+	Routes      []routev1.Route
+
+	// Objects of any other kind
+	Others []unstructured.Unstructured
+}
+
+// ComponentExpectation describes the expected content of the gitops manifests of a component. Empty fields are not checked
+type ComponentExpectation struct {
+	// Image of the component container
+	Image string
+
+	// Number of replicas of the component deployment
+	Replicas *int32
+
+	// Target port of the component route
+	RoutePort int
+}
+
+// CloneApplicationGitOpsRepository clones the gitops repository of a HAS application into a temporary directory
+func CloneApplicationGitOpsRepository(application *appservice.Application) (*GitOpsRepository, error) {
+	repoURL := utils.ObtainGitOpsRepositoryUrl(application.Status.Devfile)
+	if repoURL == "" {
+		return nil, fmt.Errorf("application %s has no gitops repository in its devfile", application.Name)
+	}
+	dir, err := os.MkdirTemp("", fmt.Sprintf("gitops-%s-", application.Name))
+	if err != nil {
+		return nil, err
+	}
+	return CloneGitOpsRepository(repoURL, dir)
+}
+
+// CloneGitOpsRepository clones a gitops repository into a given directory. GitHub and GitLab repositories are
+// cloned using GITHUB_TOKEN and GITLAB_TOKEN env vars respectively, if set
+func CloneGitOpsRepository(repoURL, dir string) (*GitOpsRepository, error) {
+	auth := gitAuthForURL(repoURL)
+	klog.Infof("cloning gitops repository %s into %s", repoURL, dir)
+	repo, err := git.PlainClone(dir, false, &git.CloneOptions{URL: repoURL, Auth: auth})
+	if err != nil {
+		return nil, fmt.Errorf("error when cloning gitops repository %s: %v", repoURL, err)
+	}
+	return &GitOpsRepository{URL: repoURL, Dir: dir, repo: repo, auth: auth}, nil
+}
+
+// Pull fetches the latest changes of the repository, e.g. after a component got scaled or a snapshot got promoted
+func (r *GitOpsRepository) Pull() error {
+	worktree, err := r.repo.Worktree()
+	if err != nil {
+		return err
+	}
+	if err := worktree.Pull(&git.PullOptions{Auth: r.auth}); err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("error when pulling gitops repository %s: %v", r.URL, err)
+	}
+	return nil
+}
+
+// Remove deletes the local clone
+func (r *GitOpsRepository) Remove() error {
+	return os.RemoveAll(r.Dir)
+}
+
+// Components returns the names of the components present in the repository
+func (r *GitOpsRepository) Components() ([]string, error) {
+	return listDirs(filepath.Join(r.Dir, gitOpsComponentsDir))
+}
+
+// Environments returns the names of the environments a component has an overlay for
+func (r *GitOpsRepository) Environments(component string) ([]string, error) {
+	return listDirs(filepath.Join(r.Dir, gitOpsComponentsDir, component, "overlays"))
+}
+
+// Base renders the kustomize base of a component
+func (r *GitOpsRepository) Base(component string) (*ComponentManifests, error) {
+	return buildKustomization(filepath.Join(r.Dir, gitOpsComponentsDir, component, "base"))
+}
+
+// Overlay renders the kustomize overlay of a component for a given environment
+func (r *GitOpsRepository) Overlay(component, environment string) (*ComponentManifests, error) {
+	return buildKustomization(filepath.Join(r.Dir, gitOpsComponentsDir, component, "overlays", environment))
+}
+
+// Manifests renders the overlay of a component for a given environment, or its base if the environment is empty
+func (r *GitOpsRepository) Manifests(component, environment string) (*ComponentManifests, error) {
+	if environment == "" {
+		return r.Base(component)
+	}
+	return r.Overlay(component, environment)
+}
+
+// VerifyComponent checks the manifests of a component in a given environment (or its base if the environment is empty)
+// match the expectation. The returned error describes all the mismatches
+func (r *GitOpsRepository) VerifyComponent(component, environment string, expected ComponentExpectation) error {
+	manifests, err := r.Manifests(component, environment)
+	if err != nil {
+		return err
+	}
+	return manifests.Verify(component, expected)
+}
+
+// WaitForComponent pulls the repository until the manifests of a component in a given environment match the expectation
+func (r *GitOpsRepository) WaitForComponent(component, environment string, expected ComponentExpectation, timeout time.Duration) error {
+	var lastErr error
+	err := utils.WaitUntil(func() (done bool, err error) {
+		if err := r.Pull(); err != nil {
+			klog.Info(err)
+			return false, nil
+		}
+		lastErr = r.VerifyComponent(component, environment, expected)
+		return lastErr == nil, nil
+	}, timeout)
+	if err != nil {
+		return fmt.Errorf("gitops repository %s doesn't contain the expected manifests of component %s: %v", r.URL, component, lastErr)
+	}
+	return nil
+}
+
+// Deployment returns the deployment with a given name
+func (m *ComponentManifests) Deployment(name string) (*appsv1.Deployment, error) {
+	for i := range m.Deployments {
+		if m.Deployments[i].Name == name {
+			return &m.Deployments[i], nil
+		}
+	}
+	return nil, fmt.Errorf("deployment %s not found", name)
+}
+
+// Route returns the route with a given name
+func (m *ComponentManifests) Route(name string) (*routev1.Route, error) {
+	for i := range m.Routes {
+		if m.Routes[i].Name == name {
+			return &m.Routes[i], nil
+		}
+	}
+	return nil, fmt.Errorf("route %s not found", name)
+}
+
+// Verify checks the deployment and route named after a component match the expectation
+func (m *ComponentManifests) Verify(component string, expected ComponentExpectation) error {
+	var mismatches []string
+
+	if expected.Image != "" || expected.Replicas != nil {
+		deployment, err := m.Deployment(component)
+		if err != nil {
+			return err
+		}
+		if expected.Image != "" && !hasContainerImage(deployment, expected.Image) {
+			mismatches = append(mismatches, fmt.Sprintf("deployment %s has no container with image %s (images: %v)", component, expected.Image, containerImages(deployment)))
+		}
+		if expected.Replicas != nil {
+			actual := int32(1)
+			if deployment.Spec.Replicas != nil {
+				actual = *deployment.Spec.Replicas
+			}
+			if actual != *expected.Replicas {
+				mismatches = append(mismatches, fmt.Sprintf("deployment %s has %d replicas, expected %d", component, actual, *expected.Replicas))
+			}
+		}
+	}
+
+	if expected.RoutePort != 0 {
+		route, err := m.Route(component)
+		if err != nil {
+			return err
+		}
+		if route.Spec.Port == nil || route.Spec.Port.TargetPort != intstr.FromInt(expected.RoutePort) {
+			mismatches = append(mismatches, fmt.Sprintf("route %s doesn't target port %d (port: %v)", component, expected.RoutePort, route.Spec.Port))
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return errors.New(strings.Join(mismatches, "; "))
+	}
+	return nil
+}
+
+func buildKustomization(path string) (*ComponentManifests, error) {
+	resources, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(filesys.MakeFsOnDisk(), path)
+	if err != nil {
+		return nil, fmt.Errorf("error when building kustomization %s: %v", path, err)
+	}
+
+	manifests := &ComponentManifests{}
+	for _, resource := range resources.Resources() {
+		content, err := resource.Map()
+		if err != nil {
+			return nil, err
+		}
+		obj := unstructured.Unstructured{Object: content}
+		switch obj.GetKind() {
+		case "Deployment":
+			deployment := appsv1.Deployment{}
+			err = runtime.DefaultUnstructuredConverter.FromUnstructured(content, &deployment)
+			manifests.Deployments = append(manifests.Deployments, deployment)
+		case "Service":
+			service := corev1.Service{}
+			err = runtime.DefaultUnstructuredConverter.FromUnstructured(content, &service)
+			manifests.Services = append(manifests.Services, service)
+		case "Route":
+			route := routev1.Route{}
+			err = runtime.DefaultUnstructuredConverter.FromUnstructured(content, &route)
+			manifests.Routes = append(manifests.Routes, route)
+		default:
+			manifests.Others = append(manifests.Others, obj)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error when decoding %s %s from %s: %v", obj.GetKind(), obj.GetName(), path, err)
+		}
+	}
+	return manifests, nil
+}
+
+func gitAuthForURL(repoURL string) transport.AuthMethod {
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return nil
+	}
+	token := ""
+	switch {
+	case strings.Contains(u.Host, "github"):
+		token = utils.GetEnv(constants.GITHUB_TOKEN_ENV, "")
+	case strings.Contains(u.Host, "gitlab"):
+		token = utils.GetEnv(constants.GITLAB_TOKEN_ENV, "")
+	}
+	if token == "" {
+		return nil
+	}
+	// Both providers accept any non-empty user name along with a token
+	return &http.BasicAuth{Username: "e2e-tests", Password: token}
+}
+
+func listDirs(path string) ([]string, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	var dirs []string
+	for _, e := range entries {
+		if e.IsDir() {
+			dirs = append(dirs, e.Name())
+		}
+	}
+	return dirs, nil
+}
+
+func hasContainerImage(deployment *appsv1.Deployment, image string) bool {
+	for _, c := range deployment.Spec.Template.Spec.Containers {
+		if c.Image == image {
+			return true
+		}
+	}
+	return false
+}
+
+func containerImages(deployment *appsv1.Deployment) []string {
+	var images []string
+	for _, c := range deployment.Spec.Template.Spec.Containers {
+		images = append(images, c.Image)
+	}
+	return images
+}
@@ -0,0 +1,125 @@
+package gitops
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	baseDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: comp
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: comp
+  template:
+    metadata:
+      labels:
+        app: comp
+    spec:
+      containers:
+      - name: container-image
+        image: quay.io/org/comp:base
+`
+	baseRoute = `apiVersion: route.openshift.io/v1
+kind: Route
+metadata:
+  name: comp
+spec:
+  port:
+    targetPort: 8081
+  to:
+    kind: Service
+    name: comp
+`
+	baseKustomization = `resources:
+- deployment.yaml
+- route.yaml
+`
+	overlayPatch = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: comp
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: container-image
+        image: quay.io/org/comp:promoted
+`
+	overlayKustomization = `resources:
+- ../../base
+patchesStrategicMerge:
+- deployment-patch.yaml
+`
+)
+
+func TestGitOpsRepository(t *testing.T) {
+	origin := t.TempDir()
+	writeFiles(t, origin, map[string]string{
+		"components/comp/base/deployment.yaml":                       baseDeployment,
+		"components/comp/base/route.yaml":                            baseRoute,
+		"components/comp/base/kustomization.yaml":                    baseKustomization,
+		"components/comp/overlays/development/deployment-patch.yaml": overlayPatch,
+		"components/comp/overlays/development/kustomization.yaml":    overlayKustomization,
+	})
+	originRepo := commitAll(t, origin, nil)
+
+	r, err := CloneGitOpsRepository(origin, filepath.Join(t.TempDir(), "clone"))
+	assert.NoError(t, err)
+
+	components, err := r.Components()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"comp"}, components)
+	environments, err := r.Environments("comp")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"development"}, environments)
+
+	assert.NoError(t, r.VerifyComponent("comp", "", ComponentExpectation{Image: "quay.io/org/comp:base", Replicas: pointer.Int32(1), RoutePort: 8081}))
+	assert.NoError(t, r.VerifyComponent("comp", "development", ComponentExpectation{Image: "quay.io/org/comp:promoted", Replicas: pointer.Int32(3), RoutePort: 8081}))
+
+	err = r.VerifyComponent("comp", "development", ComponentExpectation{Image: "quay.io/org/comp:base", Replicas: pointer.Int32(1), RoutePort: 8080})
+	assert.ErrorContains(t, err, "no container with image quay.io/org/comp:base")
+	assert.ErrorContains(t, err, "has 3 replicas, expected 1")
+	assert.ErrorContains(t, err, "doesn't target port 8080")
+	assert.Error(t, r.VerifyComponent("other", "", ComponentExpectation{Image: "quay.io/org/comp:base"}))
+
+	// Scaling the component in the origin repository is picked up after pulling
+	writeFiles(t, origin, map[string]string{"components/comp/base/deployment.yaml": strings.Replace(baseDeployment, "replicas: 1", "replicas: 2", 1)})
+	commitAll(t, origin, originRepo)
+	assert.NoError(t, r.WaitForComponent("comp", "", ComponentExpectation{Replicas: pointer.Int32(2)}, 10*time.Second))
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+}
+
+func commitAll(t *testing.T, dir string, repo *git.Repository) *git.Repository {
+	var err error
+	if repo == nil {
+		repo, err = git.PlainInit(dir, false)
+		assert.NoError(t, err)
+	}
+	worktree, err := repo.Worktree()
+	assert.NoError(t, err)
+	assert.NoError(t, worktree.AddGlob("."))
+	_, err = worktree.Commit("update", &git.CommitOptions{Author: &object.Signature{Name: "e2e", Email: "e2e@example.com", When: time.Now()}})
+	assert.NoError(t, err)
+	return repo
+}
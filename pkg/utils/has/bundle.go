@@ -0,0 +1,437 @@
+package has
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	appservice "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
+	integrationv1alpha1 "github.com/redhat-appstudio/integration-service/api/v1alpha1"
+	releasev1alpha1 "github.com/redhat-appstudio/release-service/api/v1alpha1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	rclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// ApplicationBundleVersion is the version of the ApplicationBundle format
+const ApplicationBundleVersion = "v1"
+
+// ApplicationBundle is a portable export of an application with its related resources. Status and cluster specific
+// metadata are stripped, secrets are only referenced by name and need to be created in the target namespace before importing
+type ApplicationBundle struct {
+	Version string `json:"version"`
+
+	Application                 appservice.Application                        `json:"application"`
+	Components                  []appservice.Component                        `json:"components,omitempty"`
+	Environments                []appservice.Environment                      `json:"environments,omitempty"`
+	Snapshots                   []appservice.Snapshot                         `json:"snapshots,omitempty"`
+	SnapshotEnvironmentBindings []appservice.SnapshotEnvironmentBinding       `json:"snapshotEnvironmentBindings,omitempty"`
+	IntegrationTestScenarios    []integrationv1alpha1.IntegrationTestScenario `json:"integrationTestScenarios,omitempty"`
+	ReleasePlans                []releasev1alpha1.ReleasePlan                 `json:"releasePlans,omitempty"`
+
+	// Names of the secrets referenced by the exported resources
+	Secrets []string `json:"secrets,omitempty"`
+}
+
+// ApplicationImportOptions controls how an ApplicationBundle gets imported
+type ApplicationImportOptions struct {
+	// Namespace to import the bundle into
+	Namespace string
+
+	// Explicit new names of the imported resources, by their exported name. Resources referencing a renamed resource are updated accordingly
+	Names map[string]string
+
+	// Suffix appended to the names of the resources which are not in Names. Names are kept if empty
+	NameSuffix string
+
+	// If set, how long to wait for HAS to generate the application devfile before creating the components
+	ApplicationReadyTimeout time.Duration
+}
+
+// ExportApplication exports an application with its components, the environments of the namespace, and the snapshots,
+// bindings, integration test scenarios and release plans of the application
+func (h *SuiteController) ExportApplication(name, namespace string) (*ApplicationBundle, error) {
+	return ExportApplicationBundle(h.KubeRest(), name, namespace)
+}
+
+// ImportApplication creates the resources of an ApplicationBundle in a namespace and returns the bundle of the created resources
+func (h *SuiteController) ImportApplication(bundle *ApplicationBundle, opts ApplicationImportOptions) (*ApplicationBundle, error) {
+	return ImportApplicationBundle(h.KubeRest(), bundle, opts)
+}
+
+// ExportApplicationBundle exports an application and its related resources using a given client
+func ExportApplicationBundle(c rclient.Client, name, namespace string) (*ApplicationBundle, error) {
+	ctx := context.TODO()
+	inNamespace := rclient.InNamespace(namespace)
+	bundle := &ApplicationBundle{Version: ApplicationBundleVersion}
+
+	if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &bundle.Application); err != nil {
+		return nil, fmt.Errorf("error when getting application %s from %s namespace: %v", name, namespace, err)
+	}
+	stripObjectMeta(&bundle.Application.ObjectMeta)
+	bundle.Application.Status = appservice.ApplicationStatus{}
+	// HAS creates new gitops and app model repositories for the imported application
+	bundle.Application.Spec.GitOpsRepository = appservice.ApplicationGitRepository{}
+	bundle.Application.Spec.AppModelRepository = appservice.ApplicationGitRepository{}
+
+	components := &appservice.ComponentList{}
+	if err := c.List(ctx, components, inNamespace); err != nil {
+		return nil, fmt.Errorf("error when listing components in %s namespace: %v", namespace, err)
+	}
+	for _, component := range components.Items {
+		if component.Spec.Application != name {
+			continue
+		}
+		stripObjectMeta(&component.ObjectMeta)
+		component.Status = appservice.ComponentStatus{}
+		bundle.Components = append(bundle.Components, component)
+	}
+
+	environments := &appservice.EnvironmentList{}
+	if err := c.List(ctx, environments, inNamespace); err != nil {
+		return nil, fmt.Errorf("error when listing environments in %s namespace: %v", namespace, err)
+	}
+	for _, environment := range environments.Items {
+		stripObjectMeta(&environment.ObjectMeta)
+		environment.Status = appservice.EnvironmentStatus{}
+		bundle.Environments = append(bundle.Environments, environment)
+	}
+
+	snapshots := &appservice.SnapshotList{}
+	if err := c.List(ctx, snapshots, inNamespace); err != nil {
+		return nil, fmt.Errorf("error when listing snapshots in %s namespace: %v", namespace, err)
+	}
+	for _, snapshot := range snapshots.Items {
+		if snapshot.Spec.Application != name {
+			continue
+		}
+		stripObjectMeta(&snapshot.ObjectMeta)
+		snapshot.Status = appservice.SnapshotStatus{}
+		bundle.Snapshots = append(bundle.Snapshots, snapshot)
+	}
+
+	bindings := &appservice.SnapshotEnvironmentBindingList{}
+	if err := c.List(ctx, bindings, inNamespace); err != nil {
+		return nil, fmt.Errorf("error when listing snapshotenvironmentbindings in %s namespace: %v", namespace, err)
+	}
+	for _, binding := range bindings.Items {
+		if binding.Spec.Application != name {
+			continue
+		}
+		stripObjectMeta(&binding.ObjectMeta)
+		binding.Status = appservice.SnapshotEnvironmentBindingStatus{}
+		bundle.SnapshotEnvironmentBindings = append(bundle.SnapshotEnvironmentBindings, binding)
+	}
+
+	scenarios := &integrationv1alpha1.IntegrationTestScenarioList{}
+	if err := c.List(ctx, scenarios, inNamespace); err != nil {
+		return nil, fmt.Errorf("error when listing integrationtestscenarios in %s namespace: %v", namespace, err)
+	}
+	for _, scenario := range scenarios.Items {
+		if scenario.Spec.Application != name {
+			continue
+		}
+		stripObjectMeta(&scenario.ObjectMeta)
+		scenario.Status = integrationv1alpha1.IntegrationTestScenarioStatus{}
+		bundle.IntegrationTestScenarios = append(bundle.IntegrationTestScenarios, scenario)
+	}
+
+	releasePlans := &releasev1alpha1.ReleasePlanList{}
+	if err := c.List(ctx, releasePlans, inNamespace); err != nil {
+		return nil, fmt.Errorf("error when listing releaseplans in %s namespace: %v", namespace, err)
+	}
+	for _, releasePlan := range releasePlans.Items {
+		if releasePlan.Spec.Application != name {
+			continue
+		}
+		stripObjectMeta(&releasePlan.ObjectMeta)
+		releasePlan.Status = releasev1alpha1.ReleasePlanStatus{}
+		bundle.ReleasePlans = append(bundle.ReleasePlans, releasePlan)
+	}
+
+	bundle.Secrets = bundle.referencedSecrets()
+	return bundle, nil
+}
+
+// ImportApplicationBundle creates the resources of a bundle using a given client. Environments are created first,
+// then the application, its components and the rest of the resources. Environments which already exist in the namespace
+// (e.g. "development") are reused. If the import fails, the resources created so far are deleted
+func ImportApplicationBundle(c rclient.Client, bundle *ApplicationBundle, opts ApplicationImportOptions) (*ApplicationBundle, error) {
+	if opts.Namespace == "" {
+		return nil, fmt.Errorf("the namespace to import application %s into is required", bundle.Application.Name)
+	}
+	imported := bundle.remap(opts)
+	ctx := context.TODO()
+
+	var created []rclient.Object
+	create := func(obj rclient.Object) error {
+		obj.SetNamespace(opts.Namespace)
+		if err := c.Create(ctx, obj); err != nil {
+			return fmt.Errorf("error when importing %T %s into %s namespace: %w", obj, obj.GetName(), opts.Namespace, err)
+		}
+		created = append(created, obj)
+		return nil
+	}
+	// fail deletes the created resources, in the reverse order of their creation
+	fail := func(err error) (*ApplicationBundle, error) {
+		for i := len(created) - 1; i >= 0; i-- {
+			if deleteErr := c.Delete(ctx, created[i]); deleteErr != nil && !k8sErrors.IsNotFound(deleteErr) {
+				err = fmt.Errorf("%v; error when deleting imported %T %s: %v", err, created[i], created[i].GetName(), deleteErr)
+			}
+		}
+		return nil, err
+	}
+
+	for i := range imported.Environments {
+		env := &imported.Environments[i]
+		err := create(env)
+		if err == nil {
+			continue
+		}
+		if !k8sErrors.IsAlreadyExists(err) {
+			return fail(err)
+		}
+		if err := c.Get(ctx, types.NamespacedName{Name: env.Name, Namespace: opts.Namespace}, env); err != nil {
+			return fail(fmt.Errorf("error when getting existing environment %s in %s namespace: %v", env.Name, opts.Namespace, err))
+		}
+	}
+	if err := create(&imported.Application); err != nil {
+		return fail(err)
+	}
+	if opts.ApplicationReadyTimeout > 0 {
+		err := utils.WaitUntil(func() (done bool, err error) {
+			application := &appservice.Application{}
+			if err := c.Get(ctx, types.NamespacedName{Name: imported.Application.Name, Namespace: opts.Namespace}, application); err != nil {
+				return false, nil
+			}
+			return application.Status.Devfile != "", nil
+		}, opts.ApplicationReadyTimeout)
+		if err != nil {
+			return fail(fmt.Errorf("timed out when waiting for imported application %s to be ready in %s namespace", imported.Application.Name, opts.Namespace))
+		}
+	}
+	for i := range imported.Components {
+		if err := create(&imported.Components[i]); err != nil {
+			return fail(err)
+		}
+	}
+	for i := range imported.Snapshots {
+		if err := create(&imported.Snapshots[i]); err != nil {
+			return fail(err)
+		}
+	}
+	for i := range imported.IntegrationTestScenarios {
+		if err := create(&imported.IntegrationTestScenarios[i]); err != nil {
+			return fail(err)
+		}
+	}
+	for i := range imported.ReleasePlans {
+		if err := create(&imported.ReleasePlans[i]); err != nil {
+			return fail(err)
+		}
+	}
+	for i := range imported.SnapshotEnvironmentBindings {
+		if err := create(&imported.SnapshotEnvironmentBindings[i]); err != nil {
+			return fail(err)
+		}
+	}
+	return imported, nil
+}
+
+// LoadApplicationBundle reads an ApplicationBundle from a YAML file
+func LoadApplicationBundle(path string) (*ApplicationBundle, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	bundle := &ApplicationBundle{}
+	if err := yaml.UnmarshalStrict(data, bundle); err != nil {
+		return nil, fmt.Errorf("error when parsing application bundle %s: %v", path, err)
+	}
+	if bundle.Version != ApplicationBundleVersion {
+		return nil, fmt.Errorf("unsupported application bundle version %q in %s, expected %q", bundle.Version, path, ApplicationBundleVersion)
+	}
+	return bundle, nil
+}
+
+// Save writes the bundle to a YAML file
+func (b *ApplicationBundle) Save(path string) error {
+	data, err := yaml.Marshal(b)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// remap returns a copy of the bundle with the resources renamed according to the import options
+func (b *ApplicationBundle) remap(opts ApplicationImportOptions) *ApplicationBundle {
+	rename := func(name string) string {
+		if name == "" {
+			return ""
+		}
+		if newName, ok := opts.Names[name]; ok {
+			return newName
+		}
+		if opts.NameSuffix != "" {
+			return fmt.Sprintf("%s-%s", name, opts.NameSuffix)
+		}
+		return name
+	}
+
+	r := &ApplicationBundle{Version: b.Version, Secrets: b.Secrets}
+
+	r.Application = *b.Application.DeepCopy()
+	r.Application.Name = rename(r.Application.Name)
+
+	for _, comp := range b.Components {
+		comp := *comp.DeepCopy()
+		comp.Name = rename(comp.Name)
+		comp.Spec.ComponentName = rename(comp.Spec.ComponentName)
+		comp.Spec.Application = rename(comp.Spec.Application)
+		r.Components = append(r.Components, comp)
+	}
+
+	// Parent environments need to be created before their children
+	environments := append([]appservice.Environment{}, b.Environments...)
+	sort.SliceStable(environments, func(i, j int) bool {
+		return environmentDepth(environments, environments[i].Name) < environmentDepth(environments, environments[j].Name)
+	})
+	for _, env := range environments {
+		env := *env.DeepCopy()
+		env.Name = rename(env.Name)
+		env.Spec.ParentEnvironment = rename(env.Spec.ParentEnvironment)
+		r.Environments = append(r.Environments, env)
+	}
+
+	for _, snapshot := range b.Snapshots {
+		snapshot := *snapshot.DeepCopy()
+		snapshot.Name = rename(snapshot.Name)
+		snapshot.Spec.Application = rename(snapshot.Spec.Application)
+		for i := range snapshot.Spec.Components {
+			snapshot.Spec.Components[i].Name = rename(snapshot.Spec.Components[i].Name)
+		}
+		r.Snapshots = append(r.Snapshots, snapshot)
+	}
+
+	for _, binding := range b.SnapshotEnvironmentBindings {
+		binding := *binding.DeepCopy()
+		binding.Name = rename(binding.Name)
+		binding.Spec.Application = rename(binding.Spec.Application)
+		binding.Spec.Environment = rename(binding.Spec.Environment)
+		binding.Spec.Snapshot = rename(binding.Spec.Snapshot)
+		for i := range binding.Spec.Components {
+			binding.Spec.Components[i].Name = rename(binding.Spec.Components[i].Name)
+		}
+		r.SnapshotEnvironmentBindings = append(r.SnapshotEnvironmentBindings, binding)
+	}
+
+	for _, scenario := range b.IntegrationTestScenarios {
+		scenario := *scenario.DeepCopy()
+		scenario.Name = rename(scenario.Name)
+		scenario.Spec.Application = rename(scenario.Spec.Application)
+		scenario.Spec.Environment.Name = rename(scenario.Spec.Environment.Name)
+		r.IntegrationTestScenarios = append(r.IntegrationTestScenarios, scenario)
+	}
+
+	for _, releasePlan := range b.ReleasePlans {
+		releasePlan := *releasePlan.DeepCopy()
+		releasePlan.Name = rename(releasePlan.Name)
+		releasePlan.Spec.Application = rename(releasePlan.Spec.Application)
+		r.ReleasePlans = append(r.ReleasePlans, releasePlan)
+	}
+	return r
+}
+
+// referencedSecrets returns the sorted names of the secrets referenced by the components and environments of the bundle
+func (b *ApplicationBundle) referencedSecrets() []string {
+	secrets := map[string]bool{}
+	for _, comp := range b.Components {
+		if comp.Spec.Secret != "" {
+			secrets[comp.Spec.Secret] = true
+		}
+	}
+	for _, env := range b.Environments {
+		if env.Spec.UnstableConfigurationFields != nil && env.Spec.UnstableConfigurationFields.ClusterCredentialsSecret != "" {
+			secrets[env.Spec.UnstableConfigurationFields.ClusterCredentialsSecret] = true
+		}
+	}
+	var names []string
+	for name := range secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func environmentDepth(environments []appservice.Environment, name string) int {
+	depth := 0
+	for visited := map[string]bool{}; !visited[name]; depth++ {
+		visited[name] = true
+		parent := ""
+		for _, env := range environments {
+			if env.Name == name {
+				parent = env.Spec.ParentEnvironment
+			}
+		}
+		if parent == "" {
+			return depth
+		}
+		name = parent
+	}
+	return depth
+}
+
+// Annotations holding the state controllers keep for the objects in the source namespace, e.g. the image repository
+// image-controller generated (with its push secret) or the PaC and build status. Imported into another namespace,
+// they would point the new objects to resources of the source namespace
+var (
+	controllerOwnedAnnotations = []string{
+		"kubectl.kubernetes.io/last-applied-configuration",
+		"image.redhat.com/image",
+		constants.ComponentInitialBuildAnnotationKey,
+	}
+	controllerOwnedAnnotationPrefixes = []string{"build.appstudio.openshift.io/"}
+)
+
+// stripObjectMeta keeps only the name, labels and annotations (except the controller owned ones) of an object
+func stripObjectMeta(meta *metav1.ObjectMeta) {
+	stripped := metav1.ObjectMeta{Name: meta.Name}
+	for k, v := range meta.Labels {
+		if stripped.Labels == nil {
+			stripped.Labels = map[string]string{}
+		}
+		stripped.Labels[k] = v
+	}
+	for k, v := range meta.Annotations {
+		if isControllerOwnedAnnotation(k) {
+			continue
+		}
+		if stripped.Annotations == nil {
+			stripped.Annotations = map[string]string{}
+		}
+		stripped.Annotations[k] = v
+	}
+	*meta = stripped
+}
+
+func isControllerOwnedAnnotation(key string) bool {
+	for _, annotation := range controllerOwnedAnnotations {
+		if key == annotation {
+			return true
+		}
+	}
+	for _, prefix := range controllerOwnedAnnotationPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
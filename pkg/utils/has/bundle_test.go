@@ -0,0 +1,232 @@
+package has
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	appservice "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	integrationv1alpha1 "github.com/redhat-appstudio/integration-service/api/v1alpha1"
+	releasev1alpha1 "github.com/redhat-appstudio/release-service/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newBundleTestClient(objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(appservice.AddToScheme(scheme))
+	utilruntime.Must(integrationv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(releasev1alpha1.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func bundleTestObjects() []client.Object {
+	meta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: "src", UID: types.UID(name), Finalizers: []string{"finalizer"}, Labels: map[string]string{"app": "demo"}}
+	}
+	return []client.Object{
+		&appservice.Application{
+			ObjectMeta: meta("demo"),
+			Spec:       appservice.ApplicationSpec{DisplayName: "demo", GitOpsRepository: appservice.ApplicationGitRepository{URL: "https://github.com/org/gitops"}},
+			Status:     appservice.ApplicationStatus{Devfile: "schemaVersion: 2.2.0"},
+		},
+		&appservice.Component{
+			ObjectMeta: meta("frontend"),
+			Spec:       appservice.ComponentSpec{ComponentName: "frontend", Application: "demo", Secret: "git-token"},
+			Status:     appservice.ComponentStatus{ContainerImage: "quay.io/org/frontend"},
+		},
+		&appservice.Component{
+			ObjectMeta: meta("other"),
+			Spec:       appservice.ComponentSpec{ComponentName: "other", Application: "other-app"},
+		},
+		&appservice.Environment{
+			ObjectMeta: meta("staging"),
+			Spec:       appservice.EnvironmentSpec{DisplayName: "staging", ParentEnvironment: "development"},
+		},
+		&appservice.Environment{
+			ObjectMeta: meta("development"),
+			Spec: appservice.EnvironmentSpec{DisplayName: "development", UnstableConfigurationFields: &appservice.UnstableEnvironmentConfiguration{
+				KubernetesClusterCredentials: appservice.KubernetesClusterCredentials{ClusterCredentialsSecret: "cluster-credentials"},
+			}},
+		},
+		&appservice.Snapshot{
+			ObjectMeta: meta("demo-snapshot"),
+			Spec:       appservice.SnapshotSpec{Application: "demo", Components: []appservice.SnapshotComponent{{Name: "frontend", ContainerImage: "quay.io/org/frontend"}}},
+		},
+		&appservice.SnapshotEnvironmentBinding{
+			ObjectMeta: meta("demo-development"),
+			Spec: appservice.SnapshotEnvironmentBindingSpec{Application: "demo", Environment: "development", Snapshot: "demo-snapshot",
+				Components: []appservice.BindingComponent{{Name: "frontend"}}},
+		},
+		&integrationv1alpha1.IntegrationTestScenario{
+			ObjectMeta: meta("demo-its"),
+			Spec:       integrationv1alpha1.IntegrationTestScenarioSpec{Application: "demo", Bundle: "quay.io/org/bundle", Pipeline: "integration"},
+		},
+		&releasev1alpha1.ReleasePlan{
+			ObjectMeta: meta("demo-release"),
+			Spec:       releasev1alpha1.ReleasePlanSpec{Application: "demo", Target: "managed"},
+		},
+	}
+}
+
+func TestExportApplicationBundle(t *testing.T) {
+	bundle, err := ExportApplicationBundle(newBundleTestClient(bundleTestObjects()...), "demo", "src")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "demo", bundle.Application.Name)
+	assert.Empty(t, bundle.Application.Namespace)
+	assert.Empty(t, bundle.Application.UID)
+	assert.Empty(t, bundle.Application.ResourceVersion)
+	assert.Empty(t, bundle.Application.Finalizers)
+	assert.Equal(t, "demo", bundle.Application.Labels["app"])
+	assert.Empty(t, bundle.Application.Status.Devfile)
+	assert.Empty(t, bundle.Application.Spec.GitOpsRepository.URL)
+
+	assert.Len(t, bundle.Components, 1)
+	assert.Equal(t, "frontend", bundle.Components[0].Name)
+	assert.Empty(t, bundle.Components[0].Status.ContainerImage)
+	assert.Len(t, bundle.Environments, 2)
+	assert.Len(t, bundle.Snapshots, 1)
+	assert.Len(t, bundle.SnapshotEnvironmentBindings, 1)
+	assert.Len(t, bundle.IntegrationTestScenarios, 1)
+	assert.Len(t, bundle.ReleasePlans, 1)
+	assert.Equal(t, []string{"cluster-credentials", "git-token"}, bundle.Secrets)
+
+	_, err = ExportApplicationBundle(newBundleTestClient(), "demo", "src")
+	assert.Error(t, err)
+}
+
+func TestExportApplicationBundleStripsControllerOwnedAnnotations(t *testing.T) {
+	component := &appservice.Component{
+		ObjectMeta: metav1.ObjectMeta{Name: "frontend", Namespace: "src", Annotations: map[string]string{
+			"image.redhat.com/generate":                        "true",
+			"image.redhat.com/image":                           `{"image": "quay.io/org/src/frontend", "secret": "frontend-push"}`,
+			"build.appstudio.openshift.io/status":              `{"pac": {"state": "enabled"}}`,
+			"build.appstudio.openshift.io/pipelinerun":         "frontend-abcd",
+			"appstudio.openshift.io/component-initial-build":   "processed",
+			"kubectl.kubernetes.io/last-applied-configuration": "{}",
+			"skip-initial-checks":                              "true",
+		}},
+		Spec: appservice.ComponentSpec{ComponentName: "frontend", Application: "demo"},
+	}
+	objs := append(bundleTestObjects()[:1], component)
+	bundle, err := ExportApplicationBundle(newBundleTestClient(objs...), "demo", "src")
+	assert.NoError(t, err)
+
+	assert.Len(t, bundle.Components, 1)
+	assert.Equal(t, map[string]string{"image.redhat.com/generate": "true", "skip-initial-checks": "true"}, bundle.Components[0].Annotations)
+}
+
+func TestApplicationBundleSaveAndLoad(t *testing.T) {
+	bundle, err := ExportApplicationBundle(newBundleTestClient(bundleTestObjects()...), "demo", "src")
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "bundle.yaml")
+	assert.NoError(t, bundle.Save(path))
+	loaded, err := LoadApplicationBundle(path)
+	assert.NoError(t, err)
+	assert.Equal(t, bundle, loaded)
+
+	loaded.Version = "v0"
+	assert.NoError(t, loaded.Save(path))
+	_, err = LoadApplicationBundle(path)
+	assert.Error(t, err)
+}
+
+func TestImportApplicationBundle(t *testing.T) {
+	bundle, err := ExportApplicationBundle(newBundleTestClient(bundleTestObjects()...), "demo", "src")
+	assert.NoError(t, err)
+
+	c := newBundleTestClient()
+	imported, err := ImportApplicationBundle(c, bundle, ApplicationImportOptions{
+		Namespace:  "dst",
+		NameSuffix: "copy",
+		Names:      map[string]string{"demo": "customer-app"},
+	})
+	assert.NoError(t, err)
+
+	// Parents are created before their children
+	assert.Equal(t, "development-copy", imported.Environments[0].Name)
+	assert.Equal(t, "staging-copy", imported.Environments[1].Name)
+	assert.Equal(t, "development-copy", imported.Environments[1].Spec.ParentEnvironment)
+
+	ctx := context.TODO()
+	application := &appservice.Application{}
+	assert.NoError(t, c.Get(ctx, types.NamespacedName{Name: "customer-app", Namespace: "dst"}, application))
+
+	component := &appservice.Component{}
+	assert.NoError(t, c.Get(ctx, types.NamespacedName{Name: "frontend-copy", Namespace: "dst"}, component))
+	assert.Equal(t, "customer-app", component.Spec.Application)
+	assert.Equal(t, "frontend-copy", component.Spec.ComponentName)
+	assert.Equal(t, "git-token", component.Spec.Secret)
+
+	snapshot := &appservice.Snapshot{}
+	assert.NoError(t, c.Get(ctx, types.NamespacedName{Name: "demo-snapshot-copy", Namespace: "dst"}, snapshot))
+	assert.Equal(t, "customer-app", snapshot.Spec.Application)
+	assert.Equal(t, "frontend-copy", snapshot.Spec.Components[0].Name)
+
+	binding := &appservice.SnapshotEnvironmentBinding{}
+	assert.NoError(t, c.Get(ctx, types.NamespacedName{Name: "demo-development-copy", Namespace: "dst"}, binding))
+	assert.Equal(t, "customer-app", binding.Spec.Application)
+	assert.Equal(t, "development-copy", binding.Spec.Environment)
+	assert.Equal(t, "demo-snapshot-copy", binding.Spec.Snapshot)
+	assert.Equal(t, "frontend-copy", binding.Spec.Components[0].Name)
+
+	scenario := &integrationv1alpha1.IntegrationTestScenario{}
+	assert.NoError(t, c.Get(ctx, types.NamespacedName{Name: "demo-its-copy", Namespace: "dst"}, scenario))
+	assert.Equal(t, "customer-app", scenario.Spec.Application)
+
+	releasePlan := &releasev1alpha1.ReleasePlan{}
+	assert.NoError(t, c.Get(ctx, types.NamespacedName{Name: "demo-release-copy", Namespace: "dst"}, releasePlan))
+	assert.Equal(t, "customer-app", releasePlan.Spec.Application)
+	assert.Equal(t, "managed", releasePlan.Spec.Target)
+
+	// The exported bundle is left untouched
+	assert.Equal(t, "demo", bundle.Application.Name)
+
+	_, err = ImportApplicationBundle(c, bundle, ApplicationImportOptions{})
+	assert.Error(t, err)
+}
+
+func TestImportApplicationBundleEnvironmentsAndRollback(t *testing.T) {
+	bundle, err := ExportApplicationBundle(newBundleTestClient(bundleTestObjects()...), "demo", "src")
+	assert.NoError(t, err)
+	ctx := context.TODO()
+
+	// Existing environments are reused
+	development := &appservice.Environment{
+		ObjectMeta: metav1.ObjectMeta{Name: "development", Namespace: "dst"},
+		Spec:       appservice.EnvironmentSpec{DisplayName: "existing"},
+	}
+	c := newBundleTestClient(development)
+	imported, err := ImportApplicationBundle(c, bundle, ApplicationImportOptions{Namespace: "dst"})
+	assert.NoError(t, err)
+	assert.Equal(t, "existing", imported.Environments[0].Spec.DisplayName)
+	assert.NoError(t, c.Get(ctx, types.NamespacedName{Name: "staging", Namespace: "dst"}, &appservice.Environment{}))
+
+	// A failed import deletes the resources it created, but not the reused environments
+	conflicting := &releasev1alpha1.ReleasePlan{ObjectMeta: metav1.ObjectMeta{Name: "demo-release", Namespace: "dst"}}
+	c = newBundleTestClient(development, conflicting)
+	_, err = ImportApplicationBundle(c, bundle, ApplicationImportOptions{Namespace: "dst"})
+	assert.ErrorContains(t, err, "demo-release")
+
+	environments := &appservice.EnvironmentList{}
+	assert.NoError(t, c.List(ctx, environments))
+	assert.Len(t, environments.Items, 1)
+	assert.Equal(t, "development", environments.Items[0].Name)
+	applications := &appservice.ApplicationList{}
+	assert.NoError(t, c.List(ctx, applications))
+	assert.Empty(t, applications.Items)
+	components := &appservice.ComponentList{}
+	assert.NoError(t, c.List(ctx, components))
+	assert.Empty(t, components.Items)
+	snapshots := &appservice.SnapshotList{}
+	assert.NoError(t, c.List(ctx, snapshots))
+	assert.Empty(t, snapshots.Items)
+	assert.NoError(t, c.Get(ctx, types.NamespacedName{Name: "demo-release", Namespace: "dst"}, &releasev1alpha1.ReleasePlan{}))
+}
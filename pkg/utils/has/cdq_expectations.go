@@ -0,0 +1,147 @@
+package has
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	appservice "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	"gopkg.in/yaml.v2"
+)
+
+// ComponentDetectionCorpus is a set of source repositories with the results a ComponentDetectionQuery is expected to detect
+type ComponentDetectionCorpus struct {
+	Repositories []ComponentDetectionCase `yaml:"repositories"`
+}
+
+// ComponentDetectionCase is a source repository along with the components expected to be detected in it
+type ComponentDetectionCase struct {
+	// Unique name of the case, used as a Ginkgo table entry description
+	Name string `yaml:"name"`
+
+	// Repository URL, revision and relative path inside the repository to run the detection on
+	URL      string `yaml:"url"`
+	Revision string `yaml:"revision,omitempty"`
+	Context  string `yaml:"context,omitempty"`
+
+	// Set if the repository can only be accessed with a token
+	Private bool `yaml:"private,omitempty"`
+
+	// If not empty, the case is skipped with the given reason
+	Skip string `yaml:"skip,omitempty"`
+
+	// Components expected to be detected. The number of detected components must match
+	Components []ExpectedDetectedComponent `yaml:"components"`
+}
+
+// ExpectedDetectedComponent holds the expected detection results of a component. Empty fields are not checked
+type ExpectedDetectedComponent struct {
+	// Relative path of the component in the repository. Required if more than one component is expected
+	Context string `yaml:"context,omitempty"`
+
+	Language        string `yaml:"language,omitempty"`
+	ProjectType     string `yaml:"projectType,omitempty"`
+	DevfileFound    *bool  `yaml:"devfileFound,omitempty"`
+	DockerfileFound *bool  `yaml:"dockerfileFound,omitempty"`
+	TargetPort      int    `yaml:"targetPort,omitempty"`
+}
+
+// ParseComponentDetectionCorpus parses and validates a YAML corpus of ComponentDetectionQuery expectations
+func ParseComponentDetectionCorpus(data []byte) (*ComponentDetectionCorpus, error) {
+	corpus := &ComponentDetectionCorpus{}
+	if err := yaml.UnmarshalStrict(data, corpus); err != nil {
+		return nil, fmt.Errorf("error when parsing component detection corpus: %v", err)
+	}
+
+	names := map[string]bool{}
+	for _, c := range corpus.Repositories {
+		if c.Name == "" || c.URL == "" {
+			return nil, fmt.Errorf("component detection case %q: name and url are required", c.Name)
+		}
+		if names[c.Name] {
+			return nil, fmt.Errorf("component detection case %q is defined more than once", c.Name)
+		}
+		names[c.Name] = true
+		if len(c.Components) == 0 {
+			return nil, fmt.Errorf("component detection case %q: at least one component is expected", c.Name)
+		}
+		if len(c.Components) > 1 {
+			for _, comp := range c.Components {
+				if comp.Context == "" {
+					return nil, fmt.Errorf("component detection case %q: context is required for each of the expected components", c.Name)
+				}
+			}
+		}
+	}
+	return corpus, nil
+}
+
+// VerifyComponentDetection compares the components detected by a ComponentDetectionQuery with the expected ones.
+// The returned error lists every mismatching field
+func VerifyComponentDetection(cdq *appservice.ComponentDetectionQuery, expected ComponentDetectionCase) error {
+	detected := cdq.Status.ComponentDetected
+	if len(detected) != len(expected.Components) {
+		return fmt.Errorf("expected %d components to be detected in %s, got %d: %s", len(expected.Components), expected.URL, len(detected), describeDetectedComponents(detected))
+	}
+
+	var mismatches []string
+	for _, exp := range expected.Components {
+		name, comp, found := findDetectedComponent(detected, exp.Context)
+		if !found {
+			mismatches = append(mismatches, fmt.Sprintf("no component detected in context %q", exp.Context))
+			continue
+		}
+		mismatch := func(field string, expected, actual interface{}) {
+			mismatches = append(mismatches, fmt.Sprintf("component %s: %s: expected %v, got %v", name, field, expected, actual))
+		}
+
+		if exp.Language != "" && exp.Language != comp.Language {
+			mismatch("language", exp.Language, comp.Language)
+		}
+		if exp.ProjectType != "" && exp.ProjectType != comp.ProjectType {
+			mismatch("projectType", exp.ProjectType, comp.ProjectType)
+		}
+		if exp.DevfileFound != nil && *exp.DevfileFound != comp.DevfileFound {
+			mismatch("devfileFound", *exp.DevfileFound, comp.DevfileFound)
+		}
+		dockerfileFound := comp.ComponentStub.Source.GitSource != nil && comp.ComponentStub.Source.GitSource.DockerfileURL != ""
+		if exp.DockerfileFound != nil && *exp.DockerfileFound != dockerfileFound {
+			mismatch("dockerfileFound", *exp.DockerfileFound, dockerfileFound)
+		}
+		if exp.TargetPort != 0 && exp.TargetPort != comp.ComponentStub.TargetPort {
+			mismatch("targetPort", exp.TargetPort, comp.ComponentStub.TargetPort)
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("component detection of %s doesn't match the expectations:\n  %s", expected.URL, strings.Join(mismatches, "\n  "))
+	}
+	return nil
+}
+
+// findDetectedComponent returns the detected component with a given context. An empty context matches the only detected component
+func findDetectedComponent(detected appservice.ComponentDetectionMap, context string) (string, appservice.ComponentDetectionDescription, bool) {
+	for name, comp := range detected {
+		if context == "" && len(detected) == 1 {
+			return name, comp, true
+		}
+		gitSource := comp.ComponentStub.Source.GitSource
+		if gitSource != nil && strings.Trim(gitSource.Context, "./") == strings.Trim(context, "./") {
+			return name, comp, true
+		}
+	}
+	return "", appservice.ComponentDetectionDescription{}, false
+}
+
+func describeDetectedComponents(detected appservice.ComponentDetectionMap) string {
+	var descriptions []string
+	for name, comp := range detected {
+		context := ""
+		if comp.ComponentStub.Source.GitSource != nil {
+			context = comp.ComponentStub.Source.GitSource.Context
+		}
+		descriptions = append(descriptions, fmt.Sprintf("%s (context %q, language %q, projectType %q)", name, context, comp.Language, comp.ProjectType))
+	}
+	sort.Strings(descriptions)
+	return strings.Join(descriptions, ", ")
+}
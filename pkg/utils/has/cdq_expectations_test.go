@@ -0,0 +1,81 @@
+package has
+
+import (
+	"testing"
+
+	appservice "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseComponentDetectionCorpus(t *testing.T) {
+	corpus, err := ParseComponentDetectionCorpus([]byte(`
+repositories:
+  - name: quarkus
+    url: https://github.com/org/quarkus
+    components:
+      - language: Java
+        devfileFound: true
+`))
+	assert.NoError(t, err)
+	assert.Len(t, corpus.Repositories, 1)
+	assert.True(t, *corpus.Repositories[0].Components[0].DevfileFound)
+	assert.Nil(t, corpus.Repositories[0].Components[0].DockerfileFound)
+
+	for name, data := range map[string]string{
+		"unknown field":         "repositories:\n  - name: a\n    url: u\n    languages: [Java]\n",
+		"missing url":           "repositories:\n  - name: a\n    components: [{language: Java}]\n",
+		"no components":         "repositories:\n  - name: a\n    url: u\n",
+		"duplicate name":        "repositories:\n  - {name: a, url: u, components: [{language: Java}]}\n  - {name: a, url: u, components: [{language: Java}]}\n",
+		"multi without context": "repositories:\n  - {name: a, url: u, components: [{context: a}, {language: Go}]}\n",
+	} {
+		_, err := ParseComponentDetectionCorpus([]byte(data))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestVerifyComponentDetection(t *testing.T) {
+	devfileFound := true
+	dockerfileFound := false
+	cdq := &appservice.ComponentDetectionQuery{
+		Status: appservice.ComponentDetectionQueryStatus{
+			ComponentDetected: appservice.ComponentDetectionMap{
+				"backend": {
+					Language:    "Go",
+					ProjectType: "Go",
+					ComponentStub: appservice.ComponentSpec{
+						Source: appservice.ComponentSource{ComponentSourceUnion: appservice.ComponentSourceUnion{
+							GitSource: &appservice.GitSource{Context: "./backend", DockerfileURL: "Dockerfile"},
+						}},
+						TargetPort: 8080,
+					},
+				},
+				"frontend": {
+					Language:     "JavaScript",
+					DevfileFound: true,
+					ComponentStub: appservice.ComponentSpec{
+						Source: appservice.ComponentSource{ComponentSourceUnion: appservice.ComponentSourceUnion{
+							GitSource: &appservice.GitSource{Context: "frontend"},
+						}},
+					},
+				},
+			},
+		},
+	}
+
+	assert.NoError(t, VerifyComponentDetection(cdq, ComponentDetectionCase{URL: "u", Components: []ExpectedDetectedComponent{
+		{Context: "backend", Language: "Go", TargetPort: 8080},
+		{Context: "frontend", Language: "JavaScript", DevfileFound: &devfileFound, DockerfileFound: &dockerfileFound},
+	}}))
+
+	err := VerifyComponentDetection(cdq, ComponentDetectionCase{URL: "u", Components: []ExpectedDetectedComponent{
+		{Context: "backend", Language: "Java", TargetPort: 8081, DockerfileFound: &dockerfileFound},
+		{Context: "frontend", ProjectType: "Node.js"},
+	}})
+	assert.ErrorContains(t, err, "component backend: language: expected Java, got Go")
+	assert.ErrorContains(t, err, "component backend: targetPort: expected 8081, got 8080")
+	assert.ErrorContains(t, err, "component backend: dockerfileFound: expected false, got true")
+	assert.ErrorContains(t, err, "component frontend: projectType: expected Node.js, got ")
+
+	err = VerifyComponentDetection(cdq, ComponentDetectionCase{URL: "u", Components: []ExpectedDetectedComponent{{Language: "Go"}}})
+	assert.ErrorContains(t, err, "expected 1 components to be detected in u, got 2")
+}
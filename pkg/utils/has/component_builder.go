@@ -0,0 +1,294 @@
+package has
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	appservice "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultComponentReplicas     = 1
+	defaultComponentTargetPort   = 8081
+	defaultComponentReadyTimeout = 10 * time.Minute
+)
+
+// ComponentBuilder creates HAS Components from any supported source: a git repository (optionally with a devfile or dockerfile URL),
+// a devfile URL, a container image or a stub detected by a ComponentDetectionQuery.
+// Combinations are validated when the component is built, e.g.:
+//
+//	component, err := f.AsKubeAdmin.HasController.NewComponentBuilder(applicationName, componentName, namespace).
+//		WithGitSource(gitURL, "main").
+//		WithOutputImage(outputImage).
+//		WithPaC().
+//		Create()
+type ComponentBuilder struct {
+	h *SuiteController
+
+	component appservice.Component
+
+	// Image to deploy the component from, instead of building it from a git source
+	containerImageSource string
+	outputImage          string
+	skipInitialChecks    *bool
+	pacEnabled           bool
+	fromStub             bool
+
+	readyTimeout time.Duration
+	waitForReady bool
+}
+
+// NewComponentBuilder returns a builder for a component with a given name in an application. By default the component
+// gets one replica, listens on port 8081 and has the constants.ComponentDefaultLabel, except for components provisioned
+// by Pipelines as Code which are left to the build service, and stubs which only get the default port.
+// Create waits up to 10 minutes for the component to be ready
+func (h *SuiteController) NewComponentBuilder(applicationName, componentName, namespace string) *ComponentBuilder {
+	return &ComponentBuilder{
+		h: h,
+		component: appservice.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        componentName,
+				Namespace:   namespace,
+				Labels:      map[string]string{},
+				Annotations: map[string]string{},
+			},
+			Spec: appservice.ComponentSpec{
+				ComponentName: componentName,
+				Application:   applicationName,
+			},
+		},
+		readyTimeout: defaultComponentReadyTimeout,
+		waitForReady: true,
+	}
+}
+
+// FromStub uses a component stub detected by a ComponentDetectionQuery as a base for the component.
+// Name and application of the builder take precedence over the ones from the stub
+func (b *ComponentBuilder) FromStub(stub appservice.ComponentSpec) *ComponentBuilder {
+	spec := *stub.DeepCopy()
+	spec.ComponentName = b.component.Spec.ComponentName
+	spec.Application = b.component.Spec.Application
+	b.component.Spec = spec
+	b.fromStub = true
+	return b
+}
+
+// WithGitSource builds the component from a git repository. An empty revision means the default branch
+func (b *ComponentBuilder) WithGitSource(url, revision string) *ComponentBuilder {
+	gitSource := b.gitSource()
+	gitSource.URL = url
+	gitSource.Revision = revision
+	return b
+}
+
+// WithContext sets the directory of the git repository which contains the component
+func (b *ComponentBuilder) WithContext(context string) *ComponentBuilder {
+	b.gitSource().Context = context
+	return b
+}
+
+// WithDevfileURL builds the component using the devfile from a given URL
+func (b *ComponentBuilder) WithDevfileURL(url string) *ComponentBuilder {
+	b.gitSource().DevfileURL = url
+	return b
+}
+
+// WithDockerfileURL builds the component using the dockerfile from a given URL
+func (b *ComponentBuilder) WithDockerfileURL(url string) *ComponentBuilder {
+	b.gitSource().DockerfileURL = url
+	return b
+}
+
+// WithContainerImageSource deploys the component from an existing container image, without building it
+func (b *ComponentBuilder) WithContainerImageSource(image string) *ComponentBuilder {
+	b.containerImageSource = image
+	return b
+}
+
+// WithOutputImage sets the image repository the component build pushes to
+func (b *ComponentBuilder) WithOutputImage(image string) *ComponentBuilder {
+	b.outputImage = image
+	return b
+}
+
+// WithPaC requests Pipelines as Code provisioning for the component, so builds get triggered by git events
+func (b *ComponentBuilder) WithPaC() *ComponentBuilder {
+	b.pacEnabled = true
+	return b
+}
+
+// WithSkipInitialChecks sets the "skip-initial-checks" annotation. If true, only the basic build pipeline tasks are run (PLNSRVCE-957)
+func (b *ComponentBuilder) WithSkipInitialChecks(skip bool) *ComponentBuilder {
+	b.skipInitialChecks = &skip
+	return b
+}
+
+// WithGeneratedImageRepository asks the image controller to generate an image repository for the component,
+// which gets deleted together with the component
+func (b *ComponentBuilder) WithGeneratedImageRepository() *ComponentBuilder {
+	return b.WithAnnotations(map[string]string{
+		"image.redhat.com/generate":          "true",
+		"image.redhat.com/delete-image-repo": "true",
+	})
+}
+
+// WithAnnotations adds annotations to the component
+func (b *ComponentBuilder) WithAnnotations(annotations map[string]string) *ComponentBuilder {
+	for k, v := range annotations {
+		b.component.Annotations[k] = v
+	}
+	return b
+}
+
+// WithLabels adds labels to the component
+func (b *ComponentBuilder) WithLabels(labels map[string]string) *ComponentBuilder {
+	for k, v := range labels {
+		b.component.Labels[k] = v
+	}
+	return b
+}
+
+// WithEnv adds an environment variable to the deployed component
+func (b *ComponentBuilder) WithEnv(name, value string) *ComponentBuilder {
+	b.component.Spec.Env = append(b.component.Spec.Env, corev1.EnvVar{Name: name, Value: value})
+	return b
+}
+
+// WithResources sets the compute resources of the deployed component
+func (b *ComponentBuilder) WithResources(resources corev1.ResourceRequirements) *ComponentBuilder {
+	b.component.Spec.Resources = resources
+	return b
+}
+
+// WithReplicas sets the number of replicas of the deployed component
+func (b *ComponentBuilder) WithReplicas(replicas int) *ComponentBuilder {
+	b.component.Spec.Replicas = replicas
+	return b
+}
+
+// WithTargetPort sets the port the deployed component listens on
+func (b *ComponentBuilder) WithTargetPort(port int) *ComponentBuilder {
+	b.component.Spec.TargetPort = port
+	return b
+}
+
+// WithSecret sets the secret used to access a private git repository or container image
+func (b *ComponentBuilder) WithSecret(secret string) *ComponentBuilder {
+	b.component.Spec.Secret = secret
+	return b
+}
+
+// WithReadyTimeout sets how long Create waits for the component to be ready
+func (b *ComponentBuilder) WithReadyTimeout(timeout time.Duration) *ComponentBuilder {
+	b.readyTimeout = timeout
+	return b
+}
+
+// WithoutWaitingForReady makes Create return as soon as the component is created
+func (b *ComponentBuilder) WithoutWaitingForReady() *ComponentBuilder {
+	b.waitForReady = false
+	return b
+}
+
+// Build validates the builder settings and returns the component without creating it
+func (b *ComponentBuilder) Build() (*appservice.Component, error) {
+	component := b.component.DeepCopy()
+	gitSource := component.Spec.Source.GitSource
+
+	if component.Name == "" || component.Namespace == "" || component.Spec.Application == "" {
+		return nil, fmt.Errorf("component name, namespace and application are required")
+	}
+	if gitSource != nil && gitSource.URL == "" && gitSource.DevfileURL == "" {
+		return nil, fmt.Errorf("component %s: a git source needs a repository URL or a devfile URL", component.Name)
+	}
+	if gitSource != nil && gitSource.DevfileURL != "" && gitSource.DockerfileURL != "" {
+		return nil, fmt.Errorf("component %s: a devfile URL can't be combined with a dockerfile URL", component.Name)
+	}
+	if gitSource == nil && b.containerImageSource == "" {
+		return nil, fmt.Errorf("component %s: either a git source, a devfile URL or a container image source is required", component.Name)
+	}
+	if gitSource != nil && b.containerImageSource != "" {
+		return nil, fmt.Errorf("component %s: a git source can't be combined with a container image source", component.Name)
+	}
+	if b.containerImageSource != "" && b.outputImage != "" {
+		return nil, fmt.Errorf("component %s: a component deployed from a container image source isn't built, so it can't have an output image", component.Name)
+	}
+	if b.pacEnabled && (gitSource == nil || gitSource.URL == "") {
+		return nil, fmt.Errorf("component %s: Pipelines as Code provisioning requires a git repository URL", component.Name)
+	}
+	if component.Spec.Replicas < 0 {
+		return nil, fmt.Errorf("component %s: invalid number of replicas %d", component.Name, component.Spec.Replicas)
+	}
+	if component.Spec.TargetPort < 0 || component.Spec.TargetPort > 65535 {
+		return nil, fmt.Errorf("component %s: invalid target port %d", component.Name, component.Spec.TargetPort)
+	}
+
+	if b.containerImageSource != "" {
+		component.Spec.ContainerImage = b.containerImageSource
+	} else if b.outputImage != "" {
+		component.Spec.ContainerImage = b.outputImage
+	}
+	if !b.pacEnabled {
+		if component.Spec.TargetPort == 0 {
+			component.Spec.TargetPort = defaultComponentTargetPort
+		}
+		if !b.fromStub {
+			if component.Spec.Replicas == 0 {
+				component.Spec.Replicas = defaultComponentReplicas
+			}
+			for k, v := range constants.ComponentDefaultLabel {
+				if _, ok := component.Labels[k]; !ok {
+					component.Labels[k] = v
+				}
+			}
+		}
+	}
+	if b.skipInitialChecks != nil {
+		component.Annotations["skip-initial-checks"] = strconv.FormatBool(*b.skipInitialChecks)
+	}
+	if b.pacEnabled {
+		for k, v := range constants.ComponentPaCRequestAnnotation {
+			component.Annotations[k] = v
+		}
+	}
+	return component, nil
+}
+
+// Create builds the component, creates it in the cluster and (unless disabled) waits for it to be ready.
+// Components built from source get the custom pipeline bundles exposed in CUSTOM_<PIPELINE>_PIPELINE_BUILD_BUNDLE env vars
+// through a build pipeline selector created in their namespace
+func (b *ComponentBuilder) Create() (*appservice.Component, error) {
+	component, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	// Components built from source use the custom pipeline bundles the build pipelines got overridden with
+	if component.Spec.Source.GitSource != nil {
+		if err := b.h.CreateCustomBuildPipelineSelector(component.Namespace); err != nil {
+			return nil, err
+		}
+	}
+	if err := b.h.KubeRest().Create(context.TODO(), component); err != nil {
+		return nil, err
+	}
+	if !b.waitForReady {
+		return component, nil
+	}
+	if err = utils.WaitUntil(b.h.ComponentReady(component), b.readyTimeout); err != nil {
+		return nil, fmt.Errorf("timed out when waiting for component %s to be ready in %s namespace. component: %s", component.Name, component.Namespace, utils.ToPrettyJSONString(component))
+	}
+	return component, nil
+}
+
+func (b *ComponentBuilder) gitSource() *appservice.GitSource {
+	if b.component.Spec.Source.GitSource == nil {
+		b.component.Spec.Source.GitSource = &appservice.GitSource{}
+	}
+	return b.component.Spec.Source.GitSource
+}
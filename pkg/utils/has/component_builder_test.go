@@ -0,0 +1,100 @@
+package has
+
+import (
+	"testing"
+
+	appservice "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComponentBuilderDefaults(t *testing.T) {
+	h := &SuiteController{}
+
+	component, err := h.NewComponentBuilder("app", "comp", "ns").
+		WithGitSource("https://github.com/org/repo", "main").
+		WithOutputImage("quay.io/org/image").
+		WithSkipInitialChecks(true).
+		WithEnv("FOO", "bar").
+		Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "comp", component.Spec.ComponentName)
+	assert.Equal(t, "app", component.Spec.Application)
+	assert.Equal(t, "https://github.com/org/repo", component.Spec.Source.GitSource.URL)
+	assert.Equal(t, "main", component.Spec.Source.GitSource.Revision)
+	assert.Equal(t, "quay.io/org/image", component.Spec.ContainerImage)
+	assert.Equal(t, 1, component.Spec.Replicas)
+	assert.Equal(t, 8081, component.Spec.TargetPort)
+	assert.Equal(t, "true", component.Annotations["skip-initial-checks"])
+	assert.Equal(t, "true", component.Labels["e2e-test"])
+	assert.Equal(t, "bar", component.Spec.Env[0].Value)
+	assert.Len(t, constants.ComponentDefaultLabel, 1, "the default labels must not be modified")
+}
+
+func TestComponentBuilderWithPaC(t *testing.T) {
+	h := &SuiteController{}
+
+	component, err := h.NewComponentBuilder("app", "comp", "ns").
+		WithGitSource("https://github.com/org/repo", "main").
+		WithOutputImage("quay.io/org/image").
+		WithPaC().
+		Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "request", component.Annotations["appstudio.openshift.io/pac-provision"])
+	// Deployment settings of PaC components are left to the build service
+	assert.Zero(t, component.Spec.Replicas)
+	assert.Zero(t, component.Spec.TargetPort)
+	assert.Empty(t, component.Labels)
+}
+
+func TestComponentBuilderFromStub(t *testing.T) {
+	h := &SuiteController{}
+	stub := appservice.ComponentSpec{
+		ComponentName: "detected",
+		Source: appservice.ComponentSource{
+			ComponentSourceUnion: appservice.ComponentSourceUnion{
+				GitSource: &appservice.GitSource{URL: "https://github.com/org/repo", DevfileURL: "https://example.com/devfile.yaml"},
+			},
+		},
+		TargetPort: 8080,
+	}
+
+	component, err := h.NewComponentBuilder("app", "comp", "ns").FromStub(stub).WithSecret("secret").WithReplicas(2).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, "comp", component.Spec.ComponentName)
+	assert.Equal(t, "https://example.com/devfile.yaml", component.Spec.Source.GitSource.DevfileURL)
+	assert.Equal(t, 8080, component.Spec.TargetPort)
+	assert.Equal(t, 2, component.Spec.Replicas)
+	assert.Equal(t, "secret", component.Spec.Secret)
+	assert.Equal(t, "detected", stub.ComponentName, "the stub must not be modified")
+	assert.Empty(t, component.Labels)
+
+	stub.TargetPort = 0
+	component, err = h.NewComponentBuilder("app", "comp", "ns").FromStub(stub).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, 8081, component.Spec.TargetPort)
+	assert.Zero(t, component.Spec.Replicas)
+}
+
+func TestComponentBuilderValidation(t *testing.T) {
+	h := &SuiteController{}
+	tests := []struct {
+		name    string
+		builder *ComponentBuilder
+	}{
+		{"no source", h.NewComponentBuilder("app", "comp", "ns")},
+		{"no name", h.NewComponentBuilder("app", "", "ns").WithContainerImageSource("quay.io/org/image")},
+		{"git and image source", h.NewComponentBuilder("app", "comp", "ns").WithGitSource("https://github.com/org/repo", "").WithContainerImageSource("quay.io/org/image")},
+		{"image source with output image", h.NewComponentBuilder("app", "comp", "ns").WithContainerImageSource("quay.io/org/image").WithOutputImage("quay.io/org/output")},
+		{"devfile and dockerfile", h.NewComponentBuilder("app", "comp", "ns").WithGitSource("https://github.com/org/repo", "").WithDevfileURL("devfile.yaml").WithDockerfileURL("Dockerfile")},
+		{"PaC without git repository", h.NewComponentBuilder("app", "comp", "ns").WithDevfileURL("https://example.com/devfile.yaml").WithPaC()},
+		{"context without git repository", h.NewComponentBuilder("app", "comp", "ns").WithContext("dir")},
+		{"invalid port", h.NewComponentBuilder("app", "comp", "ns").WithContainerImageSource("quay.io/org/image").WithTargetPort(70000)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.builder.Build()
+			assert.Error(t, err)
+		})
+	}
+}
@@ -4,18 +4,17 @@ import (
 	"context"
 	"fmt"
 	"math"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils/common"
-	"knative.dev/pkg/apis"
 
 	. "github.com/onsi/ginkgo/v2"
 	routev1 "github.com/openshift/api/route/v1"
 	appservice "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	"github.com/redhat-appstudio/e2e-tests/pkg/apis/git"
 	"github.com/redhat-appstudio/e2e-tests/pkg/apis/github"
 	kubeCl "github.com/redhat-appstudio/e2e-tests/pkg/apis/kubernetes"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils/tekton"
@@ -31,7 +30,9 @@ import (
 )
 
 type SuiteController struct {
-	Github *github.Github
+	// Git provider hosting the gitops repositories. HAS always creates them in its GitHub organization,
+	// whatever the git provider of the component sources is, so it is not configured by GIT_PROVIDER
+	GitOps git.GitProvider
 	*kubeCl.CustomClient
 }
 
@@ -44,7 +45,7 @@ func NewSuiteController(kube *kubeCl.CustomClient) (*SuiteController, error) {
 		return nil, err
 	}
 	return &SuiteController{
-		gh,
+		git.NewGitHubProvider(gh),
 		kube,
 	}, nil
 }
@@ -168,52 +169,6 @@ func (h *SuiteController) DeleteHasComponent(name string, namespace string, repo
 	return utils.WaitUntil(h.ComponentDeleted(&component), 1*time.Minute)
 }
 
-// CreateComponent create an has component from a given name, namespace, application, devfile and a container image
-func (h *SuiteController) CreateComponent(applicationName, componentName, namespace, gitSourceURL, gitSourceRevision, containerImageSource, outputContainerImage, secret string, skipInitialChecks bool) (*appservice.Component, error) {
-	var containerImage string
-	if outputContainerImage != "" {
-		containerImage = outputContainerImage
-	} else {
-		containerImage = containerImageSource
-	}
-	component := &appservice.Component{
-		ObjectMeta: metav1.ObjectMeta{
-			Annotations: map[string]string{
-				// PLNSRVCE-957 - if true, run only basic build pipeline tasks
-				"skip-initial-checks": strconv.FormatBool(skipInitialChecks),
-			},
-			Labels:    constants.ComponentDefaultLabel,
-			Name:      componentName,
-			Namespace: namespace,
-		},
-		Spec: appservice.ComponentSpec{
-			ComponentName: componentName,
-			Application:   applicationName,
-			Source: appservice.ComponentSource{
-				ComponentSourceUnion: appservice.ComponentSourceUnion{
-					GitSource: &appservice.GitSource{
-						URL:      gitSourceURL,
-						Revision: gitSourceRevision,
-					},
-				},
-			},
-			Secret:         secret,
-			ContainerImage: containerImage,
-			Replicas:       1,
-			TargetPort:     8081,
-			Route:          "",
-		},
-	}
-	err := h.KubeRest().Create(context.TODO(), component)
-	if err != nil {
-		return nil, err
-	}
-	if err = utils.WaitUntil(h.ComponentReady(component), time.Minute*10); err != nil {
-		return nil, fmt.Errorf("timed out when waiting for component %s to be ready in %s namespace. component: %s", componentName, namespace, utils.ToPrettyJSONString(component))
-	}
-	return component, nil
-}
-
 func (h *SuiteController) ComponentReady(component *appservice.Component) wait.ConditionFunc {
 	return func() (bool, error) {
 		messages, err := h.GetHasComponentConditionStatusMessages(component.Name, component.Namespace)
@@ -236,71 +191,6 @@ func (h *SuiteController) ComponentDeleted(component *appservice.Component) wait
 	}
 }
 
-// CreateComponentWithPaCEnabled creates a component with "pipelinesascode: '1'" annotation that is used for triggering PaC builds
-func (h *SuiteController) CreateComponentWithPaCEnabled(applicationName, componentName, namespace, gitSourceURL, baseBranch, outputContainerImage string) (*appservice.Component, error) {
-	component := &appservice.Component{
-		ObjectMeta: metav1.ObjectMeta{
-			Annotations: constants.ComponentPaCRequestAnnotation,
-			Name:        componentName,
-			Namespace:   namespace,
-		},
-		Spec: appservice.ComponentSpec{
-			ComponentName: componentName,
-			Application:   applicationName,
-			Source: appservice.ComponentSource{
-				ComponentSourceUnion: appservice.ComponentSourceUnion{
-					GitSource: &appservice.GitSource{
-						URL:      gitSourceURL,
-						Revision: baseBranch,
-					},
-				},
-			},
-			ContainerImage: outputContainerImage,
-		},
-	}
-	err := h.KubeRest().Create(context.TODO(), component)
-	if err != nil {
-		return nil, err
-	}
-	if err = utils.WaitUntil(h.ComponentReady(component), time.Minute*10); err != nil {
-		return nil, fmt.Errorf("timed out when waiting for component %s to be ready in %s namespace. component: %s", componentName, namespace, utils.ToPrettyJSONString(component))
-	}
-	return component, nil
-}
-
-// CreateComponentFromCDQ create a HAS Component resource from a Completed CDQ resource, which includes a stub Component CR
-// The Component from the CDQ is only a template, and needs things like name filled in
-func (h *SuiteController) CreateComponentFromStub(compDetected appservice.ComponentDetectionDescription, componentName, namespace, secret, applicationName string) (*appservice.Component, error) {
-	component := &appservice.Component{
-		ObjectMeta: metav1.ObjectMeta{
-			Annotations: map[string]string{
-				"skip-initial-checks":                "true",
-				"image.redhat.com/generate":          "true",
-				"image.redhat.com/delete-image-repo": "true",
-			},
-			Name:      compDetected.ComponentStub.ComponentName,
-			Namespace: namespace,
-		},
-		Spec: compDetected.ComponentStub,
-	}
-
-	if component.Spec.TargetPort == 0 {
-		component.Spec.TargetPort = 8081
-	}
-
-	component.Spec.Secret = secret
-	component.Spec.Application = applicationName
-
-	err := h.KubeRest().Create(context.TODO(), component)
-	if err != nil {
-		return nil, err
-	}
-	if err = utils.WaitUntil(h.ComponentReady(component), time.Minute*10); err != nil {
-		return nil, fmt.Errorf("timed out when waiting for component %s to be ready in %s namespace. component: %s", componentName, namespace, utils.ToPrettyJSONString(component))
-	}
-	return component, nil
-}
-
 // DeleteHasComponent delete an has component from a given name and namespace
 func (h *SuiteController) DeleteHasComponentDetectionQuery(name string, namespace string) error {
 	component := appservice.ComponentDetectionQuery{
@@ -436,72 +326,37 @@ func (h *SuiteController) GetComponentService(componentName string, componentNam
 	return service, nil
 }
 
+// WaitForComponentPipelineToBeFinished waits for the build PipelineRun of a component to be created and follows it
+// until it finishes, streaming the logs of its steps. An error is returned unless the PipelineRun succeeded
 func (h *SuiteController) WaitForComponentPipelineToBeFinished(c *common.SuiteController, componentName, applicationName, componentNamespace, sha string) error {
-	return wait.PollImmediate(20*time.Second, 30*time.Minute, func() (done bool, err error) {
-		pipelineRun, err := h.GetComponentPipelineRun(componentName, applicationName, componentNamespace, sha)
+	timeout := 30 * time.Minute
+	started := time.Now()
 
+	var pipelineRun *v1beta1.PipelineRun
+	err := wait.PollImmediate(20*time.Second, timeout, func() (done bool, err error) {
+		pipelineRun, err = h.GetComponentPipelineRun(componentName, applicationName, componentNamespace, sha)
 		if err != nil {
 			GinkgoWriter.Println("PipelineRun has not been created yet")
 			return false, nil
 		}
-
-		for _, condition := range pipelineRun.Status.Conditions {
-			GinkgoWriter.Printf("PipelineRun %s reason: %s\n", pipelineRun.Name, condition.Reason)
-
-			if !pipelineRun.IsDone() {
-				return false, nil
-			}
-
-			if pipelineRun.GetStatusCondition().GetCondition(apis.ConditionSucceeded).IsTrue() {
-				return true, nil
-			} else {
-				return false, fmt.Errorf(tekton.GetFailedPipelineRunLogs(c, pipelineRun))
-			}
-		}
-		return false, nil
+		return true, nil
 	})
-
-}
-
-// CreateComponentFromDevfile creates a has component from a given name, namespace, application, devfile and a container image
-func (h *SuiteController) CreateComponentFromDevfile(applicationName, componentName, namespace, gitSourceURL, devfile, containerImageSource, outputContainerImage, secret string) (*appservice.Component, error) {
-	var containerImage string
-	if outputContainerImage != "" {
-		containerImage = outputContainerImage
-	} else {
-		containerImage = containerImageSource
-	}
-	component := &appservice.Component{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      componentName,
-			Namespace: namespace,
-		},
-		Spec: appservice.ComponentSpec{
-			ComponentName: componentName,
-			Application:   applicationName,
-			Source: appservice.ComponentSource{
-				ComponentSourceUnion: appservice.ComponentSourceUnion{
-					GitSource: &appservice.GitSource{
-						URL:        gitSourceURL,
-						DevfileURL: devfile,
-					},
-				},
-			},
-			Secret:         secret,
-			ContainerImage: containerImage,
-			Replicas:       1,
-			TargetPort:     8080,
-			Route:          "",
-		},
+	if err != nil {
+		return fmt.Errorf("timed out when waiting for the PipelineRun of component %s to be created in %s namespace", componentName, componentNamespace)
 	}
-	err := h.KubeRest().Create(context.TODO(), component)
+
+	follower := tekton.NewPipelineRunFollower(h.KubeInterface(), h.PipelineClient(), pipelineRun.Name, componentNamespace)
+	result, err := follower.Wait(timeout - time.Since(started))
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("error when following PipelineRun %s: %v", pipelineRun.Name, err)
 	}
-	if err = utils.WaitUntil(h.ComponentReady(component), time.Minute*2); err != nil {
-		return nil, fmt.Errorf("timed out when waiting for component %s to be ready in %s namespace. component: %s", componentName, namespace, utils.ToPrettyJSONString(component))
+	if result.Outcome != tekton.PipelineRunSucceeded {
+		if result.FailedStep == "" {
+			return result.Err()
+		}
+		return fmt.Errorf("%v\nLogs from failed step '%s': \n%s", result.Err(), result.FailedStep, follower.Logs()[fmt.Sprintf("%s/%s", result.FailedTask, result.FailedStep)])
 	}
-	return component, nil
+	return nil
 }
 
 // DeleteAllComponentsInASpecificNamespace removes all component CRs from a specific namespace. Useful when creating a lot of resources and want to remove all of them
@@ -592,6 +447,6 @@ func (h *SuiteController) DeleteAllSnapshotEnvBindingsInASpecificNamespace(names
 func (s *SuiteController) ApplicationGitopsRepoExists(devfileContent string) wait.ConditionFunc {
 	return func() (bool, error) {
 		gitOpsRepoURL := utils.ObtainGitOpsRepositoryName(devfileContent)
-		return s.Github.CheckIfRepositoryExist(gitOpsRepoURL), nil
+		return s.GitOps.CheckIfRepositoryExist(gitOpsRepoURL), nil
 	}
 }
@@ -0,0 +1,477 @@
+package has
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	routev1 "github.com/openshift/api/route/v1"
+	appservice "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils/tekton"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+)
+
+// ComponentLifecycleStage is a step a component goes through from its creation to being reachable through its route
+type ComponentLifecycleStage string
+
+const (
+	ComponentCreatedStage        ComponentLifecycleStage = "ComponentCreated"
+	ComponentDevfileReadyStage   ComponentLifecycleStage = "DevfileReady"
+	ComponentBuildStartedStage   ComponentLifecycleStage = "BuildStarted"
+	ComponentBuildSucceededStage ComponentLifecycleStage = "BuildSucceeded"
+	ComponentBuildFailedStage    ComponentLifecycleStage = "BuildFailed"
+	ComponentSnapshotStage       ComponentLifecycleStage = "SnapshotCreated"
+	ComponentBoundStage          ComponentLifecycleStage = "EnvironmentBound"
+	ComponentDeployedStage       ComponentLifecycleStage = "Deployed"
+	ComponentRouteReachableStage ComponentLifecycleStage = "RouteReachable"
+)
+
+// Stages which can't be reached once the build of the component failed
+var stagesAfterBuild = map[ComponentLifecycleStage]bool{
+	ComponentBuildSucceededStage: true,
+	ComponentSnapshotStage:       true,
+	ComponentBoundStage:          true,
+	ComponentDeployedStage:       true,
+	ComponentRouteReachableStage: true,
+}
+
+var (
+	componentsGVR    = schema.GroupVersionResource{Group: "appstudio.redhat.com", Version: "v1alpha1", Resource: "components"}
+	snapshotsGVR     = schema.GroupVersionResource{Group: "appstudio.redhat.com", Version: "v1alpha1", Resource: "snapshots"}
+	bindingsGVR      = schema.GroupVersionResource{Group: "appstudio.redhat.com", Version: "v1alpha1", Resource: "snapshotenvironmentbindings"}
+	deploymentsGVR   = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	routesGVR        = schema.GroupVersionResource{Group: "route.openshift.io", Version: "v1", Resource: "routes"}
+	routeProbePeriod = 5 * time.Second
+)
+
+// ComponentLifecycleEvent is an entry of the timeline of a component
+type ComponentLifecycleEvent struct {
+	Time  time.Time
+	Stage ComponentLifecycleStage
+
+	// Kind and name of the object the event was observed on, e.g. PipelineRun/component-abcde
+	Object  string
+	Message string
+}
+
+// ComponentLifecycleTracker watches the Component, its build PipelineRuns, Snapshots, SnapshotEnvironmentBindings,
+// Deployment and Route, and records a timestamped timeline of the lifecycle stages the component reaches
+type ComponentLifecycleTracker struct {
+	ComponentName   string
+	ApplicationName string
+	Namespace       string
+
+	// Path requested on the component route to check it is reachable
+	RouteProbePath string
+
+	// Version of the Tekton API the build PipelineRuns are watched with, v1beta1 by default
+	PipelineAPIVersion string
+
+	client     dynamic.Interface
+	httpClient *http.Client
+	started    time.Time
+	ctx        context.Context
+	cancel     context.CancelFunc
+
+	mu       sync.Mutex
+	timeline []ComponentLifecycleEvent
+	seen     map[string]bool
+	reached  map[ComponentLifecycleStage]ComponentLifecycleEvent
+	changed  chan struct{}
+	probing  bool
+	routeURL string
+}
+
+// TrackComponentLifecycle starts tracking the lifecycle of a component. Call Stop once the tracker is not needed anymore
+func (h *SuiteController) TrackComponentLifecycle(componentName, applicationName, namespace string) (*ComponentLifecycleTracker, error) {
+	t := NewComponentLifecycleTracker(h.DynamicClient(), componentName, applicationName, namespace)
+	version, err := tekton.DiscoverPipelineAPIVersion(h.KubeInterface().Discovery())
+	if err != nil {
+		return nil, err
+	}
+	t.PipelineAPIVersion = version
+	if err := t.Start(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// NewComponentLifecycleTracker returns a tracker of a component lifecycle which uses a given client to watch resources
+func NewComponentLifecycleTracker(client dynamic.Interface, componentName, applicationName, namespace string) *ComponentLifecycleTracker {
+	return &ComponentLifecycleTracker{
+		ComponentName:      componentName,
+		ApplicationName:    applicationName,
+		Namespace:          namespace,
+		RouteProbePath:     "/",
+		PipelineAPIVersion: tekton.TektonV1beta1,
+		client:             client,
+		httpClient: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}, // #nosec
+		},
+		seen:    map[string]bool{},
+		reached: map[ComponentLifecycleStage]ComponentLifecycleEvent{},
+		changed: make(chan struct{}),
+	}
+}
+
+// Start lists the current state of the watched resources and starts watching them
+func (t *ComponentLifecycleTracker) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.ctx, t.cancel = ctx, cancel
+	t.started = time.Now()
+
+	pipelineRunsGVR := schema.GroupVersionResource{Group: "tekton.dev", Version: t.PipelineAPIVersion, Resource: "pipelineruns"}
+	componentSelector := fmt.Sprintf("appstudio.openshift.io/component=%s", t.ComponentName)
+	routeSelector := fmt.Sprintf("app.kubernetes.io/name=%s", t.ComponentName)
+	watches := []struct {
+		gvr      schema.GroupVersionResource
+		selector string
+		handle   func(*unstructured.Unstructured) error
+	}{
+		{componentsGVR, "", t.handleComponent},
+		{pipelineRunsGVR, componentSelector, t.handlePipelineRun},
+		{snapshotsGVR, "", t.handleSnapshot},
+		{bindingsGVR, "", t.handleBinding},
+		{deploymentsGVR, "", t.handleDeployment},
+		{routesGVR, routeSelector, t.handleRoute},
+	}
+	for _, w := range watches {
+		resourceVersion, err := t.list(ctx, w.gvr, w.selector, w.handle)
+		if err != nil {
+			cancel()
+			return fmt.Errorf("error when listing %s in %s namespace: %v", w.gvr.Resource, t.Namespace, err)
+		}
+		go t.watch(ctx, w.gvr, w.selector, resourceVersion, w.handle)
+	}
+	return nil
+}
+
+// Stop stops watching resources and probing the component route
+func (t *ComponentLifecycleTracker) Stop() {
+	if t.cancel != nil {
+		t.cancel()
+	}
+}
+
+// Timeline returns the events recorded so far, ordered by time
+func (t *ComponentLifecycleTracker) Timeline() []ComponentLifecycleEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	timeline := append([]ComponentLifecycleEvent{}, t.timeline...)
+	sort.SliceStable(timeline, func(i, j int) bool { return timeline[i].Time.Before(timeline[j].Time) })
+	return timeline
+}
+
+// String formats the timeline, with the time of each event relative to the start of the tracking
+func (t *ComponentLifecycleTracker) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Lifecycle of component %s in %s namespace (tracking started at %s):\n", t.ComponentName, t.Namespace, t.started.Format(time.RFC3339))
+	for _, e := range t.Timeline() {
+		fmt.Fprintf(&sb, "  %9s  %-17s %s", e.Time.Sub(t.started).Round(time.Second), e.Stage, e.Object)
+		if e.Message != "" {
+			fmt.Fprintf(&sb, ": %s", e.Message)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// AddToReport attaches the timeline to the report of the running Ginkgo spec
+func (t *ComponentLifecycleTracker) AddToReport() {
+	AddReportEntry(fmt.Sprintf("component %s lifecycle", t.ComponentName), t.String())
+}
+
+// WaitForStage waits until the component reaches a given stage and returns the corresponding event.
+// Waiting for a stage following the build fails as soon as the build of the component fails
+func (t *ComponentLifecycleTracker) WaitForStage(stage ComponentLifecycleStage, timeout time.Duration) (ComponentLifecycleEvent, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		t.mu.Lock()
+		event, reached := t.reached[stage]
+		failed, buildFailed := t.lastBuildFailure()
+		changed := t.changed
+		t.mu.Unlock()
+
+		if reached {
+			return event, nil
+		}
+		if stagesAfterBuild[stage] && buildFailed {
+			return ComponentLifecycleEvent{}, fmt.Errorf("component %s can't reach %s, its build failed (%s: %s)\n%s", t.ComponentName, stage, failed.Object, failed.Message, t)
+		}
+
+		select {
+		case <-changed:
+		case <-timer.C:
+			return ComponentLifecycleEvent{}, fmt.Errorf("timed out after %s waiting for component %s to reach %s\n%s", timeout, t.ComponentName, stage, t)
+		}
+	}
+}
+
+// WaitForBuild waits until the build PipelineRun of the component succeeds
+func (t *ComponentLifecycleTracker) WaitForBuild(timeout time.Duration) error {
+	_, err := t.WaitForStage(ComponentBuildSucceededStage, timeout)
+	return err
+}
+
+// WaitForDeployment waits until the component is deployed and its route responds
+func (t *ComponentLifecycleTracker) WaitForDeployment(timeout time.Duration) error {
+	_, err := t.WaitForStage(ComponentRouteReachableStage, timeout)
+	return err
+}
+
+func (t *ComponentLifecycleTracker) list(ctx context.Context, gvr schema.GroupVersionResource, selector string, handle func(*unstructured.Unstructured) error) (string, error) {
+	list, err := t.client.Resource(gvr).Namespace(t.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return "", err
+	}
+	for i := range list.Items {
+		if err := handle(&list.Items[i]); err != nil {
+			klog.Errorf("error when handling %s %s: %v", gvr.Resource, list.Items[i].GetName(), err)
+		}
+	}
+	return list.GetResourceVersion(), nil
+}
+
+// watch watches a resource until the context is cancelled, re-listing it whenever the watch gets closed by the server
+func (t *ComponentLifecycleTracker) watch(ctx context.Context, gvr schema.GroupVersionResource, selector, resourceVersion string, handle func(*unstructured.Unstructured) error) {
+	for ctx.Err() == nil {
+		w, err := t.client.Resource(gvr).Namespace(t.Namespace).Watch(ctx, metav1.ListOptions{LabelSelector: selector, ResourceVersion: resourceVersion})
+		if err != nil {
+			klog.Errorf("error when watching %s in %s namespace: %v", gvr.Resource, t.Namespace, err)
+		} else {
+			for event := range w.ResultChan() {
+				obj, ok := event.Object.(*unstructured.Unstructured)
+				if !ok || event.Type == watch.Deleted {
+					continue
+				}
+				if err := handle(obj); err != nil {
+					klog.Errorf("error when handling %s %s: %v", gvr.Resource, obj.GetName(), err)
+				}
+			}
+			w.Stop()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+		if resourceVersion, err = t.list(ctx, gvr, selector, handle); err != nil {
+			klog.Errorf("error when listing %s in %s namespace: %v", gvr.Resource, t.Namespace, err)
+		}
+	}
+}
+
+func (t *ComponentLifecycleTracker) handleComponent(obj *unstructured.Unstructured) error {
+	if obj.GetName() != t.ComponentName {
+		return nil
+	}
+	component := &appservice.Component{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, component); err != nil {
+		return err
+	}
+	object := "Component/" + component.Name
+	t.record(ComponentCreatedStage, object, "", timeOrNow(&component.CreationTimestamp))
+	if component.Status.Devfile != "" {
+		// HAS sets the Created condition once it generated the devfile of the component
+		var created *metav1.Time
+		if c := meta.FindStatusCondition(component.Status.Conditions, "Created"); c != nil && c.Status == metav1.ConditionTrue {
+			created = &c.LastTransitionTime
+		}
+		t.record(ComponentDevfileReadyStage, object, "", timeOrNow(created))
+	}
+	return nil
+}
+
+func (t *ComponentLifecycleTracker) handlePipelineRun(obj *unstructured.Unstructured) error {
+	var typed interface{} = &v1beta1.PipelineRun{}
+	if obj.GetAPIVersion() == tektonv1.SchemeGroupVersion.String() {
+		typed = &tektonv1.PipelineRun{}
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, typed); err != nil {
+		return err
+	}
+	pipelineRun, err := tekton.NewPipelineRunAccessor(typed)
+	if err != nil {
+		return err
+	}
+	object := "PipelineRun/" + pipelineRun.Name()
+	started := pipelineRun.StartTime()
+	if started == nil {
+		creationTimestamp := pipelineRun.Object().GetCreationTimestamp()
+		started = &creationTimestamp
+	}
+	t.record(ComponentBuildStartedStage, object, "", timeOrNow(started))
+
+	if !pipelineRun.IsDone() {
+		return nil
+	}
+	if pipelineRun.Succeeded() {
+		t.record(ComponentBuildSucceededStage, object, "", timeOrNow(pipelineRun.CompletionTime()))
+	} else {
+		condition := pipelineRun.Condition()
+		t.record(ComponentBuildFailedStage, object, fmt.Sprintf("%s: %s", condition.Reason, condition.Message), timeOrNow(pipelineRun.CompletionTime()))
+	}
+	return nil
+}
+
+func (t *ComponentLifecycleTracker) handleSnapshot(obj *unstructured.Unstructured) error {
+	snapshot := &appservice.Snapshot{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, snapshot); err != nil {
+		return err
+	}
+	if snapshot.Spec.Application != t.ApplicationName {
+		return nil
+	}
+	for _, c := range snapshot.Spec.Components {
+		if c.Name == t.ComponentName {
+			t.record(ComponentSnapshotStage, "Snapshot/"+snapshot.Name, c.ContainerImage, timeOrNow(&snapshot.CreationTimestamp))
+		}
+	}
+	return nil
+}
+
+func (t *ComponentLifecycleTracker) handleBinding(obj *unstructured.Unstructured) error {
+	binding := &appservice.SnapshotEnvironmentBinding{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, binding); err != nil {
+		return err
+	}
+	if binding.Spec.Application != t.ApplicationName {
+		return nil
+	}
+	for _, c := range binding.Spec.Components {
+		if c.Name == t.ComponentName {
+			message := fmt.Sprintf("snapshot %s to environment %s", binding.Spec.Snapshot, binding.Spec.Environment)
+			t.record(ComponentBoundStage, "SnapshotEnvironmentBinding/"+binding.Name, message, timeOrNow(&binding.CreationTimestamp))
+		}
+	}
+	return nil
+}
+
+func (t *ComponentLifecycleTracker) handleDeployment(obj *unstructured.Unstructured) error {
+	if obj.GetName() != t.ComponentName {
+		return nil
+	}
+	deployment := &appsv1.Deployment{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, deployment); err != nil {
+		return err
+	}
+	if deployment.Status.AvailableReplicas > 0 {
+		t.record(ComponentDeployedStage, "Deployment/"+deployment.Name, fmt.Sprintf("%d available replicas", deployment.Status.AvailableReplicas), time.Now())
+		t.startRouteProbe()
+	}
+	return nil
+}
+
+func (t *ComponentLifecycleTracker) handleRoute(obj *unstructured.Unstructured) error {
+	route := &routev1.Route{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, route); err != nil {
+		return err
+	}
+	if route.Spec.Host == "" {
+		return nil
+	}
+	t.mu.Lock()
+	t.routeURL = "https://" + route.Spec.Host + t.RouteProbePath
+	t.mu.Unlock()
+	t.startRouteProbe()
+	return nil
+}
+
+// startRouteProbe requests the component route periodically, once the component is deployed and its route is known,
+// until it responds with a non server error status
+func (t *ComponentLifecycleTracker) startRouteProbe() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, deployed := t.reached[ComponentDeployedStage]; t.probing || !deployed || t.routeURL == "" {
+		return
+	}
+	t.probing = true
+	url := t.routeURL
+
+	go func() {
+		for {
+			resp, err := t.httpClient.Get(url)
+			if err == nil {
+				resp.Body.Close()
+				if resp.StatusCode < http.StatusInternalServerError {
+					t.record(ComponentRouteReachableStage, url, fmt.Sprintf("responded with %d", resp.StatusCode), time.Now())
+					return
+				}
+			}
+			select {
+			case <-t.ctx.Done():
+				return
+			case <-time.After(routeProbePeriod):
+			}
+		}
+	}()
+}
+
+// record adds an event to the timeline, unless the same stage was already recorded for the same object
+func (t *ComponentLifecycleTracker) record(stage ComponentLifecycleStage, object, message string, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := string(stage) + "/" + object
+	if t.seen[key] {
+		return
+	}
+	t.seen[key] = true
+
+	event := ComponentLifecycleEvent{Time: at, Stage: stage, Object: object, Message: message}
+	t.timeline = append(t.timeline, event)
+	if _, ok := t.reached[stage]; !ok {
+		t.reached[stage] = event
+	}
+	klog.Infof("component %s: %s %s %s", t.ComponentName, stage, object, message)
+
+	close(t.changed)
+	t.changed = make(chan struct{})
+}
+
+// lastBuildFailure returns the failure of the last build, if no other build is running or succeeded. Must be called with the lock held
+func (t *ComponentLifecycleTracker) lastBuildFailure() (ComponentLifecycleEvent, bool) {
+	var failure ComponentLifecycleEvent
+	for _, e := range t.timeline {
+		switch e.Stage {
+		case ComponentBuildSucceededStage:
+			return ComponentLifecycleEvent{}, false
+		case ComponentBuildFailedStage:
+			failure = e
+		}
+	}
+	if failure.Object == "" {
+		return failure, false
+	}
+	for _, e := range t.timeline {
+		if e.Stage == ComponentBuildStartedStage && e.Object != failure.Object && !t.seen[string(ComponentBuildFailedStage)+"/"+e.Object] {
+			return ComponentLifecycleEvent{}, false
+		}
+	}
+	return failure, true
+}
+
+func timeOrNow(t *metav1.Time) time.Time {
+	if t == nil || t.IsZero() {
+		return time.Now()
+	}
+	return t.Time
+}
@@ -0,0 +1,127 @@
+package has
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils/tekton"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestComponentLifecycleTracker(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	pipelineRunsGVR := schema.GroupVersionResource{Group: "tekton.dev", Version: "v1", Resource: "pipelineruns"}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		componentsGVR:   "ComponentList",
+		snapshotsGVR:    "SnapshotList",
+		bindingsGVR:     "SnapshotEnvironmentBindingList",
+		pipelineRunsGVR: "PipelineRunList",
+		deploymentsGVR:  "DeploymentList",
+		routesGVR:       "RouteList",
+	}, newObject("appstudio.redhat.com/v1alpha1", "Component", "comp", nil, map[string]interface{}{
+		"spec": map[string]interface{}{"componentName": "comp", "application": "app"},
+	}))
+
+	tracker := NewComponentLifecycleTracker(client, "comp", "app", "ns")
+	tracker.PipelineAPIVersion = tekton.TektonV1
+	assert.NoError(t, tracker.Start())
+	defer tracker.Stop()
+
+	event, err := tracker.WaitForStage(ComponentCreatedStage, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "Component/comp", event.Object)
+	_, err = tracker.WaitForStage(ComponentDevfileReadyStage, 100*time.Millisecond)
+	assert.ErrorContains(t, err, "timed out")
+
+	update(t, client, componentsGVR, newObject("appstudio.redhat.com/v1alpha1", "Component", "comp", nil, map[string]interface{}{
+		"spec": map[string]interface{}{"componentName": "comp", "application": "app"},
+		"status": map[string]interface{}{
+			"devfile":    "schemaVersion: 2.2.0",
+			"conditions": []interface{}{map[string]interface{}{"type": "Created", "status": "True", "lastTransitionTime": "2023-01-02T10:00:00Z", "reason": "OK"}},
+		},
+	}))
+	event, err = tracker.WaitForStage(ComponentDevfileReadyStage, 5*time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC), event.Time.UTC())
+
+	// A failed build fails waiting for the stages following the build
+	pipelineRunLabels := map[string]interface{}{"appstudio.openshift.io/component": "comp"}
+	create(t, client, pipelineRunsGVR, newObject("tekton.dev/v1", "PipelineRun", "comp-build-1", pipelineRunLabels, map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{map[string]interface{}{"type": "Succeeded", "status": "False", "reason": "Failed", "message": "task build failed"}},
+		},
+	}))
+	_, err = tracker.WaitForStage(ComponentDeployedStage, 5*time.Second)
+	assert.ErrorContains(t, err, "its build failed (PipelineRun/comp-build-1: Failed: task build failed)")
+
+	// Once another build started, the failure of the previous one doesn't matter anymore
+	create(t, client, pipelineRunsGVR, newObject("tekton.dev/v1", "PipelineRun", "comp-build-2", pipelineRunLabels, map[string]interface{}{}))
+	assert.Eventually(t, func() bool {
+		_, err := tracker.WaitForStage(ComponentDeployedStage, 10*time.Millisecond)
+		return strings.Contains(err.Error(), "timed out")
+	}, 5*time.Second, 10*time.Millisecond)
+	update(t, client, pipelineRunsGVR, newObject("tekton.dev/v1", "PipelineRun", "comp-build-2", pipelineRunLabels, map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{map[string]interface{}{"type": "Succeeded", "status": "True", "reason": "Succeeded"}},
+		},
+	}))
+	assert.NoError(t, tracker.WaitForBuild(5*time.Second))
+
+	create(t, client, snapshotsGVR, newObject("appstudio.redhat.com/v1alpha1", "Snapshot", "snapshot-1", nil, map[string]interface{}{
+		"spec": map[string]interface{}{"application": "app", "components": []interface{}{map[string]interface{}{"name": "comp", "containerImage": "quay.io/org/comp:1"}}},
+	}))
+	create(t, client, snapshotsGVR, newObject("appstudio.redhat.com/v1alpha1", "Snapshot", "snapshot-other", nil, map[string]interface{}{
+		"spec": map[string]interface{}{"application": "other", "components": []interface{}{map[string]interface{}{"name": "comp", "containerImage": "quay.io/org/comp:1"}}},
+	}))
+	event, err = tracker.WaitForStage(ComponentSnapshotStage, 5*time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "quay.io/org/comp:1", event.Message)
+
+	create(t, client, routesGVR, newObject("route.openshift.io/v1", "Route", "comp", map[string]interface{}{"app.kubernetes.io/name": "comp"}, map[string]interface{}{
+		"spec": map[string]interface{}{"host": strings.TrimPrefix(server.URL, "https://")},
+	}))
+	create(t, client, deploymentsGVR, newObject("apps/v1", "Deployment", "comp", nil, map[string]interface{}{
+		"status": map[string]interface{}{"availableReplicas": int64(1)},
+	}))
+	assert.NoError(t, tracker.WaitForDeployment(5*time.Second))
+
+	var stages []ComponentLifecycleStage
+	for _, e := range tracker.Timeline() {
+		stages = append(stages, e.Stage)
+	}
+	assert.Contains(t, stages, ComponentBuildFailedStage)
+	assert.Equal(t, ComponentRouteReachableStage, stages[len(stages)-1])
+	assert.Contains(t, tracker.String(), "Snapshot/snapshot-1")
+	assert.NotContains(t, tracker.String(), "snapshot-other")
+}
+
+func newObject(apiVersion, kind, name string, labels map[string]interface{}, content map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: content}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetName(name)
+	obj.SetNamespace("ns")
+	obj.Object["metadata"].(map[string]interface{})["labels"] = labels
+	return obj
+}
+
+func create(t *testing.T, client *dynamicfake.FakeDynamicClient, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) {
+	_, err := client.Resource(gvr).Namespace("ns").Create(context.Background(), obj, metav1.CreateOptions{})
+	assert.NoError(t, err)
+}
+
+func update(t *testing.T, client *dynamicfake.FakeDynamicClient, gvr schema.GroupVersionResource, obj *unstructured.Unstructured) {
+	_, err := client.Resource(gvr).Namespace("ns").Update(context.Background(), obj, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,279 @@
+package has
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/onsi/gomega/types"
+	appservice "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// ConditionMatcher matches an Application, Component or ComponentDetectionQuery having a status condition
+type ConditionMatcher struct {
+	conditionType string
+	status        metav1.ConditionStatus
+	reason        string
+}
+
+// HaveCondition succeeds if an Application, Component or ComponentDetectionQuery has a condition of a given type and status.
+// The reason is not checked if empty
+func HaveCondition(conditionType string, status metav1.ConditionStatus, reason string) types.GomegaMatcher {
+	return &ConditionMatcher{conditionType: conditionType, status: status, reason: reason}
+}
+
+func (matcher *ConditionMatcher) Match(actual interface{}) (success bool, err error) {
+	conditions, err := statusConditions(actual)
+	if err != nil {
+		return false, err
+	}
+	for _, condition := range conditions {
+		if condition.Type == matcher.conditionType && condition.Status == matcher.status && (matcher.reason == "" || condition.Reason == matcher.reason) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (matcher *ConditionMatcher) FailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf("Expected %s to have condition %s\n%s", describeObject(actual), matcher, describeConditions(actual))
+}
+
+func (matcher *ConditionMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf("Expected %s not to have condition %s\n%s", describeObject(actual), matcher, describeConditions(actual))
+}
+
+func (matcher *ConditionMatcher) String() string {
+	if matcher.reason == "" {
+		return fmt.Sprintf("%s=%s", matcher.conditionType, matcher.status)
+	}
+	return fmt.Sprintf("%s=%s (reason %s)", matcher.conditionType, matcher.status, matcher.reason)
+}
+
+// DevfileComponentMatcher matches an Application or Component whose devfile contains a given component or project
+type DevfileComponentMatcher struct {
+	name string
+}
+
+// HaveDevfileWithComponent succeeds if the devfile generated by HAS for an Application or Component has a component or
+// project with a given name. The devfile of an Application lists its components as projects
+func HaveDevfileWithComponent(name string) types.GomegaMatcher {
+	return &DevfileComponentMatcher{name: name}
+}
+
+func (matcher *DevfileComponentMatcher) Match(actual interface{}) (success bool, err error) {
+	devfile, err := statusDevfile(actual)
+	if err != nil {
+		return false, err
+	}
+	if devfile == "" {
+		return false, nil
+	}
+	names, err := devfileComponentNames(devfile)
+	if err != nil {
+		return false, err
+	}
+	for _, name := range names {
+		if name == matcher.name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (matcher *DevfileComponentMatcher) FailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf("Expected devfile of %s to have component %s\n%s", describeObject(actual), matcher.name, describeDevfile(actual))
+}
+
+func (matcher *DevfileComponentMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf("Expected devfile of %s not to have component %s\n%s", describeObject(actual), matcher.name, describeDevfile(actual))
+}
+
+// GitOpsRepositoryMatcher matches an Application or Component having a gitops repository
+type GitOpsRepositoryMatcher struct{}
+
+// HaveGitOpsRepository succeeds if HAS generated a gitops repository for an Application (in its devfile) or a Component (in its status)
+func HaveGitOpsRepository() types.GomegaMatcher {
+	return &GitOpsRepositoryMatcher{}
+}
+
+func (matcher *GitOpsRepositoryMatcher) Match(actual interface{}) (success bool, err error) {
+	repositoryURL, err := gitOpsRepositoryURL(actual)
+	return repositoryURL != "", err
+}
+
+func (matcher *GitOpsRepositoryMatcher) FailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf("Expected %s to have a gitops repository\n%s", describeObject(actual), describeConditions(actual))
+}
+
+func (matcher *GitOpsRepositoryMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	repositoryURL, _ := gitOpsRepositoryURL(actual)
+	return fmt.Sprintf("Expected %s not to have a gitops repository, got %s", describeObject(actual), repositoryURL)
+}
+
+// ContainerImageMatcher matches a Component having a container image in its status
+type ContainerImageMatcher struct {
+	image string
+}
+
+// HaveContainerImage succeeds if the status of a Component has a given container image, or any image if empty
+func HaveContainerImage(image string) types.GomegaMatcher {
+	return &ContainerImageMatcher{image: image}
+}
+
+func (matcher *ContainerImageMatcher) Match(actual interface{}) (success bool, err error) {
+	component, err := asComponent(actual)
+	if err != nil {
+		return false, err
+	}
+	if matcher.image == "" {
+		return component.Status.ContainerImage != "", nil
+	}
+	return component.Status.ContainerImage == matcher.image, nil
+}
+
+func (matcher *ContainerImageMatcher) FailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf("Expected %s to have container image %q, got %q\n%s", describeObject(actual), matcher.image, containerImage(actual), describeConditions(actual))
+}
+
+func (matcher *ContainerImageMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return fmt.Sprintf("Expected %s not to have container image %q, got %q", describeObject(actual), matcher.image, containerImage(actual))
+}
+
+func statusConditions(actual interface{}) ([]metav1.Condition, error) {
+	switch obj := actual.(type) {
+	case *appservice.Application:
+		return obj.Status.Conditions, nil
+	case appservice.Application:
+		return obj.Status.Conditions, nil
+	case *appservice.Component:
+		return obj.Status.Conditions, nil
+	case appservice.Component:
+		return obj.Status.Conditions, nil
+	case *appservice.ComponentDetectionQuery:
+		return obj.Status.Conditions, nil
+	case appservice.ComponentDetectionQuery:
+		return obj.Status.Conditions, nil
+	}
+	return nil, fmt.Errorf("expected an Application, Component or ComponentDetectionQuery, got %T", actual)
+}
+
+func statusDevfile(actual interface{}) (string, error) {
+	switch obj := actual.(type) {
+	case *appservice.Application:
+		return obj.Status.Devfile, nil
+	case appservice.Application:
+		return obj.Status.Devfile, nil
+	case *appservice.Component:
+		return obj.Status.Devfile, nil
+	case appservice.Component:
+		return obj.Status.Devfile, nil
+	}
+	return "", fmt.Errorf("expected an Application or Component, got %T", actual)
+}
+
+func gitOpsRepositoryURL(actual interface{}) (string, error) {
+	if component, err := asComponent(actual); err == nil {
+		return component.Status.GitOps.RepositoryURL, nil
+	}
+	devfile, err := statusDevfile(actual)
+	if err != nil || devfile == "" {
+		return "", err
+	}
+	d := struct {
+		Metadata struct {
+			Attributes map[string]interface{} `json:"attributes"`
+		} `json:"metadata"`
+	}{}
+	if err := yaml.Unmarshal([]byte(devfile), &d); err != nil {
+		return "", fmt.Errorf("error when parsing devfile: %v", err)
+	}
+	repositoryURL, _ := d.Metadata.Attributes["gitOpsRepository.url"].(string)
+	return repositoryURL, nil
+}
+
+func asComponent(actual interface{}) (*appservice.Component, error) {
+	switch obj := actual.(type) {
+	case *appservice.Component:
+		return obj, nil
+	case appservice.Component:
+		return &obj, nil
+	}
+	return nil, fmt.Errorf("expected a Component, got %T", actual)
+}
+
+func containerImage(actual interface{}) string {
+	if component, err := asComponent(actual); err == nil {
+		return component.Status.ContainerImage
+	}
+	return ""
+}
+
+func devfileComponentNames(devfile string) ([]string, error) {
+	d := struct {
+		Components []struct {
+			Name string `json:"name"`
+		} `json:"components"`
+		Projects []struct {
+			Name string `json:"name"`
+		} `json:"projects"`
+	}{}
+	if err := yaml.Unmarshal([]byte(devfile), &d); err != nil {
+		return nil, fmt.Errorf("error when parsing devfile: %v", err)
+	}
+	var names []string
+	for _, c := range d.Components {
+		names = append(names, c.Name)
+	}
+	for _, p := range d.Projects {
+		names = append(names, p.Name)
+	}
+	return names, nil
+}
+
+func describeObject(actual interface{}) string {
+	switch obj := actual.(type) {
+	case appservice.Application:
+		actual = &obj
+	case appservice.Component:
+		actual = &obj
+	case appservice.ComponentDetectionQuery:
+		actual = &obj
+	}
+	if obj, ok := actual.(metav1.Object); ok {
+		return fmt.Sprintf("%T %s/%s", actual, obj.GetNamespace(), obj.GetName())
+	}
+	return fmt.Sprintf("%T", actual)
+}
+
+// describeConditions lists all the status conditions of an object, to be included in failure messages
+func describeConditions(actual interface{}) string {
+	conditions, err := statusConditions(actual)
+	if err != nil {
+		return ""
+	}
+	if len(conditions) == 0 {
+		return "no conditions"
+	}
+	var lines []string
+	for _, c := range conditions {
+		lines = append(lines, fmt.Sprintf("  %s=%s reason=%s message=%q", c.Type, c.Status, c.Reason, c.Message))
+	}
+	return "conditions:\n" + strings.Join(lines, "\n")
+}
+
+func describeDevfile(actual interface{}) string {
+	devfile, err := statusDevfile(actual)
+	if err != nil {
+		return ""
+	}
+	if devfile == "" {
+		return "no devfile\n" + describeConditions(actual)
+	}
+	names, err := devfileComponentNames(devfile)
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("devfile components and projects: %v\n%s", names, describeConditions(actual))
+}
@@ -0,0 +1,117 @@
+package has
+
+import (
+	"testing"
+
+	appservice "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testApplicationDevfile = `metadata:
+  attributes:
+    appModelRepository.url: https://github.com/org/app-model
+    gitOpsRepository.url: https://github.com/org/gitops
+  name: demo
+projects:
+- git:
+    remotes:
+      origin: https://github.com/org/frontend
+  name: frontend
+schemaVersion: 2.1.0
+`
+
+func TestHaveCondition(t *testing.T) {
+	component := &appservice.Component{
+		ObjectMeta: metav1.ObjectMeta{Name: "frontend", Namespace: "ns"},
+		Status: appservice.ComponentStatus{Conditions: []metav1.Condition{
+			{Type: "Created", Status: metav1.ConditionTrue, Reason: "OK", Message: "Component has been successfully created"},
+			{Type: "Updated", Status: metav1.ConditionFalse, Reason: "Error", Message: "failed to push to gitops repository"},
+		}},
+	}
+
+	match, err := HaveCondition("Created", metav1.ConditionTrue, "OK").Match(component)
+	assert.True(t, match)
+	assert.Nil(t, err)
+
+	match, err = HaveCondition("Updated", metav1.ConditionFalse, "").Match(*component)
+	assert.True(t, match)
+	assert.Nil(t, err)
+
+	matcher := HaveCondition("Updated", metav1.ConditionTrue, "OK")
+	match, err = matcher.Match(component)
+	assert.False(t, match)
+	assert.Nil(t, err)
+	message := matcher.FailureMessage(component)
+	assert.Contains(t, message, "*v1alpha1.Component ns/frontend")
+	assert.Contains(t, message, "Updated=True (reason OK)")
+	assert.Contains(t, message, `Created=True reason=OK message="Component has been successfully created"`)
+	assert.Contains(t, message, `Updated=False reason=Error message="failed to push to gitops repository"`)
+
+	cdq := &appservice.ComponentDetectionQuery{Status: appservice.ComponentDetectionQueryStatus{Conditions: []metav1.Condition{{Type: "Completed", Status: metav1.ConditionTrue}}}}
+	match, err = HaveCondition("Completed", metav1.ConditionTrue, "").Match(cdq)
+	assert.True(t, match)
+	assert.Nil(t, err)
+
+	_, err = HaveCondition("Completed", metav1.ConditionTrue, "").Match(&appservice.Environment{})
+	assert.Error(t, err)
+}
+
+func TestHaveDevfileWithComponent(t *testing.T) {
+	application := &appservice.Application{Status: appservice.ApplicationStatus{Devfile: testApplicationDevfile}}
+
+	match, err := HaveDevfileWithComponent("frontend").Match(application)
+	assert.True(t, match)
+	assert.Nil(t, err)
+
+	matcher := HaveDevfileWithComponent("backend")
+	match, err = matcher.Match(application)
+	assert.False(t, match)
+	assert.Nil(t, err)
+	assert.Contains(t, matcher.FailureMessage(application), "devfile components and projects: [frontend]")
+
+	match, err = matcher.Match(&appservice.Application{})
+	assert.False(t, match)
+	assert.Nil(t, err)
+	assert.Contains(t, matcher.FailureMessage(&appservice.Application{}), "no devfile")
+
+	component := &appservice.Component{Status: appservice.ComponentStatus{Devfile: "components:\n- name: backend\n"}}
+	match, err = matcher.Match(component)
+	assert.True(t, match)
+	assert.Nil(t, err)
+}
+
+func TestHaveGitOpsRepository(t *testing.T) {
+	match, err := HaveGitOpsRepository().Match(&appservice.Application{Status: appservice.ApplicationStatus{Devfile: testApplicationDevfile}})
+	assert.True(t, match)
+	assert.Nil(t, err)
+
+	match, err = HaveGitOpsRepository().Match(&appservice.Application{})
+	assert.False(t, match)
+	assert.Nil(t, err)
+
+	match, err = HaveGitOpsRepository().Match(appservice.Component{Status: appservice.ComponentStatus{GitOps: appservice.GitOpsStatus{RepositoryURL: "https://github.com/org/gitops"}}})
+	assert.True(t, match)
+	assert.Nil(t, err)
+}
+
+func TestHaveContainerImage(t *testing.T) {
+	component := &appservice.Component{Status: appservice.ComponentStatus{ContainerImage: "quay.io/org/frontend:latest"}}
+
+	match, err := HaveContainerImage("quay.io/org/frontend:latest").Match(component)
+	assert.True(t, match)
+	assert.Nil(t, err)
+
+	match, err = HaveContainerImage("").Match(component)
+	assert.True(t, match)
+	assert.Nil(t, err)
+
+	matcher := HaveContainerImage("quay.io/org/backend:latest")
+	match, err = matcher.Match(component)
+	assert.False(t, match)
+	assert.Nil(t, err)
+	assert.Contains(t, matcher.FailureMessage(component), `got "quay.io/org/frontend:latest"`)
+
+	_, err = HaveContainerImage("").Match(&appservice.Application{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,46 @@
+package has
+
+import (
+	"context"
+	"fmt"
+
+	buildservice "github.com/redhat-appstudio/build-service/api/v1alpha1"
+	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils/tekton/bundle"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CreateCustomBuildPipelineSelector points the components of a namespace to the custom pipeline bundles exposed in
+// CUSTOM_<PIPELINE>_PIPELINE_BUILD_BUNDLE env vars
+func (h *SuiteController) CreateCustomBuildPipelineSelector(namespace string) error {
+	return CreateCustomBuildPipelineSelector(h.KubeRest(), namespace, constants.BuildPipelineSelectorYamlURL)
+}
+
+// CreateCustomBuildPipelineSelector creates a build pipeline selector in a namespace using a given client. It holds the
+// selectors of the default build pipeline selector, which the bundle overrides are applied to, whose pipeline has a custom
+// bundle. Nothing is created if no pipeline has a custom bundle or the namespace has a build pipeline selector already
+func CreateCustomBuildPipelineSelector(c rclient.Client, namespace, buildPipelineSelectorYamlURL string) error {
+	if !bundle.HasCustomPipelineBundles() {
+		return nil
+	}
+	defaultSelector, err := utils.GetBuildPipelineSelector(buildPipelineSelectorYamlURL)
+	if err != nil {
+		return err
+	}
+	selectors := bundle.CustomPipelineSelectors(defaultSelector.Spec.Selectors)
+	if len(selectors) == 0 {
+		return nil
+	}
+
+	selector := &buildservice.BuildPipelineSelector{
+		ObjectMeta: metav1.ObjectMeta{Name: "build-pipeline-selector", Namespace: namespace},
+		Spec:       buildservice.BuildPipelineSelectorSpec{Selectors: selectors},
+	}
+	if err := c.Create(context.TODO(), selector); err != nil && !k8sErrors.IsAlreadyExists(err) {
+		return fmt.Errorf("error when creating the build pipeline selector in %s namespace: %v", namespace, err)
+	}
+	return nil
+}
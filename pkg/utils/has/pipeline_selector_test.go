@@ -0,0 +1,61 @@
+package has
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	buildservice "github.com/redhat-appstudio/build-service/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testBuildPipelineSelector = `apiVersion: appstudio.redhat.com/v1alpha1
+kind: BuildPipelineSelector
+metadata:
+  name: build-pipeline-selector
+spec:
+  selectors:
+  - name: Docker build
+    pipelineRef:
+      name: docker-build
+      bundle: quay.io/org/pipeline:1
+  - name: Java
+    pipelineRef:
+      name: java-builder
+      bundle: quay.io/org/pipeline:1
+    when:
+      language: java
+`
+
+func TestCreateCustomBuildPipelineSelector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testBuildPipelineSelector))
+	}))
+	defer server.Close()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(buildservice.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	selector := &buildservice.BuildPipelineSelector{}
+
+	// Without custom bundles of the default pipelines, the components keep using the selector of the cluster
+	assert.NoError(t, CreateCustomBuildPipelineSelector(c, "ns", server.URL))
+	t.Setenv("CUSTOM_FBC_PIPELINE_BUILD_BUNDLE", "quay.io/org/pipeline:pr")
+	assert.NoError(t, CreateCustomBuildPipelineSelector(c, "ns", server.URL))
+	assert.Error(t, c.Get(context.TODO(), types.NamespacedName{Name: "build-pipeline-selector", Namespace: "ns"}, selector))
+
+	t.Setenv("CUSTOM_JAVA_PIPELINE_BUILD_BUNDLE", "quay.io/org/pipeline:pr")
+	assert.NoError(t, CreateCustomBuildPipelineSelector(c, "ns", server.URL))
+	assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Name: "build-pipeline-selector", Namespace: "ns"}, selector))
+	assert.Equal(t, []buildservice.PipelineSelector{
+		{Name: "Java", PipelineRef: v1beta1.PipelineRef{Name: "java-builder", Bundle: "quay.io/org/pipeline:pr"}, WhenConditions: buildservice.WhenCondition{Language: "java"}},
+	}, selector.Spec.Selectors)
+
+	// A selector already present in the namespace is kept
+	assert.NoError(t, CreateCustomBuildPipelineSelector(c, "ns", server.URL))
+}
@@ -20,17 +20,20 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
-	"knative.dev/pkg/apis"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type SuiteController struct {
 	*kubeCl.CustomClient
+
+	// Used to read the build and integration PipelineRuns with the Tekton API version served by the cluster
+	tektonController *tekton.SuiteController
 }
 
 func NewSuiteController(kube *kubeCl.CustomClient) (*SuiteController, error) {
 	return &SuiteController{
-		kube,
+		CustomClient:     kube,
+		tektonController: tekton.NewSuiteController(kube),
 	}, nil
 }
 
@@ -344,79 +347,63 @@ func (h *SuiteController) CreateIntegrationTestScenario(applicationName, namespa
 
 func (h *SuiteController) WaitForIntegrationPipelineToBeFinished(c *common.SuiteController, testScenario *integrationv1alpha1.IntegrationTestScenario, snapshot *appstudioApi.Snapshot, applicationName string, appNamespace string) error {
 	return wait.PollImmediate(20*time.Second, 100*time.Minute, func() (done bool, err error) {
-		pipelineRun, _ := h.GetIntegrationPipelineRun(testScenario.Name, snapshot.Name, appNamespace)
-
-		for _, condition := range pipelineRun.Status.Conditions {
-			GinkgoWriter.Printf("PipelineRun %s reason: %s\n", pipelineRun.Name, condition.Reason)
+		pipelineRun, err := h.GetIntegrationPipelineRun(testScenario.Name, snapshot.Name, appNamespace)
+		if err != nil || pipelineRun.Condition() == nil {
+			return false, nil
+		}
+		GinkgoWriter.Printf("PipelineRun %s reason: %s\n", pipelineRun.Name(), pipelineRun.Condition().Reason)
 
-			if !pipelineRun.IsDone() {
-				return false, nil
-			}
+		if !pipelineRun.IsDone() {
+			return false, nil
+		}
 
-			if pipelineRun.GetStatusCondition().GetCondition(apis.ConditionSucceeded).IsTrue() {
-				return true, nil
-			} else {
-				return false, fmt.Errorf(tekton.GetFailedPipelineRunLogs(c, pipelineRun))
-			}
+		if pipelineRun.Succeeded() {
+			return true, nil
+		} else {
+			return false, fmt.Errorf(h.tektonController.GetFailedPipelineRunAccessorLogs(pipelineRun))
 		}
-		return false, nil
 	})
 }
 
 // GetComponentPipeline returns the pipeline for a given component labels
-func (h *SuiteController) GetBuildPipelineRun(componentName, applicationName, namespace string, pacBuild bool, sha string) (*tektonv1beta1.PipelineRun, error) {
+func (h *SuiteController) GetBuildPipelineRun(componentName, applicationName, namespace string, pacBuild bool, sha string) (*tekton.PipelineRunAccessor, error) {
 	pipelineRunLabels := map[string]string{"appstudio.openshift.io/component": componentName, "appstudio.openshift.io/application": applicationName, "pipelines.appstudio.openshift.io/type": "build"}
-	opts := []client.ListOption{
-		client.InNamespace(namespace),
-		client.MatchingLabels{
-			"pipelines.appstudio.openshift.io/type": "build",
-			"appstudio.openshift.io/application":    applicationName,
-			"appstudio.openshift.io/component":      componentName,
-		},
-	}
 
 	if sha != "" {
 		pipelineRunLabels["pipelinesascode.tekton.dev/sha"] = sha
 	}
 
-	list := &tektonv1beta1.PipelineRunList{}
-	err := h.KubeRest().List(context.TODO(), list, opts...)
+	list, err := h.tektonController.ListPipelineRunAccessors(namespace, pipelineRunLabels)
 
 	if err != nil && !k8sErrors.IsNotFound(err) {
 		return nil, fmt.Errorf("error listing pipelineruns in %s namespace: %v", namespace, err)
 	}
 
-	if len(list.Items) > 0 {
-		return &list.Items[0], nil
+	if len(list) > 0 {
+		return list[0], nil
 	}
 
-	return &tektonv1beta1.PipelineRun{}, fmt.Errorf("no pipelinerun found for component %s %s", componentName, utils.GetAdditionalInfo(applicationName, namespace))
+	return nil, fmt.Errorf("no pipelinerun found for component %s %s", componentName, utils.GetAdditionalInfo(applicationName, namespace))
 }
 
 // GetComponentPipeline returns the pipeline for a given component labels
-func (h *SuiteController) GetIntegrationPipelineRun(integrationTestScenarioName string, snapshotName string, namespace string) (*tektonv1beta1.PipelineRun, error) {
-
-	opts := []client.ListOption{
-		client.InNamespace(namespace),
-		client.MatchingLabels{
-			"pipelines.appstudio.openshift.io/type": "test",
-			"test.appstudio.openshift.io/scenario":  integrationTestScenarioName,
-			"appstudio.openshift.io/snapshot":       snapshotName,
-		},
-	}
+func (h *SuiteController) GetIntegrationPipelineRun(integrationTestScenarioName string, snapshotName string, namespace string) (*tekton.PipelineRunAccessor, error) {
 
-	list := &tektonv1beta1.PipelineRunList{}
-	err := h.KubeRest().List(context.TODO(), list, opts...)
+	list, err := h.tektonController.ListPipelineRunAccessors(namespace, map[string]string{
+		"pipelines.appstudio.openshift.io/type": "test",
+		"test.appstudio.openshift.io/scenario":  integrationTestScenarioName,
+		"appstudio.openshift.io/snapshot":       snapshotName,
+	})
 
 	if err != nil && !k8sErrors.IsNotFound(err) {
 		return nil, fmt.Errorf("error listing pipelineruns in %s namespace", namespace)
 	}
 
-	if len(list.Items) > 0 {
-		return &list.Items[0], nil
+	if len(list) > 0 {
+		return list[0], nil
 	}
 
-	return &tektonv1beta1.PipelineRun{}, fmt.Errorf("no pipelinerun found for integrationTestScenario %s (snapshot: %s, namespace: %s)", integrationTestScenarioName, snapshotName, namespace)
+	return nil, fmt.Errorf("no pipelinerun found for integrationTestScenario %s (snapshot: %s, namespace: %s)", integrationTestScenarioName, snapshotName, namespace)
 }
 
 // GetComponentPipeline returns the pipeline for a given component labels
@@ -0,0 +1,191 @@
+package pac
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v44/github"
+)
+
+const (
+	PushEventType        = "push"
+	PullRequestEventType = "pull_request"
+
+	PullRequestActionOpened      = "opened"
+	PullRequestActionSynchronize = "synchronize"
+	PullRequestActionReopened    = "reopened"
+	PullRequestActionClosed      = "closed"
+)
+
+// EventRepository identifies the GitHub repository an event is simulated for
+type EventRepository struct {
+	Owner         string
+	Name          string
+	DefaultBranch string
+	// Defaults to https://github.com/<owner>/<name>
+	HTMLURL string
+	// ID of the GitHub App installation PaC uses for the repository. Can be left empty when PaC is configured with a webhook
+	InstallationID int64
+}
+
+// PushEventOptions describes a push of a commit to a branch
+type PushEventOptions struct {
+	Repository EventRepository
+	Branch     string
+	// SHA of the pushed commit
+	SHA string
+	// SHA of the commit the branch pointed to before the push. Defaults to 40 zeroes (new branch)
+	BeforeSHA string
+	Message   string
+	Sender    string
+	// Files changed by the commit, used by PaC for "on-cel-expression" path matching
+	Added    []string
+	Modified []string
+	Removed  []string
+}
+
+// PullRequestEventOptions describes a change of a pull request
+type PullRequestEventOptions struct {
+	Repository EventRepository
+	// One of PullRequestAction* constants. Defaults to "opened"
+	Action       string
+	Number       int
+	Title        string
+	SourceBranch string
+	TargetBranch string
+	// SHA of the head commit of the pull request
+	HeadSHA string
+	Sender  string
+	Merged  bool
+}
+
+func (r EventRepository) toGithub() *github.Repository {
+	htmlURL := r.HTMLURL
+	if htmlURL == "" {
+		htmlURL = fmt.Sprintf("https://github.com/%s/%s", r.Owner, r.Name)
+	}
+	defaultBranch := r.DefaultBranch
+	if defaultBranch == "" {
+		defaultBranch = "main"
+	}
+	return &github.Repository{
+		Name:          github.String(r.Name),
+		FullName:      github.String(r.Owner + "/" + r.Name),
+		Owner:         &github.User{Login: github.String(r.Owner)},
+		HTMLURL:       github.String(htmlURL),
+		CloneURL:      github.String(htmlURL + ".git"),
+		DefaultBranch: github.String(defaultBranch),
+	}
+}
+
+func (r EventRepository) installation() *github.Installation {
+	if r.InstallationID == 0 {
+		return nil
+	}
+	return &github.Installation{ID: github.Int64(r.InstallationID)}
+}
+
+// NewPushEvent returns the payload GitHub sends to webhooks when a commit is pushed to a branch
+func NewPushEvent(opts PushEventOptions) *github.PushEvent {
+	repo := opts.Repository.toGithub()
+	before := opts.BeforeSHA
+	if before == "" {
+		before = strings.Repeat("0", 40)
+	}
+	sender := opts.Sender
+	if sender == "" {
+		sender = opts.Repository.Owner
+	}
+	now := github.Timestamp{Time: time.Now()}
+	commit := &github.HeadCommit{
+		ID:        github.String(opts.SHA),
+		SHA:       github.String(opts.SHA),
+		Message:   github.String(opts.Message),
+		Timestamp: &now,
+		URL:       github.String(fmt.Sprintf("%s/commit/%s", repo.GetHTMLURL(), opts.SHA)),
+		Author:    &github.CommitAuthor{Name: github.String(sender), Login: github.String(sender)},
+		Committer: &github.CommitAuthor{Name: github.String(sender), Login: github.String(sender)},
+		Added:     opts.Added,
+		Modified:  opts.Modified,
+		Removed:   opts.Removed,
+	}
+
+	return &github.PushEvent{
+		Ref:        github.String("refs/heads/" + opts.Branch),
+		Before:     github.String(before),
+		After:      github.String(opts.SHA),
+		Head:       github.String(opts.SHA),
+		Created:    github.Bool(opts.BeforeSHA == ""),
+		Compare:    github.String(fmt.Sprintf("%s/compare/%s...%s", repo.GetHTMLURL(), before, opts.SHA)),
+		Commits:    []*github.HeadCommit{commit},
+		HeadCommit: commit,
+		Repo: &github.PushEventRepository{
+			Name:          repo.Name,
+			FullName:      repo.FullName,
+			Owner:         &github.User{Login: github.String(opts.Repository.Owner), Name: github.String(opts.Repository.Owner)},
+			HTMLURL:       repo.HTMLURL,
+			CloneURL:      repo.CloneURL,
+			URL:           repo.HTMLURL,
+			DefaultBranch: repo.DefaultBranch,
+			MasterBranch:  repo.DefaultBranch,
+		},
+		Sender:       &github.User{Login: github.String(sender)},
+		Pusher:       &github.User{Login: github.String(sender), Name: github.String(sender)},
+		Installation: opts.Repository.installation(),
+	}
+}
+
+// NewPullRequestEvent returns the payload GitHub sends to webhooks when a pull request is opened, updated or closed
+func NewPullRequestEvent(opts PullRequestEventOptions) *github.PullRequestEvent {
+	repo := opts.Repository.toGithub()
+	action := opts.Action
+	if action == "" {
+		action = PullRequestActionOpened
+	}
+	targetBranch := opts.TargetBranch
+	if targetBranch == "" {
+		targetBranch = repo.GetDefaultBranch()
+	}
+	sender := opts.Sender
+	if sender == "" {
+		sender = opts.Repository.Owner
+	}
+	state := "open"
+	if action == PullRequestActionClosed {
+		state = "closed"
+	}
+	now := time.Now()
+	user := &github.User{Login: github.String(sender)}
+
+	return &github.PullRequestEvent{
+		Action: github.String(action),
+		Number: github.Int(opts.Number),
+		PullRequest: &github.PullRequest{
+			Number:    github.Int(opts.Number),
+			Title:     github.String(opts.Title),
+			State:     github.String(state),
+			Merged:    github.Bool(opts.Merged),
+			HTMLURL:   github.String(fmt.Sprintf("%s/pull/%d", repo.GetHTMLURL(), opts.Number)),
+			User:      user,
+			CreatedAt: &now,
+			UpdatedAt: &now,
+			Head: &github.PullRequestBranch{
+				Label: github.String(opts.Repository.Owner + ":" + opts.SourceBranch),
+				Ref:   github.String(opts.SourceBranch),
+				SHA:   github.String(opts.HeadSHA),
+				Repo:  repo,
+				User:  user,
+			},
+			Base: &github.PullRequestBranch{
+				Label: github.String(opts.Repository.Owner + ":" + targetBranch),
+				Ref:   github.String(targetBranch),
+				Repo:  repo,
+				User:  repo.Owner,
+			},
+		},
+		Repo:         repo,
+		Sender:       user,
+		Installation: opts.Repository.installation(),
+	}
+}
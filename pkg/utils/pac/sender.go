@@ -0,0 +1,133 @@
+package pac
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/devfile/library/pkg/util"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils/common"
+)
+
+const (
+	PaCControllerRouteName      = "pipelines-as-code-controller"
+	PaCControllerRouteNamespace = "pipelines-as-code"
+	// Secret holding the GitHub App credentials of PaC, including the webhook secret
+	PaCSecretName       = "pipelines-as-code-secret"
+	PaCWebhookSecretKey = "webhook.secret"
+
+	EventTypeHeader = "X-GitHub-Event"
+	DeliveryHeader  = "X-GitHub-Delivery"
+	SignatureHeader = "X-Hub-Signature-256"
+	signaturePrefix = "sha256="
+	senderUserAgent = "GitHub-Hookshot/e2e-tests"
+	requestTimeout  = 30 * time.Second
+)
+
+var (
+	// Clients are shared by all senders, so connections to the receiver are reused
+	secureClient   = &http.Client{Timeout: requestTimeout}
+	insecureClient = &http.Client{
+		Timeout:   requestTimeout,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}, //nolint:gosec
+	}
+)
+
+// EventSender posts simulated GitHub webhook events to a receiver (the PaC controller or a local test server)
+type EventSender struct {
+	// URL the events are POSTed to
+	URL string
+	// Secret used for signing the payload. The receiver rejects events signed with a different secret
+	Secret string
+	// Should validate SSL certificate of the receiver
+	IsSecure bool
+}
+
+// NewEventSender returns a sender posting events to the given URL, signed with the webhook secret
+func NewEventSender(url, secret string) *EventSender {
+	return &EventSender{URL: url, Secret: secret}
+}
+
+// NewPaCEventSender returns a sender posting events to the PaC controller route of the cluster
+func NewPaCEventSender(c *common.SuiteController, secret string) (*EventSender, error) {
+	route, err := c.GetOpenshiftRoute(PaCControllerRouteName, PaCControllerRouteNamespace)
+	if err != nil {
+		return nil, fmt.Errorf("error when getting the PaC controller route: %v", err)
+	}
+	return NewEventSender(fmt.Sprintf("https://%s", route.Spec.Host), secret), nil
+}
+
+// GetPaCWebhookSecret returns the webhook secret PaC uses for validating events from the GitHub App
+func GetPaCWebhookSecret(c *common.SuiteController) (string, error) {
+	secret, err := c.GetSecret(PaCControllerRouteNamespace, PaCSecretName)
+	if err != nil {
+		return "", fmt.Errorf("error when getting the PaC secret: %v", err)
+	}
+	value, ok := secret.Data[PaCWebhookSecretKey]
+	if !ok {
+		return "", fmt.Errorf("secret %s/%s doesn't contain the %s key", PaCControllerRouteNamespace, PaCSecretName, PaCWebhookSecretKey)
+	}
+	return string(value), nil
+}
+
+// Sign returns the value of the X-Hub-Signature-256 header for the payload, computed the same way as GitHub does
+func Sign(payload []byte, secret string) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write(payload)
+	return signaturePrefix + hex.EncodeToString(h.Sum(nil))
+}
+
+// SendPushEvent signs and sends a push event and returns the status code of the response
+func (s *EventSender) SendPushEvent(opts PushEventOptions) (int, error) {
+	return s.Send(PushEventType, NewPushEvent(opts))
+}
+
+// SendPullRequestEvent signs and sends a pull_request event and returns the status code of the response
+func (s *EventSender) SendPullRequestEvent(opts PullRequestEventOptions) (int, error) {
+	return s.Send(PullRequestEventType, NewPullRequestEvent(opts))
+}
+
+// Send marshals the event payload, signs it and POSTs it with the headers GitHub sets on webhook deliveries.
+// It returns the status code of the response, and an error if the receiver doesn't respond with a 2xx status code
+func (s *EventSender) Send(eventType string, event interface{}) (int, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return 0, fmt.Errorf("error when marshalling %s event: %v", eventType, err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.URL, bytes.NewBuffer(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", senderUserAgent)
+	req.Header.Set(EventTypeHeader, eventType)
+	req.Header.Set(DeliveryHeader, util.GenerateRandomString(32))
+	req.Header.Set(SignatureHeader, Sign(payload, s.Secret))
+
+	client := insecureClient
+	if s.IsSecure {
+		client = secureClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("error when sending %s event to %s: %v", eventType, s.URL, err)
+	}
+	// The body is always read to the end, so the connection can be reused
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, fmt.Errorf("error when reading the response to %s event from %s: %v", eventType, s.URL, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("%s event was not accepted by %s: status code %d, response: %s", eventType, s.URL, resp.StatusCode, string(body))
+	}
+	return resp.StatusCode, nil
+}
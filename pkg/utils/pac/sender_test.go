@@ -0,0 +1,92 @@
+package pac
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-github/v44/github"
+	"github.com/stretchr/testify/assert"
+)
+
+var testRepository = EventRepository{Owner: "redhat-appstudio-qe", Name: "devfile-sample-hello-world"}
+
+func TestSendPushEvent(t *testing.T) {
+	var received *github.PushEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, err := github.ValidatePayload(r, []byte("secret"))
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		event, err := github.ParseWebHook(github.WebHookType(r), payload)
+		assert.NoError(t, err)
+		received = event.(*github.PushEvent)
+	}))
+	defer server.Close()
+
+	status, err := NewEventSender(server.URL, "secret").SendPushEvent(PushEventOptions{Repository: testRepository, Branch: "main", SHA: "abc"})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "refs/heads/main", received.GetRef())
+	assert.Equal(t, "abc", received.GetHeadCommit().GetID())
+	assert.Equal(t, "redhat-appstudio-qe/devfile-sample-hello-world", received.GetRepo().GetFullName())
+
+	status, err = NewEventSender(server.URL, "wrong").SendPushEvent(PushEventOptions{Repository: testRepository, Branch: "main", SHA: "abc"})
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
+
+// A push of a merge commit to an existing branch, as sent by GitHub when a PaC PR is merged
+func TestSendPushEventOfMergedCommit(t *testing.T) {
+	var eventType string
+	var received *github.PushEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, err := github.ValidatePayload(r, []byte("secret"))
+		assert.NoError(t, err)
+		eventType = github.WebHookType(r)
+		event, err := github.ParseWebHook(eventType, payload)
+		assert.NoError(t, err)
+		received = event.(*github.PushEvent)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	repository := testRepository
+	repository.DefaultBranch = "default"
+	status, err := NewEventSender(server.URL, "secret").SendPushEvent(PushEventOptions{
+		Repository: repository,
+		Branch:     "base-branch",
+		SHA:        "merged",
+		BeforeSHA:  "previous",
+		Modified:   []string{".tekton/component-push.yaml"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, status)
+	assert.Equal(t, PushEventType, eventType)
+	assert.Equal(t, "refs/heads/base-branch", received.GetRef())
+	assert.Equal(t, "previous", received.GetBefore())
+	assert.Equal(t, "merged", received.GetAfter())
+	assert.False(t, received.GetCreated())
+	assert.Equal(t, "default", received.GetRepo().GetDefaultBranch())
+	assert.Equal(t, "https://github.com/redhat-appstudio-qe/devfile-sample-hello-world/compare/previous...merged", received.GetCompare())
+	assert.Equal(t, []string{".tekton/component-push.yaml"}, received.GetHeadCommit().Modified)
+}
+
+func TestSendPullRequestEvent(t *testing.T) {
+	var received *github.PullRequestEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, err := github.ValidatePayload(r, []byte("secret"))
+		assert.NoError(t, err)
+		event, err := github.ParseWebHook(github.WebHookType(r), payload)
+		assert.NoError(t, err)
+		received = event.(*github.PullRequestEvent)
+	}))
+	defer server.Close()
+
+	_, err := NewEventSender(server.URL, "secret").SendPullRequestEvent(PullRequestEventOptions{Repository: testRepository, Number: 1, SourceBranch: "feature", HeadSHA: "abc"})
+	assert.NoError(t, err)
+	assert.Equal(t, PullRequestActionOpened, received.GetAction())
+	assert.Equal(t, "feature", received.GetPullRequest().GetHead().GetRef())
+	assert.Equal(t, "main", received.GetPullRequest().GetBase().GetRef())
+}
@@ -8,8 +8,8 @@ import (
 	appstudioApi "github.com/redhat-appstudio/application-api/api/v1alpha1"
 	kubeCl "github.com/redhat-appstudio/e2e-tests/pkg/apis/kubernetes"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils/tekton"
 	releaseApi "github.com/redhat-appstudio/release-service/api/v1alpha1"
-	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -19,11 +19,15 @@ import (
 
 type SuiteController struct {
 	*kubeCl.CustomClient
+
+	// Used to read the release PipelineRuns with the Tekton API version served by the cluster
+	tektonController *tekton.SuiteController
 }
 
 func NewSuiteController(kube *kubeCl.CustomClient) (*SuiteController, error) {
 	return &SuiteController{
-		kube,
+		CustomClient:     kube,
+		tektonController: tekton.NewSuiteController(kube),
 	}, nil
 }
 
@@ -95,20 +99,14 @@ func (s *SuiteController) CreateReleaseStrategy(name, namespace, pipelineName, b
 }
 
 // GetPipelineRunInNamespace returns the Release PipelineRun referencing the given release.
-func (s *SuiteController) GetPipelineRunInNamespace(namespace, releaseName, releaseNamespace string) (*v1beta1.PipelineRun, error) {
-	pipelineRuns := &v1beta1.PipelineRunList{}
-	opts := []client.ListOption{
-		client.MatchingLabels{
-			"release.appstudio.openshift.io/name":      releaseName,
-			"release.appstudio.openshift.io/namespace": releaseNamespace,
-		},
-		client.InNamespace(namespace),
-	}
-
-	err := s.KubeRest().List(context.TODO(), pipelineRuns, opts...)
-
-	if err == nil && len(pipelineRuns.Items) > 0 {
-		return &pipelineRuns.Items[0], nil
+func (s *SuiteController) GetPipelineRunInNamespace(namespace, releaseName, releaseNamespace string) (*tekton.PipelineRunAccessor, error) {
+	pipelineRuns, err := s.tektonController.ListPipelineRunAccessors(namespace, map[string]string{
+		"release.appstudio.openshift.io/name":      releaseName,
+		"release.appstudio.openshift.io/namespace": releaseNamespace,
+	})
+
+	if err == nil && len(pipelineRuns) > 0 {
+		return pipelineRuns[0], nil
 	}
 
 	return nil, fmt.Errorf("couldn't find PipelineRun in managed namespace '%s' for a release '%s' in '%s' namespace", namespace, releaseName, releaseNamespace)
@@ -297,35 +295,3 @@ func (s *SuiteController) DeleteAllSnapshotsInASpecificNamespace(namespace strin
 		return len(snapshotList.Items) == 0, nil
 	}, timeout)
 }
-
-// CreateComponentWithDockerSource creates a component based on container image source.
-func (s *SuiteController) CreateComponentWithDockerSource(applicationName, componentName, namespace, gitSourceURL, containerImageSource, outputContainerImage, secret string) (*appstudioApi.Component, error) {
-	component := &appstudioApi.Component{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      componentName,
-			Namespace: namespace,
-		},
-		Spec: appstudioApi.ComponentSpec{
-			ComponentName: componentName,
-			Application:   applicationName,
-			Source: appstudioApi.ComponentSource{
-				ComponentSourceUnion: appstudioApi.ComponentSourceUnion{
-					GitSource: &appstudioApi.GitSource{
-						URL:           gitSourceURL,
-						DockerfileURL: containerImageSource,
-					},
-				},
-			},
-			Secret:         secret,
-			ContainerImage: outputContainerImage,
-			Replicas:       1,
-			TargetPort:     8081,
-			Route:          "",
-		},
-	}
-	err := s.KubeRest().Create(context.TODO(), component)
-	if err != nil {
-		return nil, err
-	}
-	return component, nil
-}
@@ -0,0 +1,136 @@
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/tektoncd/pipeline/pkg/remote"
+	"github.com/tektoncd/pipeline/pkg/remote/oci"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Object is a Tekton object stored in a bundle
+type Object struct {
+	Kind       string
+	APIVersion string
+	Name       string
+	Object     runtime.Object
+}
+
+// Bundle is the content of a Tekton bundle
+type Bundle struct {
+	Ref     string
+	Objects []Object
+}
+
+// Inspector fetches Tekton bundles from container image registries. Fetched bundles are cached,
+// so a bundle referenced by multiple pipelines is only pulled once
+type Inspector struct {
+	newResolver func(ref string) remote.Resolver
+	bundles     map[string]*Bundle
+}
+
+// NewInspector returns an Inspector authenticating to registries with the default keychain (docker config)
+func NewInspector() *Inspector {
+	return newInspector(func(ref string) remote.Resolver {
+		return oci.NewResolver(ref, authn.DefaultKeychain)
+	})
+}
+
+func newInspector(newResolver func(ref string) remote.Resolver) *Inspector {
+	return &Inspector{newResolver: newResolver, bundles: map[string]*Bundle{}}
+}
+
+// Inspect returns all the objects stored in a bundle
+func (i *Inspector) Inspect(ref string) (*Bundle, error) {
+	if b, ok := i.bundles[ref]; ok {
+		return b, nil
+	}
+	resolver := i.newResolver(ref)
+	objects, err := resolver.List(context.TODO())
+	if err != nil {
+		return nil, fmt.Errorf("error when listing objects of tekton bundle %s: %v", ref, err)
+	}
+	b := &Bundle{Ref: ref}
+	for _, o := range objects {
+		obj, _, err := resolver.Get(context.TODO(), o.Kind, o.Name)
+		if err != nil {
+			return nil, fmt.Errorf("error when getting %s %s from tekton bundle %s: %v", o.Kind, o.Name, ref, err)
+		}
+		b.Objects = append(b.Objects, Object{Kind: o.Kind, APIVersion: o.APIVersion, Name: o.Name, Object: obj})
+	}
+	sort.SliceStable(b.Objects, func(i, j int) bool {
+		if b.Objects[i].Kind != b.Objects[j].Kind {
+			return b.Objects[i].Kind < b.Objects[j].Kind
+		}
+		return b.Objects[i].Name < b.Objects[j].Name
+	})
+	i.bundles[ref] = b
+	return b, nil
+}
+
+// Task returns a task of the bundle, v1 tasks are converted to v1beta1
+func (b *Bundle) Task(name string) (*v1beta1.Task, error) {
+	obj, err := b.object("task", name)
+	if err != nil {
+		return nil, err
+	}
+	switch t := obj.(type) {
+	case *v1beta1.Task:
+		return t, nil
+	case *tektonv1.Task:
+		task := &v1beta1.Task{}
+		if err := task.ConvertFrom(context.TODO(), t); err != nil {
+			return nil, fmt.Errorf("error when converting task %s of tekton bundle %s: %v", name, b.Ref, err)
+		}
+		return task, nil
+	}
+	return nil, fmt.Errorf("task %s of tekton bundle %s has unexpected type %T", name, b.Ref, obj)
+}
+
+// Pipeline returns a pipeline of the bundle, v1 pipelines are converted to v1beta1
+func (b *Bundle) Pipeline(name string) (*v1beta1.Pipeline, error) {
+	obj, err := b.object("pipeline", name)
+	if err != nil {
+		return nil, err
+	}
+	switch p := obj.(type) {
+	case *v1beta1.Pipeline:
+		return p, nil
+	case *tektonv1.Pipeline:
+		pipeline := &v1beta1.Pipeline{}
+		if err := pipeline.ConvertFrom(context.TODO(), p); err != nil {
+			return nil, fmt.Errorf("error when converting pipeline %s of tekton bundle %s: %v", name, b.Ref, err)
+		}
+		return pipeline, nil
+	}
+	return nil, fmt.Errorf("pipeline %s of tekton bundle %s has unexpected type %T", name, b.Ref, obj)
+}
+
+func (b *Bundle) object(kind, name string) (runtime.Object, error) {
+	for _, o := range b.Objects {
+		if o.Kind == kind && o.Name == name {
+			return o.Object, nil
+		}
+	}
+	return nil, fmt.Errorf("could not find %s %s in tekton bundle %s", kind, name, b.Ref)
+}
+
+// String lists the objects of the bundle as a table
+func (b *Bundle) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "Tekton bundle %s\n", b.Ref)
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tNAME\tAPIVERSION")
+	for _, o := range b.Objects {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", o.Kind, o.Name, o.APIVersion)
+	}
+	_ = w.Flush()
+	return buf.String()
+}
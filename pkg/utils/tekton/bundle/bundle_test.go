@@ -0,0 +1,152 @@
+package bundle
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"github.com/tektoncd/pipeline/pkg/remote"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type fakeResolver struct {
+	objects []runtime.Object
+}
+
+func (r *fakeResolver) List(context.Context) ([]remote.ResolvedObject, error) {
+	var objects []remote.ResolvedObject
+	for _, o := range r.objects {
+		kind, name := objectKindName(o)
+		objects = append(objects, remote.ResolvedObject{Kind: kind, APIVersion: "tekton.dev/v1beta1", Name: name})
+	}
+	return objects, nil
+}
+
+func (r *fakeResolver) Get(_ context.Context, kind, name string) (runtime.Object, *v1beta1.ConfigSource, error) {
+	for _, o := range r.objects {
+		if k, n := objectKindName(o); k == kind && n == name {
+			return o, nil, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("not found")
+}
+
+func objectKindName(o runtime.Object) (string, string) {
+	switch t := o.(type) {
+	case *v1beta1.Task:
+		return "task", t.Name
+	case *v1beta1.Pipeline:
+		return "pipeline", t.Name
+	}
+	return "", ""
+}
+
+func newTestInspector(bundles map[string][]runtime.Object) (*Inspector, map[string]int) {
+	pulls := map[string]int{}
+	return newInspector(func(ref string) remote.Resolver {
+		pulls[ref]++
+		return &fakeResolver{objects: bundles[ref]}
+	}), pulls
+}
+
+func testTask(name string, image string, params ...string) *v1beta1.Task {
+	task := &v1beta1.Task{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: v1beta1.TaskSpec{
+		Steps:   []v1beta1.Step{{Name: "build", Image: image}},
+		Results: []v1beta1.TaskResult{{Name: "IMAGE_DIGEST"}},
+	}}
+	for _, p := range params {
+		task.Spec.Params = append(task.Spec.Params, v1beta1.ParamSpec{Name: p})
+	}
+	return task
+}
+
+func testPipeline(tasks ...v1beta1.PipelineTask) *v1beta1.Pipeline {
+	return &v1beta1.Pipeline{ObjectMeta: metav1.ObjectMeta{Name: "docker-build"}, Spec: v1beta1.PipelineSpec{
+		Params: []v1beta1.ParamSpec{{Name: "git-url"}},
+		Tasks:  tasks,
+	}}
+}
+
+func TestResolvePipeline(t *testing.T) {
+	inspector, pulls := newTestInspector(map[string][]runtime.Object{
+		"quay.io/org/pipeline:1": {testPipeline(
+			v1beta1.PipelineTask{Name: "init", TaskRef: &v1beta1.TaskRef{Name: "init"}},
+			v1beta1.PipelineTask{Name: "build-container", TaskRef: &v1beta1.TaskRef{Name: "buildah", Bundle: "quay.io/org/task-buildah:1"}},
+			v1beta1.PipelineTask{Name: "summary", TaskRef: &v1beta1.TaskRef{ResolverRef: v1beta1.ResolverRef{Resolver: "bundles", Params: []v1beta1.Param{
+				{Name: "bundle", Value: *v1beta1.NewArrayOrString("quay.io/org/task-summary:1")},
+				{Name: "name", Value: *v1beta1.NewArrayOrString("summary")},
+				{Name: "kind", Value: *v1beta1.NewArrayOrString("task")},
+			}}}},
+			v1beta1.PipelineTask{Name: "inline", TaskSpec: &v1beta1.EmbeddedTask{TaskSpec: testTask("", "alpine").Spec}},
+		), testTask("init", "quay.io/org/init:1")},
+		"quay.io/org/task-buildah:1": {testTask("buildah", "quay.io/org/buildah:1")},
+		"quay.io/org/task-summary:1": {testTask("summary", "quay.io/org/summary:1")},
+	})
+
+	tree, err := inspector.ResolvePipeline("quay.io/org/pipeline:1", "docker-build")
+	assert.NoError(t, err)
+	assert.Len(t, tree.Tasks, 4)
+	assert.Equal(t, map[string]string{
+		"init":            "quay.io/org/pipeline:1",
+		"build-container": "quay.io/org/task-buildah:1",
+		"summary":         "quay.io/org/task-summary:1",
+	}, tree.TaskBundles())
+	node, ok := tree.Task("build-container")
+	assert.True(t, ok)
+	assert.Equal(t, "buildah", node.TaskName)
+	assert.Equal(t, "quay.io/org/buildah:1", node.Task.Spec.Steps[0].Image)
+	node, _ = tree.Task("inline")
+	assert.Equal(t, "alpine", node.Task.Spec.Steps[0].Image)
+	assert.Equal(t, 1, pulls["quay.io/org/pipeline:1"])
+
+	b, err := inspector.Inspect("quay.io/org/pipeline:1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pipeline", "task"}, []string{b.Objects[0].Kind, b.Objects[1].Kind})
+	assert.Contains(t, b.String(), "task      init")
+
+	_, err = inspector.ResolvePipeline("quay.io/org/task-buildah:1", "docker-build")
+	assert.Error(t, err)
+}
+
+func TestDiffPipelines(t *testing.T) {
+	inspector, _ := newTestInspector(map[string][]runtime.Object{
+		"quay.io/org/pipeline:1": {testPipeline(
+			v1beta1.PipelineTask{Name: "init", TaskRef: &v1beta1.TaskRef{Name: "init"}},
+			v1beta1.PipelineTask{Name: "build-container", TaskRef: &v1beta1.TaskRef{Name: "buildah", Bundle: "quay.io/org/task-buildah:1"}},
+			v1beta1.PipelineTask{Name: "sast", TaskRef: &v1beta1.TaskRef{Name: "init"}},
+		), testTask("init", "quay.io/org/init:1")},
+		"quay.io/org/pipeline:2": {testPipeline(
+			v1beta1.PipelineTask{Name: "init", TaskRef: &v1beta1.TaskRef{Name: "init"}},
+			v1beta1.PipelineTask{Name: "build-container", TaskRef: &v1beta1.TaskRef{Name: "buildah", Bundle: "quay.io/org/task-buildah:2"}},
+			v1beta1.PipelineTask{Name: "clair-scan", TaskRef: &v1beta1.TaskRef{Name: "init"}},
+		), testTask("init", "quay.io/org/init:1")},
+		"quay.io/org/task-buildah:1": {testTask("buildah", "quay.io/org/buildah:1", "IMAGE", "DOCKERFILE")},
+		"quay.io/org/task-buildah:2": {testTask("buildah", "quay.io/org/buildah:2", "IMAGE", "BUILD_ARGS")},
+	})
+
+	diff, err := inspector.Diff("quay.io/org/pipeline:1", "quay.io/org/pipeline:2", "docker-build")
+	assert.NoError(t, err)
+	assert.False(t, diff.IsEmpty())
+	assert.Equal(t, []string{"clair-scan"}, diff.AddedTasks)
+	assert.Equal(t, []string{"sast"}, diff.RemovedTasks)
+	assert.Len(t, diff.ChangedTasks, 1)
+	changed := diff.ChangedTasks[0]
+	assert.Equal(t, "build-container", changed.PipelineTask)
+	assert.Equal(t, []ImageChange{{Container: "build", OldImage: "quay.io/org/buildah:1", NewImage: "quay.io/org/buildah:2"}}, changed.ImageChanges)
+	assert.Equal(t, []string{"BUILD_ARGS"}, changed.AddedParams)
+	assert.Equal(t, []string{"DOCKERFILE"}, changed.RemovedParams)
+	assert.Equal(t, "1 tasks added, 1 tasks removed, 1 tasks changed, 1 images changed", diff.Summary())
+
+	out := diff.String()
+	assert.Contains(t, out, "+ task clair-scan\n- task sast\n~ task build-container\n")
+	assert.Contains(t, out, "    ~ image build: quay.io/org/buildah:1 -> quay.io/org/buildah:2\n")
+	assert.Contains(t, out, "    + param BUILD_ARGS\n    - param DOCKERFILE\n")
+
+	diff, err = inspector.Diff("quay.io/org/pipeline:1", "quay.io/org/pipeline:1", "docker-build")
+	assert.NoError(t, err)
+	assert.True(t, diff.IsEmpty())
+	assert.Contains(t, diff.String(), "no changes")
+}
@@ -0,0 +1,242 @@
+package bundle
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+)
+
+// PipelineDiff is the difference between two versions of a pipeline and its tasks
+type PipelineDiff struct {
+	OldRef string
+	NewRef string
+
+	AddedTasks   []string
+	RemovedTasks []string
+
+	AddedParams    []string
+	RemovedParams  []string
+	AddedResults   []string
+	RemovedResults []string
+
+	// ChangedTasks are the pipeline tasks present in both versions whose task changed, in the order of the new pipeline
+	ChangedTasks []TaskDiff
+}
+
+// TaskDiff is the difference between two versions of the task of a pipeline task
+type TaskDiff struct {
+	PipelineTask string
+	OldRef       string
+	NewRef       string
+
+	ImageChanges []ImageChange
+
+	AddedSteps     []string
+	RemovedSteps   []string
+	AddedParams    []string
+	RemovedParams  []string
+	AddedResults   []string
+	RemovedResults []string
+}
+
+// ImageChange is a step or sidecar whose image changed. Sidecars are prefixed with "sidecar/"
+type ImageChange struct {
+	Container string
+	OldImage  string
+	NewImage  string
+}
+
+// Diff resolves a pipeline in two bundle versions and diffs them
+func (i *Inspector) Diff(oldRef, newRef, pipelineName string) (*PipelineDiff, error) {
+	oldTree, err := i.ResolvePipeline(oldRef, pipelineName)
+	if err != nil {
+		return nil, err
+	}
+	newTree, err := i.ResolvePipeline(newRef, pipelineName)
+	if err != nil {
+		return nil, err
+	}
+	return DiffPipelines(oldTree, newTree), nil
+}
+
+// DiffPipelines compares two resolved versions of a pipeline. Pipeline tasks are matched by name
+func DiffPipelines(oldTree, newTree *PipelineTree) *PipelineDiff {
+	d := &PipelineDiff{OldRef: oldTree.Ref, NewRef: newTree.Ref}
+
+	d.AddedTasks, d.RemovedTasks = namesDiff(pipelineTaskNames(oldTree), pipelineTaskNames(newTree))
+	d.AddedParams, d.RemovedParams = namesDiff(paramNames(oldTree.Pipeline.Spec.Params), paramNames(newTree.Pipeline.Spec.Params))
+	var oldResults, newResults []string
+	for _, r := range oldTree.Pipeline.Spec.Results {
+		oldResults = append(oldResults, r.Name)
+	}
+	for _, r := range newTree.Pipeline.Spec.Results {
+		newResults = append(newResults, r.Name)
+	}
+	d.AddedResults, d.RemovedResults = namesDiff(oldResults, newResults)
+
+	for _, newNode := range newTree.Tasks {
+		oldNode, ok := oldTree.Task(newNode.PipelineTask)
+		if !ok {
+			continue
+		}
+		if taskDiff := diffTasks(oldNode, newNode); !taskDiff.IsEmpty() {
+			d.ChangedTasks = append(d.ChangedTasks, taskDiff)
+		}
+	}
+	return d
+}
+
+func diffTasks(oldNode, newNode PipelineTaskNode) TaskDiff {
+	d := TaskDiff{PipelineTask: newNode.PipelineTask, OldRef: oldNode.Ref, NewRef: newNode.Ref}
+	oldSpec, newSpec := oldNode.Task.Spec, newNode.Task.Spec
+
+	oldImages, newImages := containerImages(oldSpec), containerImages(newSpec)
+	for _, name := range containerNames(newSpec) {
+		if oldImage, ok := oldImages[name]; ok && oldImage != newImages[name] {
+			d.ImageChanges = append(d.ImageChanges, ImageChange{Container: name, OldImage: oldImage, NewImage: newImages[name]})
+		}
+	}
+	d.AddedSteps, d.RemovedSteps = namesDiff(containerNames(oldSpec), containerNames(newSpec))
+	d.AddedParams, d.RemovedParams = namesDiff(paramNames(oldSpec.Params), paramNames(newSpec.Params))
+	var oldResults, newResults []string
+	for _, r := range oldSpec.Results {
+		oldResults = append(oldResults, r.Name)
+	}
+	for _, r := range newSpec.Results {
+		newResults = append(newResults, r.Name)
+	}
+	d.AddedResults, d.RemovedResults = namesDiff(oldResults, newResults)
+	return d
+}
+
+func pipelineTaskNames(tree *PipelineTree) []string {
+	var names []string
+	for _, node := range tree.Tasks {
+		names = append(names, node.PipelineTask)
+	}
+	return names
+}
+
+func paramNames(params []v1beta1.ParamSpec) []string {
+	var names []string
+	for _, p := range params {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// containerNames returns the names of the steps and sidecars of a task, unnamed steps are named after their index
+func containerNames(spec v1beta1.TaskSpec) []string {
+	var names []string
+	for i, step := range spec.Steps {
+		names = append(names, stepName(step.Name, i))
+	}
+	for i, sidecar := range spec.Sidecars {
+		names = append(names, "sidecar/"+stepName(sidecar.Name, i))
+	}
+	return names
+}
+
+func containerImages(spec v1beta1.TaskSpec) map[string]string {
+	images := map[string]string{}
+	for i, step := range spec.Steps {
+		images[stepName(step.Name, i)] = step.Image
+	}
+	for i, sidecar := range spec.Sidecars {
+		images["sidecar/"+stepName(sidecar.Name, i)] = sidecar.Image
+	}
+	return images
+}
+
+func stepName(name string, index int) string {
+	if name == "" {
+		return fmt.Sprintf("unnamed-%d", index)
+	}
+	return name
+}
+
+// namesDiff returns the names only present in newNames and the names only present in oldNames, both sorted
+func namesDiff(oldNames, newNames []string) (added []string, removed []string) {
+	oldSet, newSet := map[string]bool{}, map[string]bool{}
+	for _, n := range oldNames {
+		oldSet[n] = true
+	}
+	for _, n := range newNames {
+		newSet[n] = true
+		if !oldSet[n] {
+			added = append(added, n)
+		}
+	}
+	for _, n := range oldNames {
+		if !newSet[n] {
+			removed = append(removed, n)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// IsEmpty returns true if the task didn't change, apart from the bundle it's stored in
+func (d TaskDiff) IsEmpty() bool {
+	return len(d.ImageChanges) == 0 && len(d.AddedSteps) == 0 && len(d.RemovedSteps) == 0 &&
+		len(d.AddedParams) == 0 && len(d.RemovedParams) == 0 && len(d.AddedResults) == 0 && len(d.RemovedResults) == 0
+}
+
+// IsEmpty returns true if the pipeline and its tasks didn't change
+func (d *PipelineDiff) IsEmpty() bool {
+	return len(d.AddedTasks) == 0 && len(d.RemovedTasks) == 0 && len(d.AddedParams) == 0 && len(d.RemovedParams) == 0 &&
+		len(d.AddedResults) == 0 && len(d.RemovedResults) == 0 && len(d.ChangedTasks) == 0
+}
+
+// String formats the diff for humans, one change per line
+func (d *PipelineDiff) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "--- %s\n+++ %s\n", d.OldRef, d.NewRef)
+	if d.IsEmpty() {
+		buf.WriteString("no changes\n")
+		return buf.String()
+	}
+	writeNames(&buf, "", "task", d.AddedTasks, d.RemovedTasks)
+	writeNames(&buf, "", "param", d.AddedParams, d.RemovedParams)
+	writeNames(&buf, "", "result", d.AddedResults, d.RemovedResults)
+	for _, t := range d.ChangedTasks {
+		fmt.Fprintf(&buf, "~ task %s\n", t.PipelineTask)
+		if t.OldRef != t.NewRef {
+			fmt.Fprintf(&buf, "    bundle %s -> %s\n", t.OldRef, t.NewRef)
+		}
+		for _, c := range t.ImageChanges {
+			fmt.Fprintf(&buf, "    ~ image %s: %s -> %s\n", c.Container, c.OldImage, c.NewImage)
+		}
+		writeNames(&buf, "    ", "step", t.AddedSteps, t.RemovedSteps)
+		writeNames(&buf, "    ", "param", t.AddedParams, t.RemovedParams)
+		writeNames(&buf, "    ", "result", t.AddedResults, t.RemovedResults)
+	}
+	return buf.String()
+}
+
+func writeNames(buf *bytes.Buffer, indent, kind string, added, removed []string) {
+	for _, n := range added {
+		fmt.Fprintf(buf, "%s+ %s %s\n", indent, kind, n)
+	}
+	for _, n := range removed {
+		fmt.Fprintf(buf, "%s- %s %s\n", indent, kind, n)
+	}
+}
+
+// Summary returns a one line summary of the diff
+func (d *PipelineDiff) Summary() string {
+	var images int
+	for _, t := range d.ChangedTasks {
+		images += len(t.ImageChanges)
+	}
+	return strings.Join([]string{
+		fmt.Sprintf("%d tasks added", len(d.AddedTasks)),
+		fmt.Sprintf("%d tasks removed", len(d.RemovedTasks)),
+		fmt.Sprintf("%d tasks changed", len(d.ChangedTasks)),
+		fmt.Sprintf("%d images changed", images),
+	}, ", ")
+}
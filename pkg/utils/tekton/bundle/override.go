@@ -0,0 +1,305 @@
+package bundle
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/devfile/library/pkg/util"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	remoteimg "github.com/google/go-containerregistry/pkg/v1/remote"
+	buildservice "github.com/redhat-appstudio/build-service/api/v1alpha1"
+	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// Override replaces the image of a step of a task, or the bundle of a task, used by the pipeline of a build pipeline selector
+type Override struct {
+	// Selector is the name of the selector in the build pipeline selector, e.g. "Java"
+	Selector string `json:"selector"`
+	// Task is the name of the task, or of the pipeline task, to override
+	Task string `json:"task"`
+
+	// Step and Image replace the image of a step of the task
+	Step  string `json:"step,omitempty"`
+	Image string `json:"image,omitempty"`
+
+	// TaskBundle replaces the bundle the task is taken from. It's applied before the step images
+	TaskBundle string `json:"taskBundle,omitempty"`
+}
+
+// OverrideOptions configures where the selectors are read from and where the rewritten bundles are pushed to
+type OverrideOptions struct {
+	// SelectorYamlURL defaults to constants.BuildPipelineSelectorYamlURL
+	SelectorYamlURL string
+	// Repo defaults to constants.DefaultImagePushRepo
+	Repo string
+	// RemoteOption defaults to the authentication with the default keychain
+	RemoteOption remoteimg.Option
+}
+
+// OverriddenPipeline is a pipeline bundle pushed with overrides applied
+type OverriddenPipeline struct {
+	Selector       string
+	PipelineName   string
+	OriginalBundle string
+	Bundle         string
+}
+
+// ParseOverrides parses a YAML or JSON list of overrides
+func ParseOverrides(data string) ([]Override, error) {
+	var overrides []Override
+	if err := yaml.UnmarshalStrict([]byte(data), &overrides); err != nil {
+		return nil, fmt.Errorf("error when parsing bundle overrides: %v", err)
+	}
+	if err := ValidateOverrides(overrides); err != nil {
+		return nil, err
+	}
+	return overrides, nil
+}
+
+// ValidateOverrides checks that each override has a selector, a task and either a step with an image or a task bundle
+func ValidateOverrides(overrides []Override) error {
+	for _, o := range overrides {
+		if o.Selector == "" || o.Task == "" {
+			return fmt.Errorf("bundle override %+v needs a selector and a task", o)
+		}
+		if (o.Step == "") != (o.Image == "") || (o.Image == "" && o.TaskBundle == "") {
+			return fmt.Errorf("bundle override %+v needs either a step with an image or a task bundle", o)
+		}
+	}
+	return nil
+}
+
+// PipelineBundleEnv returns the env var a custom bundle of a pipeline is exposed to the specs with,
+// e.g. CUSTOM_JAVA_PIPELINE_BUILD_BUNDLE for java-builder
+func PipelineBundleEnv(pipelineName string) string {
+	n := strings.TrimSuffix(strings.TrimSuffix(pipelineName, "-builder"), "-build")
+	return fmt.Sprintf("CUSTOM_%s_PIPELINE_BUILD_BUNDLE", strings.ToUpper(strings.ReplaceAll(n, "-", "_")))
+}
+
+// CustomPipelineBundle returns the custom bundle of a pipeline exposed in the env var returned by PipelineBundleEnv,
+// or an empty string if there is none
+func CustomPipelineBundle(pipelineName string) string {
+	return os.Getenv(PipelineBundleEnv(pipelineName))
+}
+
+// HasCustomPipelineBundles returns true if a custom bundle of any pipeline is exposed in an env var returned by PipelineBundleEnv
+func HasCustomPipelineBundles() bool {
+	for _, env := range os.Environ() {
+		name, value, _ := strings.Cut(env, "=")
+		if value != "" && strings.HasPrefix(name, "CUSTOM_") && strings.HasSuffix(name, "_PIPELINE_BUILD_BUNDLE") {
+			return true
+		}
+	}
+	return false
+}
+
+// CustomPipelineSelectors returns copies of the selectors whose pipeline has a custom bundle, pointing to that bundle.
+// Selectors of pipelines without a custom bundle are left out
+func CustomPipelineSelectors(selectors []buildservice.PipelineSelector) []buildservice.PipelineSelector {
+	var custom []buildservice.PipelineSelector
+	for _, selector := range selectors {
+		if b := CustomPipelineBundle(selector.PipelineRef.Name); b != "" {
+			selector := *selector.DeepCopy()
+			selector.PipelineRef.Bundle = b
+			custom = append(custom, selector)
+		}
+	}
+	return custom
+}
+
+// ApplyOverrides extracts the pipelines of the selectors and the tasks the overrides refer to from their bundles,
+// rewrites them and pushes them as new bundles
+func ApplyOverrides(overrides []Override, opts OverrideOptions) ([]OverriddenPipeline, error) {
+	if err := ValidateOverrides(overrides); err != nil {
+		return nil, err
+	}
+	if opts.RemoteOption == nil {
+		opts.RemoteOption = remoteimg.WithAuthFromKeychain(authn.NewMultiKeychain(authn.DefaultKeychain))
+	}
+	push := func(content []byte, ref name.Reference) error {
+		return utils.BuildAndPushTektonBundle(content, ref, opts.RemoteOption)
+	}
+	return applyOverrides(NewInspector(), push, overrides, opts)
+}
+
+// SetPipelineBundleEnvs exports the overridden pipeline bundles to the specs, in the env vars returned by PipelineBundleEnv
+func SetPipelineBundleEnvs(pipelines []OverriddenPipeline) {
+	for _, p := range pipelines {
+		os.Setenv(PipelineBundleEnv(p.PipelineName), p.Bundle)
+	}
+}
+
+func applyOverrides(inspector *Inspector, push func([]byte, name.Reference) error, overrides []Override, opts OverrideOptions) ([]OverriddenPipeline, error) {
+	if opts.SelectorYamlURL == "" {
+		opts.SelectorYamlURL = constants.BuildPipelineSelectorYamlURL
+	}
+	if opts.Repo == "" {
+		opts.Repo = constants.DefaultImagePushRepo
+	}
+	tag := fmt.Sprintf("%d-%s", time.Now().Unix(), util.GenerateRandomString(4))
+
+	// Overrides are applied per selector, in the order they are given
+	var selectors []string
+	bySelector := map[string][]Override{}
+	for _, o := range overrides {
+		if _, ok := bySelector[o.Selector]; !ok {
+			selectors = append(selectors, o.Selector)
+		}
+		bySelector[o.Selector] = append(bySelector[o.Selector], o)
+	}
+
+	var result []OverriddenPipeline
+	for _, selector := range selectors {
+		pipelineRef, err := utils.GetDefaultPipelineRef(opts.SelectorYamlURL, selector)
+		if err != nil {
+			return nil, err
+		}
+		b, err := inspector.Inspect(pipelineRef.Bundle)
+		if err != nil {
+			return nil, err
+		}
+		pipeline, err := b.Pipeline(pipelineRef.Name)
+		if err != nil {
+			return nil, err
+		}
+		pipeline = pipeline.DeepCopy()
+
+		for _, task := range overriddenTasks(bySelector[selector]) {
+			if err := overrideTask(inspector, push, pipeline, pipelineRef.Bundle, task, bySelector[selector], opts.Repo, tag); err != nil {
+				return nil, fmt.Errorf("error when overriding task %s of pipeline %s: %v", task, pipelineRef.Name, err)
+			}
+		}
+
+		ref, err := name.ParseReference(fmt.Sprintf("%s:pipeline-bundle-%s-%s", opts.Repo, pipelineRef.Name, tag))
+		if err != nil {
+			return nil, fmt.Errorf("error when parsing the pipeline bundle reference: %v", err)
+		}
+		pipeline.TypeMeta = metav1.TypeMeta{APIVersion: v1beta1.SchemeGroupVersion.String(), Kind: "Pipeline"}
+		if err := pushObject(push, pipeline, "pipeline", ref); err != nil {
+			return nil, err
+		}
+		result = append(result, OverriddenPipeline{Selector: selector, PipelineName: pipelineRef.Name, OriginalBundle: pipelineRef.Bundle, Bundle: ref.String()})
+	}
+	return result, nil
+}
+
+func overriddenTasks(overrides []Override) []string {
+	var tasks []string
+	seen := map[string]bool{}
+	for _, o := range overrides {
+		if !seen[o.Task] {
+			seen[o.Task] = true
+			tasks = append(tasks, o.Task)
+		}
+	}
+	return tasks
+}
+
+// overrideTask applies the overrides of a task and points the pipeline tasks running it to the new task bundle
+func overrideTask(inspector *Inspector, push func([]byte, name.Reference) error, pipeline *v1beta1.Pipeline, pipelineBundle, task string, overrides []Override, repo, tag string) error {
+	var taskRefs []*v1beta1.TaskRef
+	for _, tasks := range [][]v1beta1.PipelineTask{pipeline.Spec.Tasks, pipeline.Spec.Finally} {
+		for i := range tasks {
+			if tasks[i].TaskRef != nil && (tasks[i].Name == task || taskRefName(tasks[i].TaskRef) == task) {
+				taskRefs = append(taskRefs, tasks[i].TaskRef)
+			}
+		}
+	}
+	if len(taskRefs) == 0 {
+		return fmt.Errorf("no pipeline task references the task")
+	}
+
+	taskBundle, taskName := taskRefBundle(taskRefs[0])
+	if taskBundle == "" {
+		taskBundle = pipelineBundle
+	}
+	images := map[string]string{}
+	for _, o := range overrides {
+		if o.Task != task {
+			continue
+		}
+		if o.TaskBundle != "" {
+			taskBundle = o.TaskBundle
+		}
+		if o.Step != "" {
+			images[o.Step] = o.Image
+		}
+	}
+
+	if len(images) > 0 {
+		b, err := inspector.Inspect(taskBundle)
+		if err != nil {
+			return err
+		}
+		t, err := b.Task(taskName)
+		if err != nil {
+			return err
+		}
+		t = t.DeepCopy()
+		for i := range t.Spec.Steps {
+			if image, ok := images[t.Spec.Steps[i].Name]; ok {
+				t.Spec.Steps[i].Image = image
+				delete(images, t.Spec.Steps[i].Name)
+			}
+		}
+		if len(images) > 0 {
+			var missing []string
+			for step := range images {
+				missing = append(missing, step)
+			}
+			sort.Strings(missing)
+			return fmt.Errorf("task %s in bundle %s has no steps %s", taskName, taskBundle, strings.Join(missing, ", "))
+		}
+		ref, err := name.ParseReference(fmt.Sprintf("%s:task-bundle-%s-%s", repo, taskName, tag))
+		if err != nil {
+			return fmt.Errorf("error when parsing the task bundle reference: %v", err)
+		}
+		t.TypeMeta = metav1.TypeMeta{APIVersion: v1beta1.SchemeGroupVersion.String(), Kind: "Task"}
+		if err := pushObject(push, t, "task", ref); err != nil {
+			return err
+		}
+		taskBundle = ref.String()
+	}
+
+	for _, taskRef := range taskRefs {
+		setTaskRefBundle(taskRef, taskBundle)
+	}
+	return nil
+}
+
+// taskRefName returns the name of the task a task reference points to
+func taskRefName(taskRef *v1beta1.TaskRef) string {
+	_, taskName := taskRefBundle(taskRef)
+	return taskName
+}
+
+func setTaskRefBundle(taskRef *v1beta1.TaskRef, bundle string) {
+	if taskRef.Resolver != "bundles" {
+		taskRef.Bundle = bundle
+		return
+	}
+	for i := range taskRef.Params {
+		if taskRef.Params[i].Name == "bundle" {
+			taskRef.Params[i].Value = *v1beta1.NewArrayOrString(bundle)
+		}
+	}
+}
+
+func pushObject(push func([]byte, name.Reference) error, obj interface{}, kind string, ref name.Reference) error {
+	content, err := yaml.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("error when marshalling a %s to YAML: %v", kind, err)
+	}
+	if err := push(content, ref); err != nil {
+		return fmt.Errorf("error when building/pushing a tekton %s bundle: %v", kind, err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package bundle
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+const testBuildPipelineSelector = `apiVersion: appstudio.redhat.com/v1alpha1
+kind: BuildPipelineSelector
+metadata:
+  name: build-pipeline-selector
+spec:
+  selectors:
+  - name: Docker build
+    pipelineRef:
+      name: docker-build
+      bundle: quay.io/org/pipeline:1
+`
+
+func TestParseOverrides(t *testing.T) {
+	overrides, err := ParseOverrides(`[{"selector": "Docker build", "task": "buildah", "step": "build", "image": "quay.io/org/buildah:pr"}]`)
+	assert.NoError(t, err)
+	assert.Equal(t, []Override{{Selector: "Docker build", Task: "buildah", Step: "build", Image: "quay.io/org/buildah:pr"}}, overrides)
+
+	overrides, err = ParseOverrides("- selector: Java\n  task: s2i-java\n  taskBundle: quay.io/org/task-s2i-java:pr\n")
+	assert.NoError(t, err)
+	assert.Equal(t, "quay.io/org/task-s2i-java:pr", overrides[0].TaskBundle)
+
+	for _, spec := range []string{
+		`[{"selector": "Java", "task": "s2i-java", "step": "build"}]`,
+		`[{"selector": "Java", "task": "s2i-java"}]`,
+		`[{"task": "s2i-java", "taskBundle": "quay.io/org/task:1"}]`,
+		`[{"selector": "Java", "task": "s2i-java", "taskBundle": "quay.io/org/task:1", "unknown": true}]`,
+	} {
+		_, err = ParseOverrides(spec)
+		assert.Error(t, err, spec)
+	}
+
+	// Overrides built in the code, e.g. from an unset image env var, are validated before anything gets pulled or pushed
+	_, err = ApplyOverrides([]Override{{Selector: "Java", Task: "s2i-java", Step: "analyse-dependencies-java-sbom"}}, OverrideOptions{})
+	assert.ErrorContains(t, err, "needs either a step with an image or a task bundle")
+}
+
+func TestPipelineBundleEnv(t *testing.T) {
+	assert.Equal(t, constants.CUSTOM_JAVA_PIPELINE_BUILD_BUNDLE_ENV, PipelineBundleEnv("java-builder"))
+	assert.Equal(t, "CUSTOM_DOCKER_PIPELINE_BUILD_BUNDLE", PipelineBundleEnv("docker-build"))
+	assert.Equal(t, "CUSTOM_FBC_PIPELINE_BUILD_BUNDLE", PipelineBundleEnv("fbc-builder"))
+
+	t.Setenv("CUSTOM_DOCKER_PIPELINE_BUILD_BUNDLE", "quay.io/org/pipeline:pr")
+	assert.True(t, HasCustomPipelineBundles())
+	assert.Equal(t, "quay.io/org/pipeline:pr", CustomPipelineBundle("docker-build"))
+	assert.Empty(t, CustomPipelineBundle("nodejs-builder"))
+}
+
+func TestApplyOverrides(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testBuildPipelineSelector))
+	}))
+	defer server.Close()
+
+	inspector, _ := newTestInspector(map[string][]runtime.Object{
+		"quay.io/org/pipeline:1": {testPipeline(
+			v1beta1.PipelineTask{Name: "init", TaskRef: &v1beta1.TaskRef{Name: "init"}},
+			v1beta1.PipelineTask{Name: "build-container", TaskRef: &v1beta1.TaskRef{Name: "buildah", Bundle: "quay.io/org/task-buildah:1"}},
+			v1beta1.PipelineTask{Name: "summary", TaskRef: &v1beta1.TaskRef{Name: "summary", Bundle: "quay.io/org/task-summary:1"}},
+		), testTask("init", "quay.io/org/init:1")},
+		"quay.io/org/task-buildah:1": {testTask("buildah", "quay.io/org/buildah:1")},
+	})
+	pushed := map[string]string{}
+	push := func(content []byte, ref name.Reference) error {
+		pushed[ref.String()] = string(content)
+		return nil
+	}
+
+	pipelines, err := applyOverrides(inspector, push, []Override{
+		{Selector: "Docker build", Task: "buildah", Step: "build", Image: "quay.io/org/buildah:pr"},
+		{Selector: "Docker build", Task: "summary", TaskBundle: "quay.io/org/task-summary:pr"},
+	}, OverrideOptions{SelectorYamlURL: server.URL, Repo: "quay.io/org/test-images"})
+	assert.NoError(t, err)
+	assert.Len(t, pipelines, 1)
+	assert.Equal(t, "Docker build", pipelines[0].Selector)
+	assert.Equal(t, "docker-build", pipelines[0].PipelineName)
+	assert.Equal(t, "quay.io/org/pipeline:1", pipelines[0].OriginalBundle)
+	assert.Regexp(t, `^quay.io/org/test-images:pipeline-bundle-docker-build-`, pipelines[0].Bundle)
+	assert.Len(t, pushed, 2)
+
+	pipeline := &v1beta1.Pipeline{}
+	assert.NoError(t, yaml.Unmarshal([]byte(pushed[pipelines[0].Bundle]), pipeline))
+	assert.Equal(t, "Pipeline", pipeline.Kind)
+	assert.Equal(t, "", pipeline.Spec.Tasks[0].TaskRef.Bundle)
+	assert.Regexp(t, `^quay.io/org/test-images:task-bundle-buildah-`, pipeline.Spec.Tasks[1].TaskRef.Bundle)
+	assert.Equal(t, "quay.io/org/task-summary:pr", pipeline.Spec.Tasks[2].TaskRef.Bundle)
+
+	task := &v1beta1.Task{}
+	assert.NoError(t, yaml.Unmarshal([]byte(pushed[pipeline.Spec.Tasks[1].TaskRef.Bundle]), task))
+	assert.Equal(t, "Task", task.Kind)
+	assert.Equal(t, "quay.io/org/buildah:pr", task.Spec.Steps[0].Image)
+
+	// The fetched bundles aren't modified
+	b, err := inspector.Inspect("quay.io/org/task-buildah:1")
+	assert.NoError(t, err)
+	original, err := b.Task("buildah")
+	assert.NoError(t, err)
+	assert.Equal(t, "quay.io/org/buildah:1", original.Spec.Steps[0].Image)
+
+	_, err = applyOverrides(inspector, push, []Override{{Selector: "Docker build", Task: "buildah", Step: "push", Image: "quay.io/org/buildah:pr"}},
+		OverrideOptions{SelectorYamlURL: server.URL})
+	assert.ErrorContains(t, err, "has no steps push")
+	_, err = applyOverrides(inspector, push, []Override{{Selector: "Docker build", Task: "clair-scan", TaskBundle: "quay.io/org/task:1"}},
+		OverrideOptions{SelectorYamlURL: server.URL})
+	assert.ErrorContains(t, err, "no pipeline task references the task")
+}
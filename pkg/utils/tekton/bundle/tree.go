@@ -0,0 +1,116 @@
+package bundle
+
+import (
+	"fmt"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+)
+
+// PipelineTree is a pipeline of a bundle with the tasks its pipeline tasks reference
+type PipelineTree struct {
+	Ref      string
+	Pipeline *v1beta1.Pipeline
+
+	// Tasks in the order of the pipeline tasks, followed by the finally tasks
+	Tasks []PipelineTaskNode
+}
+
+// PipelineTaskNode is a pipeline task with the task it runs
+type PipelineTaskNode struct {
+	PipelineTask string
+	Finally      bool
+
+	// Ref is the bundle the task is stored in, empty for embedded task specs
+	Ref      string
+	TaskName string
+	Task     *v1beta1.Task
+}
+
+// ResolvePipeline resolves a pipeline of a bundle along with the tasks referenced by its pipeline tasks.
+// Task references without a bundle are looked up in the pipeline bundle
+func (i *Inspector) ResolvePipeline(ref, name string) (*PipelineTree, error) {
+	b, err := i.Inspect(ref)
+	if err != nil {
+		return nil, err
+	}
+	pipeline, err := b.Pipeline(name)
+	if err != nil {
+		return nil, err
+	}
+	tree := &PipelineTree{Ref: ref, Pipeline: pipeline}
+	for _, tasks := range []struct {
+		tasks   []v1beta1.PipelineTask
+		finally bool
+	}{{pipeline.Spec.Tasks, false}, {pipeline.Spec.Finally, true}} {
+		for _, pt := range tasks.tasks {
+			node, err := i.resolvePipelineTask(ref, pt)
+			if err != nil {
+				return nil, fmt.Errorf("error when resolving pipeline task %s of pipeline %s in tekton bundle %s: %v", pt.Name, name, ref, err)
+			}
+			node.Finally = tasks.finally
+			tree.Tasks = append(tree.Tasks, node)
+		}
+	}
+	return tree, nil
+}
+
+func (i *Inspector) resolvePipelineTask(pipelineRef string, pt v1beta1.PipelineTask) (PipelineTaskNode, error) {
+	node := PipelineTaskNode{PipelineTask: pt.Name}
+	if pt.TaskSpec != nil {
+		node.Task = &v1beta1.Task{Spec: pt.TaskSpec.TaskSpec}
+		return node, nil
+	}
+	if pt.TaskRef == nil {
+		return node, fmt.Errorf("neither taskRef nor taskSpec is set")
+	}
+
+	node.Ref, node.TaskName = taskRefBundle(pt.TaskRef)
+	if node.Ref == "" {
+		node.Ref = pipelineRef
+	}
+	b, err := i.Inspect(node.Ref)
+	if err != nil {
+		return node, err
+	}
+	node.Task, err = b.Task(node.TaskName)
+	return node, err
+}
+
+// taskRefBundle returns the bundle and task name of a task reference, given either with the bundle
+// field or with the bundles resolver
+func taskRefBundle(taskRef *v1beta1.TaskRef) (string, string) {
+	if taskRef.Bundle != "" || taskRef.Resolver != "bundles" {
+		return taskRef.Bundle, taskRef.Name
+	}
+	var ref, name string
+	for _, param := range taskRef.Params {
+		switch param.Name {
+		case "bundle":
+			ref = param.Value.StringVal
+		case "name":
+			name = param.Value.StringVal
+		}
+	}
+	return ref, name
+}
+
+// Task returns the node of a pipeline task
+func (t *PipelineTree) Task(pipelineTask string) (PipelineTaskNode, bool) {
+	for _, node := range t.Tasks {
+		if node.PipelineTask == pipelineTask {
+			return node, true
+		}
+	}
+	return PipelineTaskNode{}, false
+}
+
+// TaskBundles returns the bundles of all the tasks of the pipeline, by pipeline task
+func (t *PipelineTree) TaskBundles() map[string]string {
+	bundles := map[string]string{}
+	for _, node := range t.Tasks {
+		if node.Ref != "" {
+			bundles[node.PipelineTask] = node.Ref
+		}
+	}
+	return bundles
+}
@@ -16,6 +16,7 @@ import (
 	ecp "github.com/enterprise-contract/enterprise-contract-controller/api/v1alpha1"
 	kubeCl "github.com/redhat-appstudio/e2e-tests/pkg/apis/kubernetes"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils/common"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils/tekton/bundle"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -47,6 +48,8 @@ type Bundles struct {
 // Create the struct for kubernetes clients
 type SuiteController struct {
 	*kubeCl.CustomClient
+
+	apiVersion *pipelineAPIVersion
 }
 
 type CosignResult struct {
@@ -73,7 +76,7 @@ func (c CosignResult) Missing(prefix string) string {
 
 // Create controller for Tekton Task/Pipeline CRUD operations
 func NewSuiteController(kube *kubeCl.CustomClient) *SuiteController {
-	return &SuiteController{kube}
+	return &SuiteController{CustomClient: kube, apiVersion: &pipelineAPIVersion{}}
 }
 
 func (s *SuiteController) NewBundles() (*Bundles, error) {
@@ -90,6 +93,10 @@ func (s *SuiteController) NewBundles() (*Bundles, error) {
 	for _, selector := range pipelineSelector.Spec.Selectors {
 		bundleName := selector.PipelineRef.Name
 		bundleRef := selector.PipelineRef.Bundle
+		// Bundles overridden for testing a PR take precedence over the ones of the cluster
+		if customBundleRef := bundle.CustomPipelineBundle(bundleName); customBundleRef != "" {
+			bundleRef = customBundleRef
+		}
 		switch bundleName {
 		case "docker-build":
 			bundles.DockerBuildBundle = bundleRef
@@ -162,26 +169,18 @@ func (s *SuiteController) GetPipelineRunLogs(pipelineRunName, namespace string)
 }
 
 func (s *SuiteController) GetTaskRunLogs(pipelineRunName, taskName, namespace string) (map[string]string, error) {
-	tektonClient := s.PipelineClient().TektonV1beta1().PipelineRuns(namespace)
-	pipelineRun, err := tektonClient.Get(context.TODO(), pipelineRunName, metav1.GetOptions{})
+	pipelineRun, err := s.GetPipelineRunAccessor(pipelineRunName, namespace)
 	if err != nil {
 		return nil, err
 	}
 
-	podName := ""
-	for _, childStatusReference := range pipelineRun.Status.ChildReferences {
-		if childStatusReference.PipelineTaskName == taskName {
-			taskRun := &v1beta1.TaskRun{}
-			taskRunKey := types.NamespacedName{Namespace: pipelineRun.Namespace, Name: childStatusReference.Name}
-			if err := s.KubeRest().Get(context.TODO(), taskRunKey, taskRun); err != nil {
-				return nil, err
-			}
-			podName = taskRun.Status.PodName
-			break
-		}
+	taskRun, err := s.GetPipelineTaskRun(pipelineRun, taskName)
+	if err != nil {
+		return nil, err
 	}
+	podName := taskRun.PodName()
 	if podName == "" {
-		return nil, fmt.Errorf("task with %s name doesn't exist in %s pipelinerun", taskName, pipelineRunName)
+		return nil, fmt.Errorf("task with %s name has no pod in %s pipelinerun", taskName, pipelineRunName)
 	}
 
 	podClient := s.KubeInterface().CoreV1().Pods(namespace)
@@ -204,44 +203,31 @@ func (s *SuiteController) GetTaskRunLogs(pipelineRunName, taskName, namespace st
 
 func (s *SuiteController) CheckPipelineRunStarted(pipelineRunName, namespace string) wait.ConditionFunc {
 	return func() (bool, error) {
-		pr, err := s.GetPipelineRun(pipelineRunName, namespace)
+		pr, err := s.GetPipelineRunAccessor(pipelineRunName, namespace)
 		if err != nil {
 			return false, nil
 		}
-		if pr.Status.StartTime != nil {
-			return true, nil
-		}
-		return false, nil
+		return pr.HasStarted(), nil
 	}
 }
 
 func (s *SuiteController) CheckPipelineRunFinished(pipelineRunName, namespace string) wait.ConditionFunc {
 	return func() (bool, error) {
-		pr, err := s.GetPipelineRun(pipelineRunName, namespace)
+		pr, err := s.GetPipelineRunAccessor(pipelineRunName, namespace)
 		if err != nil {
 			return false, nil
 		}
-		if pr.Status.CompletionTime != nil {
-			return true, nil
-		}
-		return false, nil
+		return pr.CompletionTime() != nil, nil
 	}
 }
 
 func (s *SuiteController) CheckPipelineRunSucceeded(pipelineRunName, namespace string) wait.ConditionFunc {
 	return func() (bool, error) {
-		pr, err := s.GetPipelineRun(pipelineRunName, namespace)
+		pr, err := s.GetPipelineRunAccessor(pipelineRunName, namespace)
 		if err != nil {
 			return false, err
 		}
-		if len(pr.Status.Conditions) > 0 {
-			for _, c := range pr.Status.Conditions {
-				if c.Type == "Succeeded" && c.Status == "True" {
-					return true, nil
-				}
-			}
-		}
-		return false, nil
+		return pr.Succeeded(), nil
 	}
 }
 
@@ -304,11 +290,8 @@ func (k KubeController) WatchPipelineRunSucceeded(pipelineRunName string, taskTi
 }
 
 func (k KubeController) GetTaskRunResult(pr *v1beta1.PipelineRun, pipelineTaskName string, result string) (string, error) {
-	for _, tr := range pr.Status.TaskRuns {
-		if tr.PipelineTaskName != pipelineTaskName {
-			continue
-		}
-
+	tr, err := k.GetTaskRunStatus(pr, pipelineTaskName)
+	if err == nil {
 		for _, trResult := range tr.Status.TaskRunResults {
 			if trResult.Name == result {
 				// for some reason the result might contain \n suffix
@@ -320,12 +303,24 @@ func (k KubeController) GetTaskRunResult(pr *v1beta1.PipelineRun, pipelineTaskNa
 		"result %q not found in TaskRuns of PipelineRun %s/%s", result, pr.ObjectMeta.Namespace, pr.ObjectMeta.Name)
 }
 
+// GetTaskRunStatus returns the status of the TaskRun of a pipeline task. If the PipelineRun status only has
+// references to its TaskRuns (minimal embedded status, always the case for PipelineRuns stored as v1), the TaskRun is fetched
 func (k KubeController) GetTaskRunStatus(pr *v1beta1.PipelineRun, pipelineTaskName string) (*v1beta1.PipelineRunTaskRunStatus, error) {
 	for _, tr := range pr.Status.TaskRuns {
 		if tr.PipelineTaskName == pipelineTaskName {
 			return tr, nil
 		}
 	}
+	for _, child := range pr.Status.ChildReferences {
+		if child.PipelineTaskName != pipelineTaskName || child.Kind != "TaskRun" {
+			continue
+		}
+		taskRun, err := k.Tektonctrl.PipelineClient().TektonV1beta1().TaskRuns(pr.Namespace).Get(context.TODO(), child.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error when getting TaskRun %s of PipelineRun %s/%s: %v", child.Name, pr.Namespace, pr.Name, err)
+		}
+		return &v1beta1.PipelineRunTaskRunStatus{PipelineTaskName: pipelineTaskName, Status: &taskRun.Status}, nil
+	}
 	return nil, fmt.Errorf(
 		"TaskRun status for pipeline task name %q not found in the status of PipelineRun %s/%s", pipelineTaskName, pr.ObjectMeta.Namespace, pr.ObjectMeta.Name)
 }
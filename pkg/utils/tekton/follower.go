@@ -0,0 +1,405 @@
+package tekton
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	g "github.com/onsi/ginkgo/v2"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	pipelineclientset "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+)
+
+// PipelineRunOutcome is the way a followed PipelineRun finished
+type PipelineRunOutcome string
+
+const (
+	PipelineRunSucceeded PipelineRunOutcome = "Succeeded"
+	PipelineRunFailed    PipelineRunOutcome = "Failed"
+	PipelineRunTimedOut  PipelineRunOutcome = "TimedOut"
+	PipelineRunCancelled PipelineRunOutcome = "Cancelled"
+)
+
+// Reason of a TimedOut result when the PipelineRun didn't finish before the follower gave up waiting
+const PipelineRunFollowerTimeoutReason = "FollowerTimeout"
+
+// PipelineRunResult describes how a followed PipelineRun finished
+type PipelineRunResult struct {
+	Outcome PipelineRunOutcome
+
+	// Last observed state of the PipelineRun
+	PipelineRun *PipelineRunAccessor
+
+	// Reason and message of the PipelineRun Succeeded condition
+	Reason  string
+	Message string
+
+	// Pipeline task, TaskRun and step which failed, along with the step exit code. Set only if a step failed
+	FailedTask    string
+	FailedTaskRun string
+	FailedStep    string
+	ExitCode      int32
+
+	// Path of the file with the logs of all the steps, empty if it couldn't be written
+	LogsFile string
+}
+
+// Err returns nil if the PipelineRun succeeded, or an error describing the outcome
+func (r *PipelineRunResult) Err() error {
+	if r.Outcome == PipelineRunSucceeded {
+		return nil
+	}
+	return errors.New(r.String())
+}
+
+func (r *PipelineRunResult) String() string {
+	s := fmt.Sprintf("PipelineRun %s/%s: %s", r.PipelineRun.Namespace(), r.PipelineRun.Name(), r.Outcome)
+	if r.Reason != "" {
+		s += fmt.Sprintf(" (%s: %s)", r.Reason, r.Message)
+	}
+	if r.FailedStep != "" {
+		s += fmt.Sprintf(", step %s of task %s (TaskRun %s) exited with code %d", r.FailedStep, r.FailedTask, r.FailedTaskRun, r.ExitCode)
+	}
+	if r.LogsFile != "" {
+		s += fmt.Sprintf(", logs: %s", r.LogsFile)
+	}
+	return s
+}
+
+// PipelineRunFollower watches a PipelineRun and its TaskRuns until the PipelineRun finishes, streaming the logs
+// of the step containers as they run
+type PipelineRunFollower struct {
+	Name      string
+	Namespace string
+
+	// Writer the step logs are streamed to, each line prefixed with [<task>/<step>]. GinkgoWriter by default
+	Output io.Writer
+
+	// Directory the logs of all the steps are written to once the PipelineRun finishes. ARTIFACT_DIR by default
+	ArtifactsDir string
+
+	// How long to wait for the step logs to be complete once the PipelineRun finished
+	LogsTimeout time.Duration
+
+	// Version of the Tekton API the PipelineRun and its TaskRuns are watched with. Discovered by Wait if empty
+	APIVersion string
+
+	kube   kubernetes.Interface
+	tekton pipelineclientset.Interface
+
+	mu      sync.Mutex
+	steps   map[string]*stepLog
+	order   []string
+	streams sync.WaitGroup
+}
+
+type stepLog struct {
+	task string
+	step string
+	logs bytes.Buffer
+}
+
+// FollowPipelineRun returns a follower of a PipelineRun. Call Wait to follow it until it finishes
+func (s *SuiteController) FollowPipelineRun(name, namespace string) *PipelineRunFollower {
+	follower := NewPipelineRunFollower(s.KubeInterface(), s.PipelineClient(), name, namespace)
+	// Wait retries the discovery if it failed here
+	follower.APIVersion, _ = s.PipelineAPIVersion()
+	return follower
+}
+
+// NewPipelineRunFollower returns a follower of a PipelineRun which uses given clients
+func NewPipelineRunFollower(kube kubernetes.Interface, tekton pipelineclientset.Interface, name, namespace string) *PipelineRunFollower {
+	wd, _ := os.Getwd()
+	return &PipelineRunFollower{
+		Name:         name,
+		Namespace:    namespace,
+		Output:       g.GinkgoWriter,
+		ArtifactsDir: utils.GetEnv("ARTIFACT_DIR", fmt.Sprintf("%s/tmp", wd)),
+		LogsTimeout:  30 * time.Second,
+		kube:         kube,
+		tekton:       tekton,
+		steps:        map[string]*stepLog{},
+	}
+}
+
+// Wait follows the PipelineRun until it finishes or the timeout expires, and writes the logs of its steps to the
+// artifacts directory. The returned error is only set if the PipelineRun or its TaskRuns can't be retrieved
+func (f *PipelineRunFollower) Wait(timeout time.Duration) (*PipelineRunResult, error) {
+	if f.APIVersion == "" {
+		version, err := DiscoverPipelineAPIVersion(f.kube.Discovery())
+		if err != nil {
+			return nil, err
+		}
+		f.APIVersion = version
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	streamCtx, cancelStreams := context.WithCancel(context.Background())
+	defer cancelStreams()
+
+	pipelineRun, err := f.follow(ctx, streamCtx)
+	if pipelineRun == nil {
+		return nil, err
+	}
+
+	var result *PipelineRunResult
+	if err != nil && errors.Is(err, context.DeadlineExceeded) {
+		result = &PipelineRunResult{
+			Outcome:     PipelineRunTimedOut,
+			PipelineRun: pipelineRun,
+			Reason:      PipelineRunFollowerTimeoutReason,
+			Message:     fmt.Sprintf("PipelineRun didn't finish in %s", timeout),
+		}
+	} else if err != nil {
+		return nil, err
+	} else if result, err = f.result(pipelineRun); err != nil {
+		return nil, err
+	}
+
+	f.waitForStreams(cancelStreams)
+	result.LogsFile = f.storeLogs()
+	return result, nil
+}
+
+// Logs returns the logs streamed so far for each step, keyed by <task>/<step>
+func (f *PipelineRunFollower) Logs() map[string]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	logs := map[string]string{}
+	for _, key := range f.order {
+		step := f.steps[key]
+		logs[fmt.Sprintf("%s/%s", step.task, step.step)] = step.logs.String()
+	}
+	return logs
+}
+
+// follow watches the PipelineRun and its TaskRuns until the PipelineRun is done, re-establishing the watches
+// whenever they get closed by the server. It returns the last observed state of the PipelineRun
+func (f *PipelineRunFollower) follow(ctx, streamCtx context.Context) (*PipelineRunAccessor, error) {
+	var last *PipelineRunAccessor
+	taskRunSelector := metav1.ListOptions{LabelSelector: fmt.Sprintf("tekton.dev/pipelineRun=%s", f.Name)}
+
+	for {
+		pipelineRuns, err := watchPipelineRuns(ctx, f.tekton, f.APIVersion, f.Namespace, metav1.ListOptions{FieldSelector: fmt.Sprintf("metadata.name=%s", f.Name)})
+		if err != nil {
+			return last, f.watchError(ctx, "pipelineruns", err)
+		}
+		taskRuns, err := watchTaskRuns(ctx, f.tekton, f.APIVersion, f.Namespace, taskRunSelector)
+		if err != nil {
+			pipelineRuns.Stop()
+			return last, f.watchError(ctx, "taskruns", err)
+		}
+
+		// The current state is retrieved once the watches are established, so that no change gets missed
+		pipelineRun, err := getPipelineRun(ctx, f.tekton, f.APIVersion, f.Name, f.Namespace)
+		if err != nil {
+			pipelineRuns.Stop()
+			taskRuns.Stop()
+			return last, f.watchError(ctx, "pipelinerun", err)
+		}
+		last = pipelineRun
+		if list, err := listTaskRuns(ctx, f.tekton, f.APIVersion, f.Namespace, taskRunSelector); err == nil {
+			for _, taskRun := range list {
+				f.followTaskRun(streamCtx, taskRun)
+			}
+		}
+
+		last, err = f.waitForEvents(ctx, streamCtx, pipelineRuns.ResultChan(), taskRuns.ResultChan(), last)
+		pipelineRuns.Stop()
+		taskRuns.Stop()
+		if last.IsDone() || err != nil {
+			return last, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return last, ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// waitForEvents handles watch events until the PipelineRun is done or one of the watches gets closed,
+// and returns the last observed state of the PipelineRun
+func (f *PipelineRunFollower) waitForEvents(ctx, streamCtx context.Context, pipelineRuns, taskRuns <-chan watch.Event, last *PipelineRunAccessor) (*PipelineRunAccessor, error) {
+	for !last.IsDone() {
+		select {
+		case <-ctx.Done():
+			return last, ctx.Err()
+		case event, ok := <-pipelineRuns:
+			if !ok {
+				return last, nil
+			}
+			if pipelineRun, err := NewPipelineRunAccessor(event.Object); err == nil && pipelineRun.Name() == f.Name {
+				last = pipelineRun
+			}
+		case event, ok := <-taskRuns:
+			if !ok {
+				return last, nil
+			}
+			if taskRun, err := NewTaskRunAccessor(event.Object); err == nil {
+				f.followTaskRun(streamCtx, taskRun)
+			}
+		}
+	}
+	return last, nil
+}
+
+func (f *PipelineRunFollower) watchError(ctx context.Context, resource string, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return fmt.Errorf("error when watching %s of pipelinerun %s in %s namespace: %v", resource, f.Name, f.Namespace, err)
+}
+
+// followTaskRun starts streaming the logs of the steps of a TaskRun which started running
+func (f *PipelineRunFollower) followTaskRun(ctx context.Context, taskRun *TaskRunAccessor) {
+	if taskRun.Object().GetLabels()["tekton.dev/pipelineRun"] != f.Name || taskRun.PodName() == "" {
+		return
+	}
+	task := taskRun.PipelineTaskName()
+	if task == "" {
+		task = taskRun.Name()
+	}
+	for _, step := range taskRun.Steps() {
+		if step.Running == nil && step.Terminated == nil {
+			continue
+		}
+		key := fmt.Sprintf("%s/%s", taskRun.Name(), step.ContainerName)
+		f.mu.Lock()
+		if _, ok := f.steps[key]; ok {
+			f.mu.Unlock()
+			continue
+		}
+		log := &stepLog{task: task, step: step.Name}
+		f.steps[key] = log
+		f.order = append(f.order, key)
+		f.mu.Unlock()
+
+		f.streams.Add(1)
+		go f.streamStepLogs(ctx, taskRun.PodName(), step.ContainerName, log)
+	}
+}
+
+func (f *PipelineRunFollower) streamStepLogs(ctx context.Context, podName, containerName string, log *stepLog) {
+	defer f.streams.Done()
+	prefix := fmt.Sprintf("[%s/%s] ", log.task, log.step)
+
+	stream, err := f.kube.CoreV1().Pods(f.Namespace).GetLogs(podName, &corev1.PodLogOptions{Container: containerName, Follow: true}).Stream(ctx)
+	if err != nil {
+		f.mu.Lock()
+		fmt.Fprintf(f.Output, "%sfailed to stream logs: %v\n", prefix, err)
+		f.mu.Unlock()
+		return
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		f.mu.Lock()
+		log.logs.WriteString(scanner.Text() + "\n")
+		fmt.Fprintf(f.Output, "%s%s\n", prefix, scanner.Text())
+		f.mu.Unlock()
+	}
+	// The stream is cut e.g. when the step is still running after LogsTimeout, or by the connection to the API server
+	if err := scanner.Err(); err != nil {
+		f.mu.Lock()
+		log.logs.WriteString(fmt.Sprintf("log stream ended early, logs may be incomplete: %v\n", err))
+		fmt.Fprintf(f.Output, "%slog stream ended early, logs may be incomplete: %v\n", prefix, err)
+		f.mu.Unlock()
+	}
+}
+
+// waitForStreams waits for the step logs to be complete, and stops the streams still open after LogsTimeout
+func (f *PipelineRunFollower) waitForStreams(cancel context.CancelFunc) {
+	done := make(chan struct{})
+	go func() {
+		f.streams.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(f.LogsTimeout):
+		cancel()
+		<-done
+	}
+}
+
+// result determines the outcome of a finished PipelineRun
+func (f *PipelineRunFollower) result(pipelineRun *PipelineRunAccessor) (*PipelineRunResult, error) {
+	result := &PipelineRunResult{PipelineRun: pipelineRun, Outcome: PipelineRunFailed}
+	condition := pipelineRun.Condition()
+	if condition != nil {
+		result.Reason, result.Message = condition.Reason, condition.Message
+	}
+
+	switch {
+	case condition.IsTrue():
+		result.Outcome = PipelineRunSucceeded
+		return result, nil
+	case result.Reason == v1beta1.PipelineRunReasonTimedOut.String():
+		result.Outcome = PipelineRunTimedOut
+	case result.Reason == v1beta1.PipelineRunReasonCancelled.String(),
+		result.Reason == v1beta1.PipelineRunReasonCancelledRunningFinally.String(),
+		result.Reason == v1beta1.PipelineRunReasonStoppedRunningFinally.String():
+		result.Outcome = PipelineRunCancelled
+	}
+
+	taskRuns, err := listTaskRuns(context.TODO(), f.tekton, f.APIVersion, f.Namespace, metav1.ListOptions{LabelSelector: fmt.Sprintf("tekton.dev/pipelineRun=%s", f.Name)})
+	if err != nil {
+		return nil, fmt.Errorf("error when listing taskruns of pipelinerun %s in %s namespace: %v", f.Name, f.Namespace, err)
+	}
+	for _, taskRun := range taskRuns {
+		if !taskRun.Condition().IsFalse() {
+			continue
+		}
+		for _, step := range taskRun.Steps() {
+			if step.Terminated != nil && step.Terminated.ExitCode != 0 {
+				result.FailedTask = taskRun.PipelineTaskName()
+				if result.FailedTask == "" {
+					result.FailedTask = taskRun.Name()
+				}
+				result.FailedTaskRun = taskRun.Name()
+				result.FailedStep = step.Name
+				result.ExitCode = step.Terminated.ExitCode
+				return result, nil
+			}
+		}
+	}
+	return result, nil
+}
+
+// storeLogs writes the logs of all the steps to the artifacts directory and returns the path of the file
+func (f *PipelineRunFollower) storeLogs() string {
+	var content bytes.Buffer
+	f.mu.Lock()
+	for _, key := range f.order {
+		step := f.steps[key]
+		fmt.Fprintf(&content, "--- %s/%s ---\n%s\n", step.task, step.step, step.logs.String())
+	}
+	f.mu.Unlock()
+
+	if err := os.MkdirAll(f.ArtifactsDir, os.ModePerm); err != nil {
+		g.GinkgoWriter.Printf("cannot create %s: %+v\n", f.ArtifactsDir, err)
+		return ""
+	}
+	path := filepath.Join(f.ArtifactsDir, fmt.Sprintf("%s-pr-%s-steps.log", f.Namespace, f.Name))
+	if err := os.WriteFile(path, content.Bytes(), 0644); err != nil {
+		g.GinkgoWriter.Printf("cannot write to %s: %+v\n", path, err)
+		return ""
+	}
+	return path
+}
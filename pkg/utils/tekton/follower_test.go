@@ -0,0 +1,188 @@
+package tekton
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	pipelinefake "github.com/tektoncd/pipeline/pkg/client/clientset/versioned/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	duckv1beta1 "knative.dev/pkg/apis/duck/v1beta1"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use by the log streams and the test
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func followerTestPipelineRun(status corev1.ConditionStatus, reason string) *v1beta1.PipelineRun {
+	return &v1beta1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "ns"},
+		Status: v1beta1.PipelineRunStatus{Status: duckv1beta1.Status{Conditions: duckv1beta1.Conditions{
+			{Type: apis.ConditionSucceeded, Status: status, Reason: reason, Message: "message"},
+		}}},
+	}
+}
+
+func followerTestTaskRun(status corev1.ConditionStatus, exitCode int32) *v1beta1.TaskRun {
+	return &v1beta1.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "build-buildah", Namespace: "ns", Labels: map[string]string{
+			"tekton.dev/pipelineRun":  "build",
+			"tekton.dev/pipelineTask": "buildah",
+		}},
+		Status: v1beta1.TaskRunStatus{
+			Status: duckv1beta1.Status{Conditions: duckv1beta1.Conditions{{Type: apis.ConditionSucceeded, Status: status}}},
+			TaskRunStatusFields: v1beta1.TaskRunStatusFields{
+				PodName: "build-buildah-pod",
+				Steps: []v1beta1.StepState{
+					{Name: "build", ContainerName: "step-build", ContainerState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode}}},
+					{Name: "push", ContainerName: "step-push", ContainerState: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{}}},
+				},
+			},
+		},
+	}
+}
+
+func newTestFollower(t *testing.T, tekton *pipelinefake.Clientset) (*PipelineRunFollower, *syncBuffer) {
+	output := &syncBuffer{}
+	follower := NewPipelineRunFollower(kubefake.NewSimpleClientset(), tekton, "build", "ns")
+	follower.Output = output
+	follower.ArtifactsDir = t.TempDir()
+	return follower, output
+}
+
+func TestPipelineRunFollowerFailedStep(t *testing.T) {
+	tekton := pipelinefake.NewSimpleClientset(followerTestPipelineRun(corev1.ConditionUnknown, "Running"))
+	follower, output := newTestFollower(t, tekton)
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		ctx := context.TODO()
+		_, err := tekton.TektonV1beta1().TaskRuns("ns").Create(ctx, followerTestTaskRun(corev1.ConditionFalse, 2), metav1.CreateOptions{})
+		assert.NoError(t, err)
+		time.Sleep(100 * time.Millisecond)
+		_, err = tekton.TektonV1beta1().PipelineRuns("ns").UpdateStatus(ctx, followerTestPipelineRun(corev1.ConditionFalse, "Failed"), metav1.UpdateOptions{})
+		assert.NoError(t, err)
+	}()
+
+	result, err := follower.Wait(10 * time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, PipelineRunFailed, result.Outcome)
+	assert.Equal(t, "buildah", result.FailedTask)
+	assert.Equal(t, "build-buildah", result.FailedTaskRun)
+	assert.Equal(t, "build", result.FailedStep)
+	assert.Equal(t, int32(2), result.ExitCode)
+	assert.Error(t, result.Err())
+	assert.Contains(t, result.Err().Error(), "step build of task buildah (TaskRun build-buildah) exited with code 2")
+
+	// The fake clientset returns "fake logs" for every container. The push step didn't start, so its logs aren't streamed
+	assert.Equal(t, "[buildah/build] fake logs\n", output.String())
+	assert.Equal(t, map[string]string{"buildah/build": "fake logs\n"}, follower.Logs())
+	logs, err := os.ReadFile(result.LogsFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "--- buildah/build ---\nfake logs\n\n", string(logs))
+}
+
+func TestPipelineRunFollowerOutcomes(t *testing.T) {
+	for _, c := range []struct {
+		status  corev1.ConditionStatus
+		reason  string
+		outcome PipelineRunOutcome
+	}{
+		{corev1.ConditionTrue, "Succeeded", PipelineRunSucceeded},
+		{corev1.ConditionFalse, "PipelineRunTimeout", PipelineRunTimedOut},
+		{corev1.ConditionFalse, "Cancelled", PipelineRunCancelled},
+		{corev1.ConditionFalse, "StoppedRunningFinally", PipelineRunCancelled},
+		{corev1.ConditionFalse, "Failed", PipelineRunFailed},
+	} {
+		follower, _ := newTestFollower(t, pipelinefake.NewSimpleClientset(followerTestPipelineRun(c.status, c.reason)))
+		result, err := follower.Wait(10 * time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, c.outcome, result.Outcome, c.reason)
+		assert.Equal(t, c.reason, result.Reason)
+		assert.Equal(t, c.outcome == PipelineRunSucceeded, result.Err() == nil)
+	}
+}
+
+func TestPipelineRunFollowerTimeout(t *testing.T) {
+	follower, _ := newTestFollower(t, pipelinefake.NewSimpleClientset(followerTestPipelineRun(corev1.ConditionUnknown, "Running")))
+	result, err := follower.Wait(time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, PipelineRunTimedOut, result.Outcome)
+	assert.Equal(t, PipelineRunFollowerTimeoutReason, result.Reason)
+
+	follower, _ = newTestFollower(t, pipelinefake.NewSimpleClientset())
+	_, err = follower.Wait(time.Second)
+	assert.Error(t, err)
+}
+
+func TestPipelineRunFollowerV1(t *testing.T) {
+	pipelineRun := &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "ns"},
+		Status: tektonv1.PipelineRunStatus{Status: duckv1beta1.Status{Conditions: duckv1beta1.Conditions{
+			{Type: apis.ConditionSucceeded, Status: corev1.ConditionFalse, Reason: "Failed"},
+		}}},
+	}
+	taskRun := tektonv1.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "build-buildah", Namespace: "ns", Labels: map[string]string{
+			"tekton.dev/pipelineRun":  "build",
+			"tekton.dev/pipelineTask": "buildah",
+		}},
+		Status: tektonv1.TaskRunStatus{
+			Status: duckv1.Status{Conditions: duckv1.Conditions{{Type: apis.ConditionSucceeded, Status: corev1.ConditionFalse}}},
+			TaskRunStatusFields: tektonv1.TaskRunStatusFields{
+				PodName: "build-buildah-pod",
+				Steps: []tektonv1.StepState{
+					{Name: "build", Container: "step-build", ContainerState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}}},
+				},
+			},
+		},
+	}
+	// The v1 PipelineRun and TaskRun types aren't registered in the scheme of the fake clientset, so they are served by reactors
+	tekton := pipelinefake.NewSimpleClientset()
+	tekton.PrependWatchReactor("*", func(k8stesting.Action) (bool, watch.Interface, error) {
+		return true, watch.NewFake(), nil
+	})
+	tekton.PrependReactor("get", "pipelineruns", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, pipelineRun, nil
+	})
+	tekton.PrependReactor("list", "taskruns", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, &tektonv1.TaskRunList{Items: []tektonv1.TaskRun{taskRun}}, nil
+	})
+	follower, _ := newTestFollower(t, tekton)
+	follower.APIVersion = TektonV1
+
+	result, err := follower.Wait(10 * time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, PipelineRunFailed, result.Outcome)
+	assert.Equal(t, TektonV1, result.PipelineRun.APIVersion())
+	assert.Equal(t, "build", result.FailedStep)
+	assert.Equal(t, int32(1), result.ExitCode)
+	assert.Equal(t, map[string]string{"buildah/build": "fake logs\n"}, follower.Logs())
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/onsi/gomega"
 	"github.com/onsi/gomega/types"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"k8s.io/client-go/util/jsonpath"
 	"knative.dev/pkg/apis"
@@ -33,7 +34,7 @@ func (matcher *TaskRunResultMatcher) FailureMessage(actual interface{}) (message
 }
 
 func (matcher *TaskRunResultMatcher) Match(actual interface{}) (success bool, err error) {
-	if tr, ok := actual.(v1beta1.TaskRunResult); !ok {
+	if tr, ok := asV1beta1TaskRunResult(actual); !ok {
 		return false, fmt.Errorf("not given TaskRunResult")
 	} else {
 		if tr.Name != matcher.name {
@@ -118,12 +119,36 @@ func MatchTaskRunResultWithJSONPathValue(name, path string, json interface{}) ty
 	return &TaskRunResultMatcher{name: name, jsonPath: &path, jsonValue: &json}
 }
 
+// asV1beta1TaskRunResult returns a v1beta1 or v1 TaskRunResult as a v1beta1 one
+func asV1beta1TaskRunResult(actual interface{}) (v1beta1.TaskRunResult, bool) {
+	switch tr := actual.(type) {
+	case v1beta1.TaskRunResult:
+		return tr, true
+	case tektonv1.TaskRunResult:
+		return v1beta1.TaskRunResult{
+			Name: tr.Name,
+			Type: v1beta1.ResultsType(tr.Type),
+			Value: v1beta1.ParamValue{
+				Type:      v1beta1.ParamType(tr.Value.Type),
+				StringVal: tr.Value.StringVal,
+				ArrayVal:  tr.Value.ArrayVal,
+				ObjectVal: tr.Value.ObjectVal,
+			},
+		}, true
+	}
+	return v1beta1.TaskRunResult{}, false
+}
+
 func DidTaskSucceed(tr interface{}) bool {
 	switch tr := tr.(type) {
 	case *v1beta1.PipelineRunTaskRunStatus:
 		return tr.Status.GetCondition(apis.ConditionSucceeded).IsTrue()
 	case *v1beta1.TaskRunStatus:
 		return tr.Status.GetCondition(apis.ConditionSucceeded).IsTrue()
+	case *tektonv1.TaskRunStatus:
+		return tr.Status.GetCondition(apis.ConditionSucceeded).IsTrue()
+	case *TaskRunAccessor:
+		return tr.Succeeded()
 	}
 	return false
 }
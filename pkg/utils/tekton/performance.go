@@ -0,0 +1,234 @@
+package tekton
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	g "github.com/onsi/ginkgo/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PipelineRunPerformance is the breakdown of where the time of a finished PipelineRun went
+type PipelineRunPerformance struct {
+	PipelineRun string        `json:"pipelineRun"`
+	Namespace   string        `json:"namespace"`
+	Created     time.Time     `json:"created"`
+	Completed   time.Time     `json:"completed"`
+	Duration    time.Duration `json:"duration"`
+
+	// Tasks ordered by the creation of their TaskRun
+	Tasks []TaskRunPerformance `json:"tasks"`
+}
+
+// TaskRunPerformance is the breakdown of the time of a TaskRun of a PipelineRun
+type TaskRunPerformance struct {
+	PipelineTask string `json:"pipelineTask"`
+	TaskRun      string `json:"taskRun"`
+
+	Created   time.Time `json:"created"`
+	Completed time.Time `json:"completed"`
+
+	// Time from the TaskRun creation until its pod got scheduled
+	QueueTime time.Duration `json:"queueTime"`
+
+	// Time from the pod being scheduled until all of its step containers started, which is dominated by pulling the images
+	ImagePullTime time.Duration `json:"imagePullTime"`
+
+	// Time from the TaskRun creation until its completion
+	Duration time.Duration `json:"duration"`
+
+	// Time between the completion of the last task this task depends on (or the PipelineRun creation) and the TaskRun creation
+	IdleGap time.Duration `json:"idleGap"`
+
+	Steps []StepPerformance `json:"steps"`
+}
+
+// StepPerformance is the time a step of a TaskRun ran for
+type StepPerformance struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+}
+
+// GetPipelineRunPerformance analyzes the performance of a finished PipelineRun, along with its TaskRuns and their pods
+func (s *SuiteController) GetPipelineRunPerformance(pipelineRunName, namespace string) (*PipelineRunPerformance, error) {
+	pipelineRun, err := s.GetPipelineRunAccessor(pipelineRunName, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("error when getting pipelinerun %s in %s namespace: %v", pipelineRunName, namespace, err)
+	}
+	taskRuns, err := s.ListTaskRunAccessors(namespace, map[string]string{"tekton.dev/pipelineRun": pipelineRunName})
+	if err != nil {
+		return nil, fmt.Errorf("error when listing taskruns of pipelinerun %s in %s namespace: %v", pipelineRunName, namespace, err)
+	}
+	var pods []corev1.Pod
+	for _, taskRun := range taskRuns {
+		if taskRun.PodName() == "" {
+			continue
+		}
+		pod, err := s.KubeInterface().CoreV1().Pods(namespace).Get(context.TODO(), taskRun.PodName(), metav1.GetOptions{})
+		if err != nil {
+			// Pods of finished TaskRuns might be pruned already, their queue and image pull time are left out
+			continue
+		}
+		pods = append(pods, *pod)
+	}
+	return AnalyzePipelineRunPerformance(pipelineRun, taskRuns, pods)
+}
+
+// AnalyzePipelineRunPerformance computes the performance breakdown of a finished PipelineRun from the status of its
+// TaskRuns and the conditions of their pods. Missing pods are ignored
+func AnalyzePipelineRunPerformance(pipelineRun *PipelineRunAccessor, taskRuns []*TaskRunAccessor, pods []corev1.Pod) (*PipelineRunPerformance, error) {
+	if !pipelineRun.IsDone() || pipelineRun.CompletionTime() == nil {
+		return nil, fmt.Errorf("pipelinerun %s in %s namespace has not finished yet", pipelineRun.Name(), pipelineRun.Namespace())
+	}
+	p := &PipelineRunPerformance{
+		PipelineRun: pipelineRun.Name(),
+		Namespace:   pipelineRun.Namespace(),
+		Created:     pipelineRun.Object().GetCreationTimestamp().Time,
+		Completed:   pipelineRun.CompletionTime().Time,
+	}
+	p.Duration = p.Completed.Sub(p.Created)
+
+	podsByName := map[string]*corev1.Pod{}
+	for i := range pods {
+		podsByName[pods[i].Name] = &pods[i]
+	}
+
+	completed := map[string]time.Time{}
+	for _, taskRun := range taskRuns {
+		task := analyzeTaskRun(taskRun, podsByName[taskRun.PodName()])
+		completed[task.PipelineTask] = task.Completed
+		p.Tasks = append(p.Tasks, task)
+	}
+	sort.SliceStable(p.Tasks, func(i, j int) bool { return p.Tasks[i].Created.Before(p.Tasks[j].Created) })
+
+	deps := pipelineRun.PipelineTaskDeps()
+	for i := range p.Tasks {
+		ready := p.Created
+		for _, dep := range deps[p.Tasks[i].PipelineTask] {
+			if c, ok := completed[dep]; ok && c.After(ready) {
+				ready = c
+			}
+		}
+		p.Tasks[i].IdleGap = nonNegative(p.Tasks[i].Created.Sub(ready))
+	}
+	return p, nil
+}
+
+func analyzeTaskRun(taskRun *TaskRunAccessor, pod *corev1.Pod) TaskRunPerformance {
+	task := TaskRunPerformance{
+		PipelineTask: taskRun.PipelineTaskName(),
+		TaskRun:      taskRun.Name(),
+		Created:      taskRun.Object().GetCreationTimestamp().Time,
+	}
+	if task.PipelineTask == "" {
+		task.PipelineTask = taskRun.Name()
+	}
+	if taskRun.CompletionTime() != nil {
+		task.Completed = taskRun.CompletionTime().Time
+		task.Duration = task.Completed.Sub(task.Created)
+	}
+
+	// Steps run one after another once all the step containers started
+	steps := taskRun.Steps()
+	var containersStarted time.Time
+	for _, step := range steps {
+		if step.Terminated != nil && step.Terminated.StartedAt.After(containersStarted) {
+			containersStarted = step.Terminated.StartedAt.Time
+		}
+	}
+	previous := containersStarted
+	for _, step := range steps {
+		if step.Terminated == nil {
+			task.Steps = append(task.Steps, StepPerformance{Name: step.Name})
+			continue
+		}
+		finished := step.Terminated.FinishedAt.Time
+		task.Steps = append(task.Steps, StepPerformance{Name: step.Name, Duration: nonNegative(finished.Sub(previous))})
+		if finished.After(previous) {
+			previous = finished
+		}
+	}
+
+	if pod != nil {
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionTrue {
+				scheduled := condition.LastTransitionTime.Time
+				task.QueueTime = nonNegative(scheduled.Sub(task.Created))
+				if !containersStarted.IsZero() {
+					task.ImagePullTime = nonNegative(containersStarted.Sub(scheduled))
+				}
+			}
+		}
+	}
+	return task
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// String formats the breakdown as a table, which is also how it's displayed as a Ginkgo report entry
+func (p *PipelineRunPerformance) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "PipelineRun %s/%s finished in %s\n", p.Namespace, p.PipelineRun, p.Duration.Round(time.Second))
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TASK\tSTART\tIDLE\tQUEUE\tIMAGE PULL\tDURATION\tSTEPS")
+	for _, task := range p.Tasks {
+		var steps []string
+		for _, step := range task.Steps {
+			steps = append(steps, fmt.Sprintf("%s=%s", step.Name, step.Duration.Round(time.Second)))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", task.PipelineTask, task.Created.Sub(p.Created).Round(time.Second),
+			task.IdleGap.Round(time.Second), task.QueueTime.Round(time.Second), task.ImagePullTime.Round(time.Second),
+			task.Duration.Round(time.Second), strings.Join(steps, " "))
+	}
+	_ = w.Flush()
+	return buf.String()
+}
+
+// CSV formats the breakdown as CSV, with one row per step
+func (p *PipelineRunPerformance) CSV() string {
+	var buf bytes.Buffer
+	_ = WritePipelineRunPerformanceCSV(&buf, p)
+	return buf.String()
+}
+
+// AddToReport attaches the breakdown to the report of the running Ginkgo spec. The JSON report contains all the fields
+func (p *PipelineRunPerformance) AddToReport() {
+	g.AddReportEntry(fmt.Sprintf("pipelinerun %s performance", p.PipelineRun), p)
+}
+
+// WritePipelineRunPerformanceCSV writes breakdowns of PipelineRuns as CSV with a header, one row per step. Durations are in seconds
+func WritePipelineRunPerformanceCSV(out io.Writer, performances ...*PipelineRunPerformance) error {
+	w := csv.NewWriter(out)
+	records := [][]string{{"namespace", "pipelinerun", "pipelinerun_duration", "task", "taskrun", "task_start", "idle_gap", "queue_time", "image_pull_time", "task_duration", "step", "step_duration"}}
+	seconds := func(d time.Duration) string { return fmt.Sprintf("%.3f", d.Seconds()) }
+	for _, p := range performances {
+		for _, task := range p.Tasks {
+			steps := task.Steps
+			if len(steps) == 0 {
+				steps = []StepPerformance{{}}
+			}
+			for _, step := range steps {
+				records = append(records, []string{p.Namespace, p.PipelineRun, seconds(p.Duration), task.PipelineTask, task.TaskRun,
+					seconds(task.Created.Sub(p.Created)), seconds(task.IdleGap), seconds(task.QueueTime), seconds(task.ImagePullTime),
+					seconds(task.Duration), step.Name, seconds(step.Duration)})
+			}
+		}
+	}
+	if err := w.WriteAll(records); err != nil {
+		return fmt.Errorf("error when writing pipelinerun performance CSV: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package tekton
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1beta1 "knative.dev/pkg/apis/duck/v1beta1"
+)
+
+var performanceTestStart = time.Date(2023, 4, 1, 10, 0, 0, 0, time.UTC)
+
+func at(seconds int) metav1.Time {
+	return metav1.NewTime(performanceTestStart.Add(time.Duration(seconds) * time.Second))
+}
+
+func performanceTestTaskRun(task string, created, completed int, steps ...v1beta1.StepState) *TaskRunAccessor {
+	completionTime := at(completed)
+	return &TaskRunAccessor{v1beta1: &v1beta1.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "build-" + task, CreationTimestamp: at(created), Labels: map[string]string{"tekton.dev/pipelineTask": task}},
+		Status: v1beta1.TaskRunStatus{TaskRunStatusFields: v1beta1.TaskRunStatusFields{
+			PodName:        "build-" + task + "-pod",
+			CompletionTime: &completionTime,
+			Steps:          steps,
+		}},
+	}}
+}
+
+func performanceTestStep(name string, started, finished int) v1beta1.StepState {
+	return v1beta1.StepState{Name: name, ContainerState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{StartedAt: at(started), FinishedAt: at(finished)}}}
+}
+
+func performanceTestPod(task string, scheduled int) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "build-" + task + "-pod"},
+		Status: corev1.PodStatus{Conditions: []corev1.PodCondition{
+			{Type: corev1.PodScheduled, Status: corev1.ConditionTrue, LastTransitionTime: at(scheduled)},
+		}},
+	}
+}
+
+func performanceTestPipelineRun() *v1beta1.PipelineRun {
+	completionTime := at(100)
+	return &v1beta1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "ns", CreationTimestamp: at(0)},
+		Status: v1beta1.PipelineRunStatus{
+			Status: duckv1beta1.Status{Conditions: duckv1beta1.Conditions{{Type: apis.ConditionSucceeded, Status: corev1.ConditionTrue}}},
+			PipelineRunStatusFields: v1beta1.PipelineRunStatusFields{
+				CompletionTime: &completionTime,
+				PipelineSpec: &v1beta1.PipelineSpec{
+					Tasks: []v1beta1.PipelineTask{
+						{Name: "clone"},
+						{Name: "build", Params: []v1beta1.Param{{Name: "url", Value: *v1beta1.NewArrayOrString("$(tasks.clone.results.url)")}}},
+					},
+					Finally: []v1beta1.PipelineTask{{Name: "summary"}},
+				},
+			},
+		},
+	}
+}
+
+func TestAnalyzePipelineRunPerformance(t *testing.T) {
+	taskRuns := []*TaskRunAccessor{
+		performanceTestTaskRun("build", 25, 80, performanceTestStep("build", 40, 70), performanceTestStep("push", 41, 80)),
+		performanceTestTaskRun("clone", 2, 20, performanceTestStep("clone", 10, 20)),
+		performanceTestTaskRun("summary", 85, 95, performanceTestStep("summary", 90, 95)),
+	}
+	// The pod of the summary task got pruned
+	pods := []corev1.Pod{performanceTestPod("clone", 4), performanceTestPod("build", 30)}
+
+	p, err := AnalyzePipelineRunPerformance(&PipelineRunAccessor{v1beta1: performanceTestPipelineRun()}, taskRuns, pods)
+	assert.NoError(t, err)
+	assert.Equal(t, 100*time.Second, p.Duration)
+	assert.Len(t, p.Tasks, 3)
+
+	clone, build, summary := p.Tasks[0], p.Tasks[1], p.Tasks[2]
+	assert.Equal(t, "clone", clone.PipelineTask)
+	assert.Equal(t, 2*time.Second, clone.IdleGap)
+	assert.Equal(t, 2*time.Second, clone.QueueTime)
+	assert.Equal(t, 6*time.Second, clone.ImagePullTime)
+	assert.Equal(t, 18*time.Second, clone.Duration)
+
+	assert.Equal(t, "build", build.PipelineTask)
+	assert.Equal(t, 5*time.Second, build.IdleGap)
+	assert.Equal(t, 5*time.Second, build.QueueTime)
+	assert.Equal(t, 11*time.Second, build.ImagePullTime)
+	assert.Equal(t, []StepPerformance{{Name: "build", Duration: 29 * time.Second}, {Name: "push", Duration: 10 * time.Second}}, build.Steps)
+
+	assert.Equal(t, "summary", summary.PipelineTask)
+	assert.Equal(t, 5*time.Second, summary.IdleGap)
+	assert.Zero(t, summary.QueueTime)
+	assert.Zero(t, summary.ImagePullTime)
+
+	table := p.String()
+	assert.Contains(t, table, "PipelineRun ns/build finished in 1m40s")
+	assert.Contains(t, table, "build=29s push=10s")
+
+	csv := strings.Split(strings.TrimSpace(p.CSV()), "\n")
+	assert.Len(t, csv, 5)
+	assert.Equal(t, "namespace,pipelinerun,pipelinerun_duration,task,taskrun,task_start,idle_gap,queue_time,image_pull_time,task_duration,step,step_duration", csv[0])
+	assert.Equal(t, "ns,build,100.000,build,build-build,25.000,5.000,5.000,11.000,55.000,push,10.000", csv[3])
+
+	data, err := json.Marshal(p)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"pipelineTask":"clone"`)
+}
+
+func TestAnalyzeRunningPipelineRun(t *testing.T) {
+	pipelineRun := performanceTestPipelineRun()
+	pipelineRun.Status.Conditions[0].Status = corev1.ConditionUnknown
+	_, err := AnalyzePipelineRunPerformance(&PipelineRunAccessor{v1beta1: pipelineRun}, nil, nil)
+	assert.Error(t, err)
+}
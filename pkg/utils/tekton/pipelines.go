@@ -170,9 +170,18 @@ func GetFailedPipelineRunLogs(c *common.SuiteController, pipelineRun *v1beta1.Pi
 // StorePipelineRunLogs stores logs and parsed yamls of pipelineRuns into directory of given testName under ARTIFACT_DIR env.
 // In case the files can't be stored in ARTIFACT_DIR, they will be recorder in GinkgoWriter.
 func StorePipelineRun(pipelineRun *v1beta1.PipelineRun, testName string, suiteController *common.SuiteController) error {
+	return StoreClusterPipelineRun(pipelineRun, testName, "", suiteController)
+}
+
+// StoreClusterPipelineRun works as StorePipelineRun, but stores the files into a subdirectory named after the cluster
+// the pipelineRun is running on, so artifacts from multiple clusters don't overwrite each other.
+func StoreClusterPipelineRun(pipelineRun *v1beta1.PipelineRun, testName, clusterName string, suiteController *common.SuiteController) error {
 	wd, _ := os.Getwd()
 	artifactDir := utils.GetEnv("ARTIFACT_DIR", fmt.Sprintf("%s/tmp", wd))
 	testLogsDir := fmt.Sprintf("%s/%s", artifactDir, testName)
+	if clusterName != "" {
+		testLogsDir = fmt.Sprintf("%s/%s", testLogsDir, clusterName)
+	}
 
 	pipelineRunLog := GetFailedPipelineRunLogs(suiteController, pipelineRun)
 
@@ -0,0 +1,574 @@
+package tekton
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	pipelineclientset "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	"knative.dev/pkg/apis"
+)
+
+// Versions of the tekton.dev API group
+const (
+	TektonV1beta1 = "v1beta1"
+	TektonV1      = "v1"
+)
+
+// pipelineAPIVersion holds the Tekton API version discovered by a SuiteController. It's kept behind a pointer, so that
+// copies of the controller share it
+type pipelineAPIVersion struct {
+	mu      sync.Mutex
+	version string
+}
+
+// PipelineAPIVersion returns the version PipelineRuns and TaskRuns are read with: v1 if the cluster serves it, v1beta1 otherwise.
+// The version is discovered once per controller created by NewSuiteController
+func (s *SuiteController) PipelineAPIVersion() (string, error) {
+	if s.apiVersion == nil {
+		return DiscoverPipelineAPIVersion(s.KubeInterface().Discovery())
+	}
+	s.apiVersion.mu.Lock()
+	defer s.apiVersion.mu.Unlock()
+	if s.apiVersion.version != "" {
+		return s.apiVersion.version, nil
+	}
+	version, err := DiscoverPipelineAPIVersion(s.KubeInterface().Discovery())
+	if err != nil {
+		return "", err
+	}
+	s.apiVersion.version = version
+	return version, nil
+}
+
+// DiscoverPipelineAPIVersion returns v1 if PipelineRuns are served as tekton.dev/v1, v1beta1 otherwise
+func DiscoverPipelineAPIVersion(client discovery.DiscoveryInterface) (string, error) {
+	resources, err := client.ServerResourcesForGroupVersion(tektonv1.SchemeGroupVersion.String())
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return TektonV1beta1, nil
+		}
+		return "", fmt.Errorf("error when discovering the tekton API version: %v", err)
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Name == "pipelineruns" {
+			return TektonV1, nil
+		}
+	}
+	return TektonV1beta1, nil
+}
+
+// GetPipelineRunAccessor gets a PipelineRun using the Tekton API version served by the cluster
+func (s *SuiteController) GetPipelineRunAccessor(name, namespace string) (*PipelineRunAccessor, error) {
+	version, err := s.PipelineAPIVersion()
+	if err != nil {
+		return nil, err
+	}
+	return getPipelineRun(context.TODO(), s.PipelineClient(), version, name, namespace)
+}
+
+// GetTaskRunAccessor gets a TaskRun using the Tekton API version served by the cluster
+func (s *SuiteController) GetTaskRunAccessor(name, namespace string) (*TaskRunAccessor, error) {
+	version, err := s.PipelineAPIVersion()
+	if err != nil {
+		return nil, err
+	}
+	if version == TektonV1 {
+		taskRun, err := s.PipelineClient().TektonV1().TaskRuns(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &TaskRunAccessor{v1: taskRun}, nil
+	}
+	taskRun, err := s.PipelineClient().TektonV1beta1().TaskRuns(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return &TaskRunAccessor{v1beta1: taskRun}, nil
+}
+
+// ListPipelineRunAccessors lists the PipelineRuns with given labels using the Tekton API version served by the cluster
+func (s *SuiteController) ListPipelineRunAccessors(namespace string, matchLabels map[string]string) ([]*PipelineRunAccessor, error) {
+	version, err := s.PipelineAPIVersion()
+	if err != nil {
+		return nil, err
+	}
+	return listPipelineRuns(context.TODO(), s.PipelineClient(), version, namespace, metav1.ListOptions{LabelSelector: labels.Set(matchLabels).String()})
+}
+
+// ListTaskRunAccessors lists the TaskRuns with given labels using the Tekton API version served by the cluster
+func (s *SuiteController) ListTaskRunAccessors(namespace string, matchLabels map[string]string) ([]*TaskRunAccessor, error) {
+	version, err := s.PipelineAPIVersion()
+	if err != nil {
+		return nil, err
+	}
+	return listTaskRuns(context.TODO(), s.PipelineClient(), version, namespace, metav1.ListOptions{LabelSelector: labels.Set(matchLabels).String()})
+}
+
+func getPipelineRun(ctx context.Context, client pipelineclientset.Interface, version, name, namespace string) (*PipelineRunAccessor, error) {
+	if version == TektonV1 {
+		pipelineRun, err := client.TektonV1().PipelineRuns(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &PipelineRunAccessor{v1: pipelineRun}, nil
+	}
+	pipelineRun, err := client.TektonV1beta1().PipelineRuns(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return &PipelineRunAccessor{v1beta1: pipelineRun}, nil
+}
+
+func listPipelineRuns(ctx context.Context, client pipelineclientset.Interface, version, namespace string, opts metav1.ListOptions) ([]*PipelineRunAccessor, error) {
+	var pipelineRuns []*PipelineRunAccessor
+	if version == TektonV1 {
+		list, err := client.TektonV1().PipelineRuns(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			pipelineRuns = append(pipelineRuns, &PipelineRunAccessor{v1: &list.Items[i]})
+		}
+		return pipelineRuns, nil
+	}
+	list, err := client.TektonV1beta1().PipelineRuns(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list.Items {
+		pipelineRuns = append(pipelineRuns, &PipelineRunAccessor{v1beta1: &list.Items[i]})
+	}
+	return pipelineRuns, nil
+}
+
+func listTaskRuns(ctx context.Context, client pipelineclientset.Interface, version, namespace string, opts metav1.ListOptions) ([]*TaskRunAccessor, error) {
+	var taskRuns []*TaskRunAccessor
+	if version == TektonV1 {
+		list, err := client.TektonV1().TaskRuns(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			taskRuns = append(taskRuns, &TaskRunAccessor{v1: &list.Items[i]})
+		}
+		return taskRuns, nil
+	}
+	list, err := client.TektonV1beta1().TaskRuns(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list.Items {
+		taskRuns = append(taskRuns, &TaskRunAccessor{v1beta1: &list.Items[i]})
+	}
+	return taskRuns, nil
+}
+
+func watchPipelineRuns(ctx context.Context, client pipelineclientset.Interface, version, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	if version == TektonV1 {
+		return client.TektonV1().PipelineRuns(namespace).Watch(ctx, opts)
+	}
+	return client.TektonV1beta1().PipelineRuns(namespace).Watch(ctx, opts)
+}
+
+func watchTaskRuns(ctx context.Context, client pipelineclientset.Interface, version, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	if version == TektonV1 {
+		return client.TektonV1().TaskRuns(namespace).Watch(ctx, opts)
+	}
+	return client.TektonV1beta1().TaskRuns(namespace).Watch(ctx, opts)
+}
+
+// GetPipelineTaskRun gets the TaskRun of a pipeline task of a PipelineRun
+func (s *SuiteController) GetPipelineTaskRun(pipelineRun *PipelineRunAccessor, pipelineTaskName string) (*TaskRunAccessor, error) {
+	for _, child := range pipelineRun.ChildReferences() {
+		if child.PipelineTaskName == pipelineTaskName && child.Kind == "TaskRun" {
+			return s.GetTaskRunAccessor(child.Name, pipelineRun.Namespace())
+		}
+	}
+	return nil, fmt.Errorf("task %s doesn't exist in pipelinerun %s/%s", pipelineTaskName, pipelineRun.Namespace(), pipelineRun.Name())
+}
+
+// GetPipelineTaskRunResult gets the value of a result of the TaskRun of a pipeline task of a PipelineRun
+func (s *SuiteController) GetPipelineTaskRunResult(pipelineRun *PipelineRunAccessor, pipelineTaskName, result string) (string, error) {
+	taskRun, err := s.GetPipelineTaskRun(pipelineRun, pipelineTaskName)
+	if err != nil {
+		return "", err
+	}
+	value, ok := taskRun.Results()[result]
+	if !ok {
+		return "", fmt.Errorf("result %q not found in TaskRun %s of pipelinerun %s/%s", result, taskRun.Name(), pipelineRun.Namespace(), pipelineRun.Name())
+	}
+	return value, nil
+}
+
+// GetFailedPipelineRunAccessorLogs works as GetFailedPipelineRunLogs for v1beta1 and v1 PipelineRuns. The failed
+// step is looked up in the TaskRuns of the PipelineRun, which are read from the cluster
+func (s *SuiteController) GetFailedPipelineRunAccessorLogs(pipelineRun *PipelineRunAccessor) string {
+	failMessage := fmt.Sprintf("Pipelinerun '%s' didn't succeed\n", pipelineRun.Name())
+	for _, child := range pipelineRun.ChildReferences() {
+		if child.Kind != "TaskRun" {
+			continue
+		}
+		taskRun, err := s.GetTaskRunAccessor(child.Name, pipelineRun.Namespace())
+		if err != nil || !taskRun.Condition().IsFalse() {
+			continue
+		}
+		for _, step := range taskRun.Steps() {
+			if step.Terminated != nil && step.Terminated.ExitCode != 0 {
+				logs, _ := s.fetchContainerLog(taskRun.PodName(), step.ContainerName, pipelineRun.Namespace())
+				return failMessage + fmt.Sprintf("Logs from failed container '%s': \n%s", step.ContainerName, logs)
+			}
+		}
+	}
+	return failMessage
+}
+
+// ChildReference is a TaskRun or Run created by a PipelineRun
+type ChildReference struct {
+	Kind             string
+	Name             string
+	PipelineTaskName string
+}
+
+// PipelineRunAccessor gives access to the fields of a tekton.dev/v1beta1 or tekton.dev/v1 PipelineRun which
+// differ between the two versions
+type PipelineRunAccessor struct {
+	v1beta1 *v1beta1.PipelineRun
+	v1      *tektonv1.PipelineRun
+}
+
+// NewPipelineRunAccessor wraps a v1beta1 or v1 PipelineRun
+func NewPipelineRunAccessor(pipelineRun interface{}) (*PipelineRunAccessor, error) {
+	switch pr := pipelineRun.(type) {
+	case *v1beta1.PipelineRun:
+		return &PipelineRunAccessor{v1beta1: pr}, nil
+	case v1beta1.PipelineRun:
+		return &PipelineRunAccessor{v1beta1: &pr}, nil
+	case *tektonv1.PipelineRun:
+		return &PipelineRunAccessor{v1: pr}, nil
+	case tektonv1.PipelineRun:
+		return &PipelineRunAccessor{v1: &pr}, nil
+	}
+	return nil, fmt.Errorf("expected a v1beta1 or v1 PipelineRun, got %T", pipelineRun)
+}
+
+// APIVersion returns the version of the wrapped PipelineRun
+func (p *PipelineRunAccessor) APIVersion() string {
+	if p.v1 != nil {
+		return TektonV1
+	}
+	return TektonV1beta1
+}
+
+// V1beta1 returns the wrapped PipelineRun if it's a v1beta1 one, nil otherwise
+func (p *PipelineRunAccessor) V1beta1() *v1beta1.PipelineRun {
+	return p.v1beta1
+}
+
+// V1 returns the wrapped PipelineRun if it's a v1 one, nil otherwise
+func (p *PipelineRunAccessor) V1() *tektonv1.PipelineRun {
+	return p.v1
+}
+
+// Object returns the metadata of the PipelineRun
+func (p *PipelineRunAccessor) Object() metav1.Object {
+	if p.v1 != nil {
+		return p.v1
+	}
+	return p.v1beta1
+}
+
+func (p *PipelineRunAccessor) Name() string {
+	return p.Object().GetName()
+}
+
+func (p *PipelineRunAccessor) Namespace() string {
+	return p.Object().GetNamespace()
+}
+
+// Condition returns the Succeeded condition of the PipelineRun, or nil if it's not set yet
+func (p *PipelineRunAccessor) Condition() *apis.Condition {
+	if p.v1 != nil {
+		return p.v1.Status.GetCondition(apis.ConditionSucceeded)
+	}
+	return p.v1beta1.Status.GetCondition(apis.ConditionSucceeded)
+}
+
+func (p *PipelineRunAccessor) HasStarted() bool {
+	if p.v1 != nil {
+		return p.v1.HasStarted()
+	}
+	return p.v1beta1.HasStarted()
+}
+
+func (p *PipelineRunAccessor) IsDone() bool {
+	if p.v1 != nil {
+		return p.v1.IsDone()
+	}
+	return p.v1beta1.IsDone()
+}
+
+// Succeeded returns true if the PipelineRun finished successfully
+func (p *PipelineRunAccessor) Succeeded() bool {
+	return p.Condition().IsTrue()
+}
+
+func (p *PipelineRunAccessor) StartTime() *metav1.Time {
+	if p.v1 != nil {
+		return p.v1.Status.StartTime
+	}
+	return p.v1beta1.Status.StartTime
+}
+
+func (p *PipelineRunAccessor) CompletionTime() *metav1.Time {
+	if p.v1 != nil {
+		return p.v1.Status.CompletionTime
+	}
+	return p.v1beta1.Status.CompletionTime
+}
+
+// PipelineTaskDeps returns the tasks each pipeline task depends on, through runAfter or result references.
+// Finally tasks depend on all the other tasks
+func (p *PipelineRunAccessor) PipelineTaskDeps() map[string][]string {
+	deps := map[string][]string{}
+	var tasks, finally []string
+	if p.v1 != nil {
+		if p.v1.Status.PipelineSpec == nil {
+			return deps
+		}
+		for _, task := range p.v1.Status.PipelineSpec.Tasks {
+			deps[task.Name] = task.Deps()
+			tasks = append(tasks, task.Name)
+		}
+		for _, task := range p.v1.Status.PipelineSpec.Finally {
+			finally = append(finally, task.Name)
+		}
+	} else {
+		if p.v1beta1.Status.PipelineSpec == nil {
+			return deps
+		}
+		for _, task := range p.v1beta1.Status.PipelineSpec.Tasks {
+			deps[task.Name] = task.Deps()
+			tasks = append(tasks, task.Name)
+		}
+		for _, task := range p.v1beta1.Status.PipelineSpec.Finally {
+			finally = append(finally, task.Name)
+		}
+	}
+	for _, task := range finally {
+		deps[task] = append(deps[task], tasks...)
+	}
+	return deps
+}
+
+// Params returns the values of the PipelineRun params. Array and object values are JSON encoded
+func (p *PipelineRunAccessor) Params() map[string]string {
+	params := map[string]string{}
+	if p.v1 != nil {
+		for _, param := range p.v1.Spec.Params {
+			params[param.Name] = v1ValueString(param.Value)
+		}
+		return params
+	}
+	for _, param := range p.v1beta1.Spec.Params {
+		params[param.Name] = v1beta1ValueString(param.Value)
+	}
+	return params
+}
+
+// Results returns the values of the PipelineRun results. Array and object values are JSON encoded
+func (p *PipelineRunAccessor) Results() map[string]string {
+	results := map[string]string{}
+	if p.v1 != nil {
+		for _, result := range p.v1.Status.Results {
+			results[result.Name] = v1ValueString(result.Value)
+		}
+		return results
+	}
+	for _, result := range p.v1beta1.Status.PipelineResults {
+		results[result.Name] = v1beta1ValueString(result.Value)
+	}
+	return results
+}
+
+// ChildReferences returns the TaskRuns and Runs of the PipelineRun. For v1beta1 PipelineRuns with the full
+// embedded status, they are taken from the embedded TaskRun and Run statuses
+func (p *PipelineRunAccessor) ChildReferences() []ChildReference {
+	var children []ChildReference
+	if p.v1 != nil {
+		for _, child := range p.v1.Status.ChildReferences {
+			children = append(children, ChildReference{Kind: child.Kind, Name: child.Name, PipelineTaskName: child.PipelineTaskName})
+		}
+		return children
+	}
+	for _, child := range p.v1beta1.Status.ChildReferences {
+		children = append(children, ChildReference{Kind: child.Kind, Name: child.Name, PipelineTaskName: child.PipelineTaskName})
+	}
+	if len(children) > 0 {
+		return children
+	}
+	for name, taskRun := range p.v1beta1.Status.TaskRuns {
+		children = append(children, ChildReference{Kind: "TaskRun", Name: name, PipelineTaskName: taskRun.PipelineTaskName})
+	}
+	for name, run := range p.v1beta1.Status.Runs {
+		children = append(children, ChildReference{Kind: "Run", Name: name, PipelineTaskName: run.PipelineTaskName})
+	}
+	sort.Slice(children, func(i, j int) bool { return children[i].Name < children[j].Name })
+	return children
+}
+
+// TaskRunAccessor gives access to the fields of a tekton.dev/v1beta1 or tekton.dev/v1 TaskRun which
+// differ between the two versions
+type TaskRunAccessor struct {
+	v1beta1 *v1beta1.TaskRun
+	v1      *tektonv1.TaskRun
+}
+
+// NewTaskRunAccessor wraps a v1beta1 or v1 TaskRun
+func NewTaskRunAccessor(taskRun interface{}) (*TaskRunAccessor, error) {
+	switch tr := taskRun.(type) {
+	case *v1beta1.TaskRun:
+		return &TaskRunAccessor{v1beta1: tr}, nil
+	case v1beta1.TaskRun:
+		return &TaskRunAccessor{v1beta1: &tr}, nil
+	case *tektonv1.TaskRun:
+		return &TaskRunAccessor{v1: tr}, nil
+	case tektonv1.TaskRun:
+		return &TaskRunAccessor{v1: &tr}, nil
+	}
+	return nil, fmt.Errorf("expected a v1beta1 or v1 TaskRun, got %T", taskRun)
+}
+
+// APIVersion returns the version of the wrapped TaskRun
+func (t *TaskRunAccessor) APIVersion() string {
+	if t.v1 != nil {
+		return TektonV1
+	}
+	return TektonV1beta1
+}
+
+// V1beta1 returns the wrapped TaskRun if it's a v1beta1 one, nil otherwise
+func (t *TaskRunAccessor) V1beta1() *v1beta1.TaskRun {
+	return t.v1beta1
+}
+
+// V1 returns the wrapped TaskRun if it's a v1 one, nil otherwise
+func (t *TaskRunAccessor) V1() *tektonv1.TaskRun {
+	return t.v1
+}
+
+// Object returns the metadata of the TaskRun
+func (t *TaskRunAccessor) Object() metav1.Object {
+	if t.v1 != nil {
+		return t.v1
+	}
+	return t.v1beta1
+}
+
+func (t *TaskRunAccessor) Name() string {
+	return t.Object().GetName()
+}
+
+func (t *TaskRunAccessor) Namespace() string {
+	return t.Object().GetNamespace()
+}
+
+// PipelineTaskName returns the name of the pipeline task the TaskRun was created for
+func (t *TaskRunAccessor) PipelineTaskName() string {
+	return t.Object().GetLabels()["tekton.dev/pipelineTask"]
+}
+
+// Condition returns the Succeeded condition of the TaskRun, or nil if it's not set yet
+func (t *TaskRunAccessor) Condition() *apis.Condition {
+	if t.v1 != nil {
+		return t.v1.Status.GetCondition(apis.ConditionSucceeded)
+	}
+	return t.v1beta1.Status.GetCondition(apis.ConditionSucceeded)
+}
+
+// Succeeded returns true if the TaskRun finished successfully
+func (t *TaskRunAccessor) Succeeded() bool {
+	return t.Condition().IsTrue()
+}
+
+func (t *TaskRunAccessor) PodName() string {
+	if t.v1 != nil {
+		return t.v1.Status.PodName
+	}
+	return t.v1beta1.Status.PodName
+}
+
+func (t *TaskRunAccessor) CompletionTime() *metav1.Time {
+	if t.v1 != nil {
+		return t.v1.Status.CompletionTime
+	}
+	return t.v1beta1.Status.CompletionTime
+}
+
+// StepState is the state of the container of a TaskRun step
+type StepState struct {
+	corev1.ContainerState
+	Name          string
+	ContainerName string
+}
+
+// Steps returns the state of the step containers of the TaskRun
+func (t *TaskRunAccessor) Steps() []StepState {
+	var steps []StepState
+	if t.v1 != nil {
+		for _, step := range t.v1.Status.Steps {
+			steps = append(steps, StepState{ContainerState: step.ContainerState, Name: step.Name, ContainerName: step.Container})
+		}
+		return steps
+	}
+	for _, step := range t.v1beta1.Status.Steps {
+		steps = append(steps, StepState{ContainerState: step.ContainerState, Name: step.Name, ContainerName: step.ContainerName})
+	}
+	return steps
+}
+
+// Results returns the values of the TaskRun results. Array and object values are JSON encoded
+func (t *TaskRunAccessor) Results() map[string]string {
+	results := map[string]string{}
+	if t.v1 != nil {
+		for _, result := range t.v1.Status.Results {
+			results[result.Name] = v1ValueString(result.Value)
+		}
+		return results
+	}
+	for _, result := range t.v1beta1.Status.TaskRunResults {
+		results[result.Name] = v1beta1ValueString(result.Value)
+	}
+	return results
+}
+
+func v1beta1ValueString(value v1beta1.ParamValue) string {
+	return valueString(string(value.Type), value.StringVal, value.ArrayVal, value.ObjectVal)
+}
+
+func v1ValueString(value tektonv1.ParamValue) string {
+	return valueString(string(value.Type), value.StringVal, value.ArrayVal, value.ObjectVal)
+}
+
+func valueString(paramType, stringVal string, arrayVal []string, objectVal map[string]string) string {
+	var encoded []byte
+	switch paramType {
+	case string(v1beta1.ParamTypeArray):
+		encoded, _ = json.Marshal(arrayVal)
+	case string(v1beta1.ParamTypeObject):
+		encoded, _ = json.Marshal(objectVal)
+	default:
+		return stringVal
+	}
+	return string(encoded)
+}
@@ -0,0 +1,138 @@
+package tekton
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	duckv1beta1 "knative.dev/pkg/apis/duck/v1beta1"
+)
+
+func TestDiscoverPipelineAPIVersion(t *testing.T) {
+	client := kubefake.NewSimpleClientset()
+	version, err := DiscoverPipelineAPIVersion(client.Discovery())
+	assert.NoError(t, err)
+	assert.Equal(t, TektonV1beta1, version)
+
+	client.Resources = []*metav1.APIResourceList{{
+		GroupVersion: "tekton.dev/v1",
+		APIResources: []metav1.APIResource{{Name: "tasks"}, {Name: "pipelineruns"}},
+	}}
+	version, err = DiscoverPipelineAPIVersion(client.Discovery())
+	assert.NoError(t, err)
+	assert.Equal(t, TektonV1, version)
+}
+
+func TestPipelineAPIVersionIsCachedPerController(t *testing.T) {
+	// The controller has no client, so the version can only come from the cache
+	s := &SuiteController{apiVersion: &pipelineAPIVersion{version: TektonV1}}
+	version, err := s.PipelineAPIVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, TektonV1, version)
+
+	// Copies of the controller, as embedded in KubeController, share the discovered version
+	copied := *s
+	version, err = copied.PipelineAPIVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, TektonV1, version)
+}
+
+func TestPipelineRunAccessorV1beta1(t *testing.T) {
+	pr, err := NewPipelineRunAccessor(v1beta1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "ns"},
+		Spec: v1beta1.PipelineRunSpec{Params: []v1beta1.Param{
+			{Name: "git-url", Value: *v1beta1.NewArrayOrString("https://github.com/org/repo")},
+			{Name: "build-args", Value: *v1beta1.NewArrayOrString("a=1", "b=2")},
+		}},
+		Status: v1beta1.PipelineRunStatus{
+			Status: duckv1beta1.Status{Conditions: duckv1beta1.Conditions{{Type: apis.ConditionSucceeded, Status: corev1.ConditionTrue}}},
+			PipelineRunStatusFields: v1beta1.PipelineRunStatusFields{
+				StartTime:       &metav1.Time{Time: time.Now()},
+				CompletionTime:  &metav1.Time{},
+				PipelineResults: []v1beta1.PipelineRunResult{{Name: "IMAGE_URL", Value: *v1beta1.NewArrayOrString("quay.io/org/repo")}},
+				TaskRuns: map[string]*v1beta1.PipelineRunTaskRunStatus{
+					"build-init":    {PipelineTaskName: "init"},
+					"build-buildah": {PipelineTaskName: "buildah"},
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, TektonV1beta1, pr.APIVersion())
+	assert.Equal(t, "build", pr.Name())
+	assert.Equal(t, "ns", pr.Namespace())
+	assert.True(t, pr.HasStarted())
+	assert.True(t, pr.IsDone())
+	assert.True(t, pr.Succeeded())
+	assert.Equal(t, map[string]string{"git-url": "https://github.com/org/repo", "build-args": `["a=1","b=2"]`}, pr.Params())
+	assert.Equal(t, map[string]string{"IMAGE_URL": "quay.io/org/repo"}, pr.Results())
+	assert.Equal(t, []ChildReference{
+		{Kind: "TaskRun", Name: "build-buildah", PipelineTaskName: "buildah"},
+		{Kind: "TaskRun", Name: "build-init", PipelineTaskName: "init"},
+	}, pr.ChildReferences())
+}
+
+func TestPipelineRunAccessorV1(t *testing.T) {
+	pr, err := NewPipelineRunAccessor(&tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "build", Namespace: "ns"},
+		Status: tektonv1.PipelineRunStatus{
+			Status: duckv1beta1.Status{Conditions: duckv1beta1.Conditions{{Type: apis.ConditionSucceeded, Status: corev1.ConditionFalse}}},
+			PipelineRunStatusFields: tektonv1.PipelineRunStatusFields{
+				Results: []tektonv1.PipelineRunResult{{Name: "IMAGE_URL", Value: *tektonv1.NewStructuredValues("quay.io/org/repo")}},
+				ChildReferences: []tektonv1.ChildStatusReference{{
+					TypeMeta:         runtime.TypeMeta{Kind: "TaskRun"},
+					Name:             "build-buildah",
+					PipelineTaskName: "buildah",
+				}},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, TektonV1, pr.APIVersion())
+	assert.Nil(t, pr.V1beta1())
+	assert.True(t, pr.IsDone())
+	assert.False(t, pr.Succeeded())
+	assert.Equal(t, map[string]string{"IMAGE_URL": "quay.io/org/repo"}, pr.Results())
+	assert.Equal(t, []ChildReference{{Kind: "TaskRun", Name: "build-buildah", PipelineTaskName: "buildah"}}, pr.ChildReferences())
+
+	_, err = NewPipelineRunAccessor(&v1beta1.TaskRun{})
+	assert.Error(t, err)
+}
+
+func TestTaskRunAccessor(t *testing.T) {
+	tr, err := NewTaskRunAccessor(&tektonv1.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "build-buildah", Labels: map[string]string{"tekton.dev/pipelineTask": "buildah"}},
+		Status: tektonv1.TaskRunStatus{
+			Status: duckv1.Status{Conditions: duckv1.Conditions{{Type: apis.ConditionSucceeded, Status: corev1.ConditionTrue}}},
+			TaskRunStatusFields: tektonv1.TaskRunStatusFields{
+				PodName: "build-buildah-pod",
+				Steps:   []tektonv1.StepState{{Name: "build", Container: "step-build"}},
+				Results: []tektonv1.TaskRunResult{
+					{Name: "IMAGE_DIGEST", Value: *tektonv1.NewStructuredValues("sha256:abc")},
+					{Name: "LABELS", Value: *tektonv1.NewObject(map[string]string{"a": "1"})},
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, TektonV1, tr.APIVersion())
+	assert.Equal(t, "buildah", tr.PipelineTaskName())
+	assert.True(t, tr.Succeeded())
+	assert.Equal(t, "build-buildah-pod", tr.PodName())
+	assert.Equal(t, []StepState{{Name: "build", ContainerName: "step-build"}}, tr.Steps())
+	assert.Equal(t, map[string]string{"IMAGE_DIGEST": "sha256:abc", "LABELS": `{"a":"1"}`}, tr.Results())
+
+	assert.True(t, DidTaskSucceed(tr))
+
+	match, err := MatchTaskRunResult("IMAGE_DIGEST", "sha256:abc").Match(tr.V1().Status.Results[0])
+	assert.True(t, match)
+	assert.Nil(t, err)
+}
@@ -269,26 +269,45 @@ func BuildAndPushTektonBundle(YamlContent []byte, ref name.Reference, remoteOpti
 // GetDefaultPipelineBundleRef gets the specific Tekton pipeline bundle reference from a Build pipeline selector
 // (in a YAML format) from a URL specified in the parameter
 func GetDefaultPipelineBundleRef(buildPipelineSelectorYamlURL, selectorName string) (string, error) {
+	pipelineRef, err := GetDefaultPipelineRef(buildPipelineSelectorYamlURL, selectorName)
+	if err != nil {
+		return "", err
+	}
+	return pipelineRef.Bundle, nil
+}
+
+// GetDefaultPipelineRef gets the pipeline reference (pipeline name and bundle) of a selector from a Build pipeline selector
+// (in a YAML format) from a URL specified in the parameter
+func GetDefaultPipelineRef(buildPipelineSelectorYamlURL, selectorName string) (*v1beta1.PipelineRef, error) {
+	ps, err := GetBuildPipelineSelector(buildPipelineSelectorYamlURL)
+	if err != nil {
+		return nil, err
+	}
+	for i := range ps.Spec.Selectors {
+		if ps.Spec.Selectors[i].Name == selectorName {
+			return &ps.Spec.Selectors[i].PipelineRef, nil
+		}
+	}
+
+	return nil, fmt.Errorf("could not find %s pipeline bundle in build pipeline selector fetched from %s", selectorName, buildPipelineSelectorYamlURL)
+}
+
+// GetBuildPipelineSelector gets a Build pipeline selector (in a YAML format) from a URL specified in the parameter
+func GetBuildPipelineSelector(buildPipelineSelectorYamlURL string) (*buildservice.BuildPipelineSelector, error) {
 	res, err := http.Get(buildPipelineSelectorYamlURL)
 	if err != nil {
-		return "", fmt.Errorf("failed to get a build pipeline selector from url %s: %v", buildPipelineSelectorYamlURL, err)
+		return nil, fmt.Errorf("failed to get a build pipeline selector from url %s: %v", buildPipelineSelectorYamlURL, err)
 	}
 	defer res.Body.Close()
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read the body response of a build pipeline selector: %v", err)
+		return nil, fmt.Errorf("failed to read the body response of a build pipeline selector: %v", err)
 	}
 	ps := &buildservice.BuildPipelineSelector{}
 	if err = yaml.Unmarshal(body, ps); err != nil {
-		return "", fmt.Errorf("failed to unmarshal build pipeline selector: %v", err)
+		return nil, fmt.Errorf("failed to unmarshal build pipeline selector: %v", err)
 	}
-	for _, s := range ps.Spec.Selectors {
-		if s.Name == selectorName {
-			return s.PipelineRef.Bundle, nil
-		}
-	}
-
-	return "", fmt.Errorf("could not find %s pipeline bundle in build pipeline selector fetched from %s", selectorName, buildPipelineSelectorYamlURL)
+	return ps, nil
 }
 
 // ParseDevfileModel calls the devfile library's parse and returns the devfile data
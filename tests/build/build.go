@@ -8,7 +8,6 @@ import (
 
 	"k8s.io/utils/pointer"
 
-	"github.com/google/go-github/v44/github"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils/tekton"
 
 	"github.com/devfile/library/pkg/util"
@@ -21,6 +20,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	buildservice "github.com/redhat-appstudio/build-service/api/v1alpha1"
+	"github.com/redhat-appstudio/e2e-tests/pkg/apis/git"
 	"github.com/redhat-appstudio/e2e-tests/pkg/framework"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -35,6 +35,7 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 
 	Describe("test PaC component build", Ordered, Label("github-webhook", "pac-build", "pipeline"), func() {
 		var applicationName, componentName, componentBaseBranchName, pacBranchName, testNamespace, outputContainerImage, pacControllerHost, defaultBranchTestComponentName string
+		var helloWorldComponentGitSourceURL string
 
 		var timeout, interval time.Duration
 
@@ -42,7 +43,7 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 		var prCreationTime time.Time
 
 		BeforeAll(func() {
-			f, err = framework.NewFramework(utils.GetGeneratedNamespace("build-e2e"))
+			f, err = framework.NewFramework(utils.GetGeneratedNamespace(constants.BUILD_E2E_USER_PREFIX))
 			Expect(err).NotTo(HaveOccurred())
 			testNamespace = f.UserNamespace
 
@@ -70,10 +71,12 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 			componentBaseBranchName = fmt.Sprintf("base-%s", util.GenerateRandomString(4))
 			outputContainerImage = fmt.Sprintf("quay.io/%s/test-images", utils.GetQuayIOOrganization())
 
-			err = f.AsKubeAdmin.CommonController.Github.CreateRef(helloWorldComponentGitSourceRepoName, helloWorldComponentDefaultBranch, componentBaseBranchName)
+			err = f.AsKubeAdmin.CommonController.GitProvider.CreateRef(helloWorldComponentGitSourceRepoName, helloWorldComponentDefaultBranch, componentBaseBranchName)
 			Expect(err).ShouldNot(HaveOccurred())
 
 			defaultBranchTestComponentName = fmt.Sprintf("test-custom-default-branch-%s", util.GenerateRandomString(4))
+			// The component has to be built from the repository the specs check PaC branches, PRs and webhooks in
+			helloWorldComponentGitSourceURL = f.AsKubeAdmin.CommonController.GitProvider.RepositoryURL(helloWorldComponentGitSourceRepoName)
 		})
 
 		AfterAll(func() {
@@ -83,27 +86,22 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 			}
 
 			// Delete new branches created by PaC and a testing branch used as a component's base branch
-			err = f.AsKubeAdmin.CommonController.Github.DeleteRef(helloWorldComponentGitSourceRepoName, pacBranchName)
-			if err != nil {
-				Expect(err.Error()).To(ContainSubstring("Reference does not exist"))
-			}
-			err = f.AsKubeAdmin.CommonController.Github.DeleteRef(helloWorldComponentGitSourceRepoName, componentBaseBranchName)
-			if err != nil {
-				Expect(err.Error()).To(ContainSubstring("Reference does not exist"))
-			}
-			err = f.AsKubeAdmin.CommonController.Github.DeleteRef(helloWorldComponentGitSourceRepoName, pacPRBranchPrefix+defaultBranchTestComponentName)
-			if err != nil {
-				Expect(err.Error()).To(ContainSubstring("Reference does not exist"))
+			// The providers report missing branches differently, so check for the branch before deleting it
+			for _, branch := range []string{pacBranchName, componentBaseBranchName, pacPRBranchPrefix + defaultBranchTestComponentName} {
+				exists, err := f.AsKubeAdmin.CommonController.GitProvider.ExistsRef(helloWorldComponentGitSourceRepoName, branch)
+				Expect(err).NotTo(HaveOccurred())
+				if exists {
+					Expect(f.AsKubeAdmin.CommonController.GitProvider.DeleteRef(helloWorldComponentGitSourceRepoName, branch)).To(Succeed())
+				}
 			}
 
-			// Delete created webhook from GitHub
-			hooks, err := f.AsKubeAdmin.CommonController.Github.ListRepoWebhooks(helloWorldComponentGitSourceRepoName)
+			// Delete created webhook from the git provider
+			hooks, err := f.AsKubeAdmin.CommonController.GitProvider.ListRepoWebhooks(helloWorldComponentGitSourceRepoName)
 			Expect(err).NotTo(HaveOccurred())
 
 			for _, h := range hooks {
-				hookUrl := h.Config["url"].(string)
-				if strings.Contains(hookUrl, pacControllerHost) {
-					Expect(f.AsKubeAdmin.CommonController.Github.DeleteWebhook(helloWorldComponentGitSourceRepoName, h.GetID())).To(Succeed())
+				if strings.Contains(h.URL, pacControllerHost) {
+					Expect(f.AsKubeAdmin.CommonController.GitProvider.DeleteWebhook(helloWorldComponentGitSourceRepoName, h.ID)).To(Succeed())
 					break
 				}
 			}
@@ -111,7 +109,7 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 
 		When("a new component without specified branch is created", Label("pac-custom-default-branch"), func() {
 			BeforeAll(func() {
-				_, err = f.AsKubeDeveloper.HasController.CreateComponentWithPaCEnabled(applicationName, defaultBranchTestComponentName, testNamespace, helloWorldComponentGitSourceURL, "", outputContainerImage)
+				_, err = f.AsKubeDeveloper.HasController.NewComponentBuilder(applicationName, defaultBranchTestComponentName, testNamespace).WithGitSource(helloWorldComponentGitSourceURL, "").WithOutputImage(outputContainerImage).WithPaC().Create()
 				Expect(err).ShouldNot(HaveOccurred())
 			})
 
@@ -119,12 +117,12 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 				timeout = time.Second * 300
 				interval = time.Second * 1
 				Eventually(func() bool {
-					prs, err := f.AsKubeAdmin.CommonController.Github.ListPullRequests(helloWorldComponentGitSourceRepoName)
+					prs, err := f.AsKubeAdmin.CommonController.GitProvider.ListPullRequests(helloWorldComponentGitSourceRepoName)
 					Expect(err).ShouldNot(HaveOccurred())
 
 					for _, pr := range prs {
-						if pr.Head.GetRef() == pacPRBranchPrefix+defaultBranchTestComponentName {
-							Expect(pr.GetBase().GetRef()).To(Equal(helloWorldComponentDefaultBranch))
+						if pr.SourceBranch == pacPRBranchPrefix+defaultBranchTestComponentName {
+							Expect(pr.TargetBranch).To(Equal(helloWorldComponentDefaultBranch))
 							return true
 						}
 					}
@@ -154,12 +152,11 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 				}, timeout, interval).Should(BeTrue(), "timed out when waiting for the PipelineRun to start")
 				// Test removal of related webhook in GitHub repo
 				Eventually(func() bool {
-					hooks, err := f.AsKubeAdmin.CommonController.Github.ListRepoWebhooks(helloWorldComponentGitSourceRepoName)
+					hooks, err := f.AsKubeAdmin.CommonController.GitProvider.ListRepoWebhooks(helloWorldComponentGitSourceRepoName)
 					Expect(err).NotTo(HaveOccurred())
 
 					for _, h := range hooks {
-						hookUrl := h.Config["url"].(string)
-						if strings.Contains(hookUrl, pacControllerHost) {
+						if strings.Contains(h.URL, pacControllerHost) {
 							return false
 						}
 					}
@@ -170,7 +167,7 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 				timeout = time.Second * 60
 				interval = time.Second * 1
 				Eventually(func() bool {
-					exists, err := f.AsKubeAdmin.CommonController.Github.ExistsRef(helloWorldComponentGitSourceRepoName, pacPRBranchPrefix+defaultBranchTestComponentName)
+					exists, err := f.AsKubeAdmin.CommonController.GitProvider.ExistsRef(helloWorldComponentGitSourceRepoName, pacPRBranchPrefix+defaultBranchTestComponentName)
 					Expect(err).ShouldNot(HaveOccurred())
 					return exists
 				}, timeout, interval).Should(BeFalse(), "timed out when waiting for the branch to be deleted")
@@ -180,7 +177,7 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 		When("a new component with specified custom branch branch is created", func() {
 			BeforeAll(func() {
 				// Create a component with Git Source URL and a specified git branch
-				_, err = f.AsKubeAdmin.HasController.CreateComponentWithPaCEnabled(applicationName, componentName, testNamespace, helloWorldComponentGitSourceURL, componentBaseBranchName, outputContainerImage)
+				_, err = f.AsKubeAdmin.HasController.NewComponentBuilder(applicationName, componentName, testNamespace).WithGitSource(helloWorldComponentGitSourceURL, componentBaseBranchName).WithOutputImage(outputContainerImage).WithPaC().Create()
 				Expect(err).ShouldNot(HaveOccurred())
 			})
 			It("triggers a PipelineRun", func() {
@@ -200,13 +197,13 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 				interval = time.Second * 1
 
 				Eventually(func() bool {
-					prs, err := f.AsKubeAdmin.CommonController.Github.ListPullRequests(helloWorldComponentGitSourceRepoName)
+					prs, err := f.AsKubeAdmin.CommonController.GitProvider.ListPullRequests(helloWorldComponentGitSourceRepoName)
 					Expect(err).ShouldNot(HaveOccurred())
 
 					for _, pr := range prs {
-						if pr.Head.GetRef() == pacBranchName {
-							prNumber = pr.GetNumber()
-							prCreationTime = pr.GetCreatedAt()
+						if pr.SourceBranch == pacBranchName {
+							prNumber = pr.Number
+							prCreationTime = pr.CreatedAt
 							return true
 						}
 					}
@@ -234,14 +231,20 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 					}
 					return true
 				}, timeout, interval).Should(BeTrue(), "timed out when waiting for the PipelineRun to finish")
+
+				pipelineRun, err := f.AsKubeAdmin.HasController.GetComponentPipelineRun(componentName, applicationName, testNamespace, "")
+				Expect(err).ShouldNot(HaveOccurred())
+				performance, err := f.AsKubeAdmin.TektonController.GetPipelineRunPerformance(pipelineRun.Name, testNamespace)
+				Expect(err).ShouldNot(HaveOccurred())
+				performance.AddToReport()
 			})
 			It("eventually leads to a creation of a PR comment with the PipelineRun status report", func() {
-				var comments []*github.IssueComment
+				var comments []*git.Comment
 				timeout = time.Minute * 15
 				interval = time.Second * 10
 
 				Eventually(func() bool {
-					comments, err = f.AsKubeAdmin.CommonController.Github.ListPullRequestCommentsSince(helloWorldComponentGitSourceRepoName, prNumber, prCreationTime)
+					comments, err = f.AsKubeAdmin.CommonController.GitProvider.ListPullRequestCommentsSince(helloWorldComponentGitSourceRepoName, prNumber, prCreationTime)
 					Expect(err).ShouldNot(HaveOccurred())
 
 					return len(comments) != 0
@@ -249,7 +252,7 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 
 				// TODO uncomment once https://issues.redhat.com/browse/SRVKP-2471 is sorted
 				//Expect(comments).To(HaveLen(1), fmt.Sprintf("the initial PR has more than 1 comment after a single pipelinerun. repo: %s, pr number: %d, comments content: %v", helloWorldComponentGitSourceURL, prNumber, comments))
-				Expect(comments[len(comments)-1]).To(ContainSubstring("success"), "the initial PR doesn't contain the info about successful pipelinerun")
+				Expect(comments[len(comments)-1].Body).To(ContainSubstring("success"), "the initial PR doesn't contain the info about successful pipelinerun")
 			})
 		})
 
@@ -260,10 +263,10 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 			BeforeAll(func() {
 				fileToCreatePath := fmt.Sprintf(".tekton/%s-readme.md", componentName)
 				branchUpdateTimestamp = time.Now()
-				createdFile, err := f.AsKubeAdmin.CommonController.Github.CreateFile(helloWorldComponentGitSourceRepoName, fileToCreatePath, fmt.Sprintf("test PaC branch %s update", pacBranchName), pacBranchName)
+				createdFile, err := f.AsKubeAdmin.CommonController.GitProvider.CreateFile(helloWorldComponentGitSourceRepoName, fileToCreatePath, fmt.Sprintf("test PaC branch %s update", pacBranchName), pacBranchName)
 				Expect(err).NotTo(HaveOccurred())
 
-				createdFileSHA = createdFile.GetSHA()
+				createdFileSHA = createdFile.CommitSHA
 				GinkgoWriter.Println("created file sha:", createdFileSHA)
 			})
 
@@ -278,7 +281,9 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 						return false
 					}
 					return pipelineRun.HasStarted()
-				}, timeout, interval).Should(BeTrue(), "timed out when waiting for the PipelineRun to start")
+				}, timeout, interval).Should(BeTrue(), func() string {
+					return "timed out when waiting for the PipelineRun to start, " + f.AsKubeAdmin.CommonController.GitProvider.DescribeWebhookDeliveries(helloWorldComponentGitSourceRepoName, pacControllerHost, branchUpdateTimestamp)
+				})
 			})
 			It("PipelineRun should eventually finish", func() {
 				timeout = time.Minute * 50
@@ -304,13 +309,13 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 				}, timeout, interval).Should(BeTrue(), "timed out when waiting for the PipelineRun to finish")
 			})
 			It("eventually leads to another update of a PR with a comment about the PipelineRun status report", func() {
-				var comments []*github.IssueComment
+				var comments []*git.Comment
 
 				timeout = time.Minute * 20
 				interval = time.Second * 5
 
 				Eventually(func() bool {
-					comments, err = f.AsKubeAdmin.CommonController.Github.ListPullRequestCommentsSince(helloWorldComponentGitSourceRepoName, prNumber, branchUpdateTimestamp)
+					comments, err = f.AsKubeAdmin.CommonController.GitProvider.ListPullRequestCommentsSince(helloWorldComponentGitSourceRepoName, prNumber, branchUpdateTimestamp)
 					Expect(err).ShouldNot(HaveOccurred())
 
 					return len(comments) != 0
@@ -318,21 +323,23 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 
 				// TODO uncomment once https://issues.redhat.com/browse/SRVKP-2471 is sorted
 				//Expect(comments).To(HaveLen(1), fmt.Sprintf("the updated PaC PR has more than 1 comment after a single branch update. repo: %s, pr number: %d, comments content: %v", helloWorldComponentGitSourceURL, prNumber, comments))
-				Expect(comments[len(comments)-1]).To(ContainSubstring("success"), "the updated PR doesn't contain the info about successful pipelinerun")
+				Expect(comments[len(comments)-1].Body).To(ContainSubstring("success"), "the updated PR doesn't contain the info about successful pipelinerun")
 			})
 		})
 
 		When("the PaC init branch is merged", func() {
-			var mergeResult *github.PullRequestMergeResult
+			var mergeResult *git.PullRequest
 			var mergeResultSha string
+			var mergeTimestamp time.Time
 
 			BeforeAll(func() {
+				mergeTimestamp = time.Now()
 				Eventually(func() error {
-					mergeResult, err = f.AsKubeAdmin.CommonController.Github.MergePullRequest(helloWorldComponentGitSourceRepoName, prNumber)
+					mergeResult, err = f.AsKubeAdmin.CommonController.GitProvider.MergePullRequest(helloWorldComponentGitSourceRepoName, prNumber)
 					return err
 				}, time.Minute).Should(BeNil(), fmt.Sprintf("error when merging PaC pull request: %+v", err))
 
-				mergeResultSha = mergeResult.GetSHA()
+				mergeResultSha = mergeResult.MergeCommitSHA
 				GinkgoWriter.Println("merged result sha:", mergeResultSha)
 			})
 
@@ -347,7 +354,9 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 						return false
 					}
 					return pipelineRun.HasStarted()
-				}, timeout, interval).Should(BeTrue(), "timed out when waiting for the PipelineRun to start")
+				}, timeout, interval).Should(BeTrue(), func() string {
+					return "timed out when waiting for the PipelineRun to start, " + f.AsKubeAdmin.CommonController.GitProvider.DescribeWebhookDeliveries(helloWorldComponentGitSourceRepoName, pacControllerHost, mergeTimestamp)
+				})
 			})
 
 			It("pipelineRun should eventually finish", func() {
@@ -384,18 +393,18 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 					return errors.IsNotFound(err)
 				}, time.Minute*1, time.Second*1).Should(BeTrue(), "timed out when waiting for the app %s to be deleted in %s namespace", applicationName, testNamespace)
 
-				_, err = f.AsKubeAdmin.HasController.CreateComponentWithPaCEnabled(applicationName, componentName, testNamespace, helloWorldComponentGitSourceURL, componentBaseBranchName, outputContainerImage)
+				_, err = f.AsKubeAdmin.HasController.NewComponentBuilder(applicationName, componentName, testNamespace).WithGitSource(helloWorldComponentGitSourceURL, componentBaseBranchName).WithOutputImage(outputContainerImage).WithPaC().Create()
 			})
 
 			It("should no longer lead to a creation of a PaC PR", func() {
 				timeout = time.Second * 40
 				interval = time.Second * 2
 				Consistently(func() bool {
-					prs, err := f.AsKubeAdmin.CommonController.Github.ListPullRequests(helloWorldComponentGitSourceRepoName)
+					prs, err := f.AsKubeAdmin.CommonController.GitProvider.ListPullRequests(helloWorldComponentGitSourceRepoName)
 					Expect(err).ShouldNot(HaveOccurred())
 
 					for _, pr := range prs {
-						if pr.Head.GetRef() == pacBranchName {
+						if pr.SourceBranch == pacBranchName {
 							return true
 						}
 					}
@@ -412,7 +421,7 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 
 		BeforeAll(func() {
 			applicationName = fmt.Sprintf("test-app-%s", util.GenerateRandomString(4))
-			f, err = framework.NewFramework(utils.GetGeneratedNamespace("build-e2e"))
+			f, err = framework.NewFramework(utils.GetGeneratedNamespace(constants.BUILD_E2E_USER_PREFIX))
 			Expect(err).NotTo(HaveOccurred())
 			testNamespace = f.UserNamespace
 
@@ -423,11 +432,10 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 			)
 
 			componentName = fmt.Sprintf("build-suite-test-component-image-source-%s", util.GenerateRandomString(4))
-			outputContainerImage := ""
 			timeout = time.Second * 500
 			interval = time.Second * 1
 			// Create a component with containerImageSource being defined
-			_, err = f.AsKubeAdmin.HasController.CreateComponent(applicationName, componentName, testNamespace, "", "", containerImageSource, outputContainerImage, "", true)
+			_, err = f.AsKubeAdmin.HasController.NewComponentBuilder(applicationName, componentName, testNamespace).WithContainerImageSource(containerImageSource).WithSkipInitialChecks(true).Create()
 			Expect(err).ShouldNot(HaveOccurred())
 		})
 
@@ -455,7 +463,7 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 		var expectedAdditionalPipelineParam buildservice.PipelineParam
 
 		BeforeAll(func() {
-			f, err = framework.NewFramework(utils.GetGeneratedNamespace("build-e2e"))
+			f, err = framework.NewFramework(utils.GetGeneratedNamespace(constants.BUILD_E2E_USER_PREFIX))
 			Expect(err).NotTo(HaveOccurred())
 			testNamespace = f.UserNamespace
 			applicationName = fmt.Sprintf("test-app-%s", util.GenerateRandomString(4))
@@ -516,7 +524,7 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 		})
 
 		It("a specific Pipeline bundle should be used and additional pipeline params should be added to the PipelineRun if all WhenConditions match", func() {
-			_, err = f.AsKubeAdmin.HasController.CreateComponent(applicationName, componentName, testNamespace, helloWorldComponentGitSourceURL, "", "", outputContainerImage, "", true)
+			_, err = f.AsKubeAdmin.HasController.NewComponentBuilder(applicationName, componentName, testNamespace).WithGitSource(f.AsKubeAdmin.CommonController.GitProvider.RepositoryURL(helloWorldComponentGitSourceRepoName), "").WithOutputImage(outputContainerImage).WithSkipInitialChecks(true).Create()
 			Expect(err).ShouldNot(HaveOccurred())
 
 			Eventually(func() bool {
@@ -539,7 +547,7 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 
 		It("default Pipeline bundle should be used and no additional Pipeline params should be added to the PipelineRun if one of the WhenConditions does not match", func() {
 			notMatchingComponentName := componentName + util.GenerateRandomString(4)
-			_, err = f.AsKubeAdmin.HasController.CreateComponent(applicationName, notMatchingComponentName, testNamespace, helloWorldComponentGitSourceURL, "", "", outputContainerImage, "", true)
+			_, err = f.AsKubeAdmin.HasController.NewComponentBuilder(applicationName, notMatchingComponentName, testNamespace).WithGitSource(f.AsKubeAdmin.CommonController.GitProvider.RepositoryURL(helloWorldComponentGitSourceRepoName), "").WithOutputImage(outputContainerImage).WithSkipInitialChecks(true).Create()
 			Expect(err).ShouldNot(HaveOccurred())
 			Eventually(func() bool {
 				pipelineRun, err := f.AsKubeAdmin.HasController.GetComponentPipelineRun(notMatchingComponentName, applicationName, testNamespace, "")
@@ -570,7 +578,7 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 
 		BeforeAll(func() {
 
-			f, err = framework.NewFramework(utils.GetGeneratedNamespace("build-e2e"))
+			f, err = framework.NewFramework(utils.GetGeneratedNamespace(constants.BUILD_E2E_USER_PREFIX))
 			Expect(err).NotTo(HaveOccurred())
 			testNamespace = f.UserNamespace
 
@@ -611,7 +619,7 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 
 			componentName = "build-suite-test-secret-overriding"
 			outputContainerImage = fmt.Sprintf("quay.io/%s/test-images:%s", utils.GetQuayIOOrganization(), strings.Replace(uuid.New().String(), "-", "", -1))
-			_, err = f.AsKubeAdmin.HasController.CreateComponent(applicationName, componentName, testNamespace, helloWorldComponentGitSourceURL, "", "", outputContainerImage, "", true)
+			_, err = f.AsKubeAdmin.HasController.NewComponentBuilder(applicationName, componentName, testNamespace).WithGitSource(f.AsKubeAdmin.CommonController.GitProvider.RepositoryURL(helloWorldComponentGitSourceRepoName), "").WithOutputImage(outputContainerImage).WithSkipInitialChecks(true).Create()
 			Expect(err).ShouldNot(HaveOccurred())
 		})
 
@@ -50,7 +50,7 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 				_, err = kubeadminClient.CommonController.CreateTestNamespace(testNamespace)
 				Expect(err).ShouldNot(HaveOccurred())
 			} else {
-				f, err = framework.NewFramework(utils.GetGeneratedNamespace("build-e2e"))
+				f, err = framework.NewFramework(utils.GetGeneratedNamespace(constants.BUILD_E2E_USER_PREFIX))
 				Expect(err).NotTo(HaveOccurred())
 				testNamespace = f.UserNamespace
 				Expect(f.UserNamespace).NotTo(BeNil())
@@ -78,7 +78,7 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 				componentNames = append(componentNames, componentName)
 				outputContainerImage = fmt.Sprintf("quay.io/%s/test-images:%s", utils.GetQuayIOOrganization(), strings.Replace(uuid.New().String(), "-", "", -1))
 				// Create a component with Git Source URL being defined
-				_, err := kubeadminClient.HasController.CreateComponent(applicationName, componentName, testNamespace, gitUrl, "", "", outputContainerImage, "", false)
+				_, err := kubeadminClient.HasController.NewComponentBuilder(applicationName, componentName, testNamespace).WithGitSource(gitUrl, "").WithOutputImage(outputContainerImage).WithSkipInitialChecks(false).Create()
 				Expect(err).ShouldNot(HaveOccurred())
 			}
 		})
@@ -218,17 +218,19 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 				}
 				pipelineRun, err := kubeadminClient.HasController.GetComponentPipelineRun(componentNames[0], applicationName, testNamespace, "")
 				Expect(err).ShouldNot(HaveOccurred())
+				pr, err := kubeadminClient.TektonController.GetPipelineRunAccessor(pipelineRun.Name, testNamespace)
+				Expect(err).ShouldNot(HaveOccurred())
 
 				for i := range gatherResult {
 					if gatherResult[i] == "inspect-image" {
 						// Fetching BASE_IMAGE shouldn't fail
-						result, err := build.FetchImageTaskRunResult(pipelineRun, gatherResult[i], "BASE_IMAGE")
+						result, err := build.FetchImageTaskRunResult(kubeadminClient.TektonController, pr, gatherResult[i], "BASE_IMAGE")
 						Expect(err).ShouldNot(HaveOccurred())
 						ret := build.ValidateImageTaskRunResults(gatherResult[i], result)
 						Expect(ret).Should(BeTrue())
 					} else if gatherResult[i] == "clair-scan" {
 						// Fetching HACBS_TEST_OUTPUT shouldn't fail
-						result, err := build.FetchTaskRunResult(pipelineRun, gatherResult[i], "HACBS_TEST_OUTPUT")
+						result, err := build.FetchTaskRunResult(kubeadminClient.TektonController, pr, gatherResult[i], "HACBS_TEST_OUTPUT")
 						Expect(err).ShouldNot(HaveOccurred())
 						ret := build.ValidateTaskRunResults(gatherResult[i], result)
 						// Vulnerabilities should get periodically eliminated with image rebuild, so the result of that task might be different
@@ -236,7 +238,7 @@ var _ = framework.BuildSuiteDescribe("Build service E2E tests", Label("build", "
 						GinkgoWriter.Printf("retcode for validate taskrun result is %s\n", ret)
 					} else {
 						// Fetching HACBS_TEST_OUTPUT shouldn't fail
-						result, err := build.FetchTaskRunResult(pipelineRun, gatherResult[i], "HACBS_TEST_OUTPUT")
+						result, err := build.FetchTaskRunResult(kubeadminClient.TektonController, pr, gatherResult[i], "HACBS_TEST_OUTPUT")
 						Expect(err).ShouldNot(HaveOccurred())
 						ret := build.ValidateTaskRunResults(gatherResult[i], result)
 						Expect(ret).Should(BeTrue())
@@ -14,7 +14,6 @@ import (
 	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	buildservice "github.com/redhat-appstudio/build-service/api/v1alpha1"
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
 	"github.com/redhat-appstudio/e2e-tests/pkg/framework"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
@@ -172,7 +171,7 @@ var _ = framework.JVMBuildSuiteDescribe("JVM Build Service E2E tests", Label("jv
 	})
 
 	BeforeAll(func() {
-		f, err = framework.NewFramework(utils.GetGeneratedNamespace("jvm-build"))
+		f, err = framework.NewFramework(utils.GetGeneratedNamespace(constants.JVM_BUILD_E2E_USER_PREFIX))
 		Expect(err).NotTo(HaveOccurred())
 		testNamespace = f.UserNamespace
 		Expect(testNamespace).NotTo(BeNil(), "failed to create sandbox user namespace")
@@ -190,27 +189,6 @@ var _ = framework.JVMBuildSuiteDescribe("JVM Build Service E2E tests", Label("jv
 		_, err = f.AsKubeAdmin.CommonController.CreateSecret(testNamespace, jvmBuildSecret)
 		Expect(err).ShouldNot(HaveOccurred())
 
-		customJavaPipelineBundleRef := os.Getenv(constants.CUSTOM_JAVA_PIPELINE_BUILD_BUNDLE_ENV)
-		if len(customJavaPipelineBundleRef) > 0 {
-			ps := &buildservice.BuildPipelineSelector{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "build-pipeline-selector",
-					Namespace: testNamespace,
-				},
-				Spec: buildservice.BuildPipelineSelectorSpec{Selectors: []buildservice.PipelineSelector{
-					{
-						Name: "custom java selector",
-						PipelineRef: v1beta1.PipelineRef{
-							Name:   "java-builder",
-							Bundle: customJavaPipelineBundleRef,
-						},
-						WhenConditions: buildservice.WhenCondition{Language: "java"},
-					},
-				}},
-			}
-			Expect(f.AsKubeAdmin.CommonController.KubeRest().Create(context.TODO(), ps)).To(Succeed())
-		}
-
 		timeout = time.Minute * 20
 		interval = time.Second * 10
 
@@ -225,7 +203,7 @@ var _ = framework.JVMBuildSuiteDescribe("JVM Build Service E2E tests", Label("jv
 		outputContainerImage = fmt.Sprintf("quay.io/%s/test-images:%s", utils.GetQuayIOOrganization(), strings.Replace(uuid.New().String(), "-", "", -1))
 
 		// Create a component with Git Source URL being defined
-		_, err = f.AsKubeAdmin.HasController.CreateComponent(applicationName, componentName, testNamespace, testProjectGitUrl, testProjectRevision, "", outputContainerImage, "", true)
+		_, err = f.AsKubeAdmin.HasController.NewComponentBuilder(applicationName, componentName, testNamespace).WithGitSource(testProjectGitUrl, testProjectRevision).WithOutputImage(outputContainerImage).WithSkipInitialChecks(true).Create()
 		Expect(err).ShouldNot(HaveOccurred())
 	})
 
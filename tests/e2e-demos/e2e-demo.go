@@ -11,7 +11,8 @@ import (
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
 	"github.com/redhat-appstudio/e2e-tests/pkg/framework"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
-	"github.com/redhat-appstudio/e2e-tests/pkg/utils/tekton"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils/gitops"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils/has"
 	e2eConfig "github.com/redhat-appstudio/e2e-tests/tests/e2e-demos/config"
 	"github.com/spf13/viper"
 	appsv1 "k8s.io/api/apps/v1"
@@ -32,7 +33,6 @@ const (
 
 var _ = framework.E2ESuiteDescribe(Label("e2e-demo"), func() {
 	defer GinkgoRecover()
-	var outputContainerImage = ""
 	var timeout, interval time.Duration
 	var namespace string
 
@@ -54,13 +54,29 @@ var _ = framework.E2ESuiteDescribe(Label("e2e-demo"), func() {
 		appTest := appTest
 
 		Describe(appTest.Name, Ordered, func() {
+			// Timelines of the components, attached to the report once the tests of the application finished
+			var trackers []*has.ComponentLifecycleTracker
+			trackComponent := func(componentName string) {
+				tracker, err := fw.AsKubeAdmin.HasController.TrackComponentLifecycle(componentName, appTest.ApplicationName, namespace)
+				Expect(err).NotTo(HaveOccurred())
+				trackers = append(trackers, tracker)
+			}
+			// Checks the manifests HAS generated for a component in the gitops repository of the application,
+			// the base ones if the environment is empty
+			verifyGitOpsManifests := func(componentName, environment string, expected gitops.ComponentExpectation) {
+				gitOpsRepository, err := gitops.CloneApplicationGitOpsRepository(application)
+				Expect(err).NotTo(HaveOccurred())
+				defer gitOpsRepository.Remove()
+				Expect(gitOpsRepository.WaitForComponent(componentName, environment, expected, 5*time.Minute)).To(Succeed())
+			}
+
 			BeforeAll(func() {
 				if appTest.Skip {
 					Skip(fmt.Sprintf("test skipped %s", appTest.Name))
 				}
 
 				// Initialize the tests controllers
-				fw, err = framework.NewFramework(utils.GetGeneratedNamespace("e2e-demos"))
+				fw, err = framework.NewFramework(utils.GetGeneratedNamespace(constants.E2E_DEMOS_USER_PREFIX))
 				Expect(err).NotTo(HaveOccurred())
 				namespace = fw.UserNamespace
 				Expect(namespace).NotTo(BeEmpty())
@@ -77,6 +93,10 @@ var _ = framework.E2ESuiteDescribe(Label("e2e-demo"), func() {
 
 			// Remove all resources created by the tests
 			AfterAll(func() {
+				for _, tracker := range trackers {
+					tracker.AddToReport()
+					tracker.Stop()
+				}
 				if !CurrentSpecReport().Failed() {
 					Expect(fw.AsKubeDeveloper.HasController.DeleteAllComponentsInASpecificNamespace(namespace, 30*time.Second)).To(Succeed())
 					Expect(fw.AsKubeAdmin.HasController.DeleteAllApplicationsInASpecificNamespace(namespace, 30*time.Second)).To(Succeed())
@@ -152,17 +172,19 @@ var _ = framework.E2ESuiteDescribe(Label("e2e-demo"), func() {
 				// Components for now can be imported from gitUrl, container image or a devfile
 				if componentTest.ContainerSource != "" {
 					It(fmt.Sprintf("creates component %s from %s container source", componentTest.Name, componentTest.Type), func() {
-						component, err = fw.AsKubeDeveloper.HasController.CreateComponent(application.Name, componentTest.Name, namespace, "", "", componentTest.ContainerSource, outputContainerImage, SPIQuaySecretName, true)
+						trackComponent(componentTest.Name)
+						component, err = fw.AsKubeDeveloper.HasController.NewComponentBuilder(application.Name, componentTest.Name, namespace).WithContainerImageSource(componentTest.ContainerSource).WithSecret(SPIQuaySecretName).WithSkipInitialChecks(true).Create()
 						Expect(err).NotTo(HaveOccurred())
 					})
 				} else if componentTest.GitSourceUrl != "" {
 					It(fmt.Sprintf("creates component %s from %s git source %s", componentTest.Name, componentTest.Type, componentTest.GitSourceUrl), func() {
 						for _, compDetected := range cdq.Status.ComponentDetected {
+							trackComponent(compDetected.ComponentStub.ComponentName)
 							if componentTest.Type == "private" {
-								component, err = fw.AsKubeDeveloper.HasController.CreateComponentFromStub(compDetected, componentTest.Name, namespace, SPIGithubSecretName, appTest.ApplicationName)
+								component, err = fw.AsKubeDeveloper.HasController.NewComponentBuilder(appTest.ApplicationName, compDetected.ComponentStub.ComponentName, namespace).FromStub(compDetected.ComponentStub).WithSecret(SPIGithubSecretName).WithSkipInitialChecks(true).WithGeneratedImageRepository().Create()
 								Expect(err).NotTo(HaveOccurred())
 							} else if componentTest.Type == "public" {
-								component, err = fw.AsKubeDeveloper.HasController.CreateComponentFromStub(compDetected, componentTest.Name, namespace, "", appTest.ApplicationName)
+								component, err = fw.AsKubeDeveloper.HasController.NewComponentBuilder(appTest.ApplicationName, compDetected.ComponentStub.ComponentName, namespace).FromStub(compDetected.ComponentStub).WithSkipInitialChecks(true).WithGeneratedImageRepository().Create()
 								Expect(err).NotTo(HaveOccurred())
 							}
 						}
@@ -186,10 +208,10 @@ var _ = framework.E2ESuiteDescribe(Label("e2e-demo"), func() {
 						pipelineRun, err := fw.AsKubeDeveloper.HasController.GetComponentPipelineRun(component.Name, application.Name, namespace, "")
 						Expect(err).ShouldNot(HaveOccurred(), "\nfailed to get pipelinerun: %v", err)
 
-						// Store failed pipelineRun logs and yamls under the ARTIFACT_DIR
-						err = tekton.StorePipelineRun(pipelineRun, "e2e-demo-tests", fw.AsKubeAdmin.CommonController)
+						// Store failed pipelineRun logs and yamls of every registered cluster under the ARTIFACT_DIR
+						err = fw.StorePipelineRuns("e2e-demo-tests", namespace)
 						if err != nil {
-							GinkgoWriter.Printf("\nfailed to store pipelineRun: %s\n", err.Error())
+							GinkgoWriter.Printf("\nfailed to store pipelineRuns: %s\n", err.Error())
 						}
 
 						err = fw.AsKubeAdmin.TektonController.DeletePipelineRun(pipelineRun.Name, namespace)
@@ -206,6 +228,12 @@ var _ = framework.E2ESuiteDescribe(Label("e2e-demo"), func() {
 					}
 				})
 
+				It(fmt.Sprintf("checks the gitops repository contains the base manifests of component %s", componentTest.Name), func() {
+					component, err = fw.AsKubeAdmin.HasController.GetHasComponent(component.Name, namespace)
+					Expect(err).NotTo(HaveOccurred())
+					verifyGitOpsManifests(component.Name, "", gitops.ComponentExpectation{Image: component.Spec.ContainerImage, RoutePort: component.Spec.TargetPort})
+				})
+
 				It("finds the snapshot and checks if it is marked as successful", func() {
 					timeout = time.Second * 600
 					interval = time.Second * 10
@@ -236,6 +264,17 @@ var _ = framework.E2ESuiteDescribe(Label("e2e-demo"), func() {
 					}, timeout, interval).Should(BeTrue(), fmt.Sprintf("time out when trying to check if SnapshotEnvironmentBinding is created (snapshot: %s, env: %s)", snapshot.Name, env.Name))
 				})
 
+				It(fmt.Sprintf("checks the gitops repository contains the %s overlay of component %s", EnvironmentName, componentTest.Name), func() {
+					expected := gitops.ComponentExpectation{}
+					for _, c := range snapshot.Spec.Components {
+						if c.Name == component.Name {
+							expected.Image = c.ContainerImage
+						}
+					}
+					Expect(expected.Image).NotTo(BeEmpty(), "snapshot %s doesn't contain component %s", snapshot.Name, component.Name)
+					verifyGitOpsManifests(component.Name, env.Name, expected)
+				})
+
 				// Deploy the component using gitops and check for the health
 				It(fmt.Sprintf("deploys component %s using gitops", component.Name), func() {
 					var deployment *appsv1.Deployment
@@ -273,6 +312,8 @@ var _ = framework.E2ESuiteDescribe(Label("e2e-demo"), func() {
 						_, err = fw.AsKubeDeveloper.HasController.ScaleComponentReplicas(component, int(*componentTest.K8sSpec.Replicas))
 						Expect(err).NotTo(HaveOccurred())
 
+						verifyGitOpsManifests(component.Name, "", gitops.ComponentExpectation{Replicas: componentTest.K8sSpec.Replicas})
+
 						Eventually(func() bool {
 							deployment, _ := fw.AsKubeDeveloper.CommonController.GetDeployment(component.Name, namespace)
 							if err != nil && !errors.IsNotFound(err) {
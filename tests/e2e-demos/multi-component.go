@@ -27,10 +27,6 @@ const (
 
 var runtimeSupported = []string{"Dockerfile", "Node.js", "Go", "Quarkus", "Python", "JavaScript", "springboot"}
 
-const (
-	multiComponentTestNamespace string = "multi-comp-e2e"
-)
-
 var _ = framework.E2ESuiteDescribe(Label("e2e-demo", "multi-component"), func() {
 	defer GinkgoRecover()
 
@@ -108,7 +104,7 @@ var _ = framework.E2ESuiteDescribe(Label("e2e-demo", "multi-component"), func()
 				}
 
 				// Initialize the tests controllers
-				fw, err = framework.NewFramework(utils.GetGeneratedNamespace(multiComponentTestNamespace))
+				fw, err = framework.NewFramework(utils.GetGeneratedNamespace(constants.MULTI_COMPONENT_E2E_USER_PREFIX))
 				Expect(err).NotTo(HaveOccurred())
 				namespace = fw.UserNamespace
 				Expect(namespace).NotTo(BeEmpty())
@@ -200,7 +196,7 @@ var _ = framework.E2ESuiteDescribe(Label("e2e-demo", "multi-component"), func()
 
 				It(fmt.Sprintf("creates multiple components in application %s", suite.ApplicationName), func() {
 					for _, component := range cdq.Status.ComponentDetected {
-						c, err := fw.AsKubeDeveloper.HasController.CreateComponentFromStub(component, component.ComponentStub.ComponentName, namespace, SPIGithubSecretName, application.Name)
+						c, err := fw.AsKubeDeveloper.HasController.NewComponentBuilder(application.Name, component.ComponentStub.ComponentName, namespace).FromStub(component.ComponentStub).WithSecret(SPIGithubSecretName).WithSkipInitialChecks(true).WithGeneratedImageRepository().Create()
 						Expect(err).NotTo(HaveOccurred())
 						Expect(c.Name).To(Equal(component.ComponentStub.ComponentName))
 						Expect(utils.Contains(runtimeSupported, component.ProjectType), "unsupported runtime used for multi component tests")
@@ -0,0 +1,74 @@
+package has
+
+import (
+	_ "embed"
+	"fmt"
+
+	"github.com/devfile/library/pkg/util"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
+	"github.com/redhat-appstudio/e2e-tests/pkg/framework"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils/has"
+	v1 "k8s.io/api/core/v1"
+)
+
+// Corpus of source repositories with the expected ComponentDetectionQuery results
+//
+//go:embed config/cdq.yaml
+var componentDetectionCorpus []byte
+
+/*
+ * Component: application-service
+ * Description: Runs a ComponentDetectionQuery for each repository of the corpus and compares the detected components with the expected ones
+ */
+
+var _ = framework.HASSuiteDescribe("[test_id:03] component detection", Label("has", "cdq"), func() {
+	defer GinkgoRecover()
+
+	var fw *framework.Framework
+	var err error
+	var testNamespace string
+
+	corpus, err := has.ParseComponentDetectionCorpus(componentDetectionCorpus)
+	Expect(err).NotTo(HaveOccurred())
+
+	BeforeAll(func() {
+		fw, err = framework.NewFramework(utils.GetGeneratedNamespace(constants.HAS_CDQ_USER_PREFIX))
+		Expect(err).NotTo(HaveOccurred())
+		testNamespace = fw.UserNamespace
+		Expect(testNamespace).NotTo(BeEmpty())
+	})
+
+	AfterAll(func() {
+		if !CurrentSpecReport().Failed() {
+			Expect(fw.SandboxController.DeleteUserSignup(fw.UserName)).NotTo(BeFalse())
+		}
+	})
+
+	entries := []interface{}{func(c has.ComponentDetectionCase) {
+		if c.Skip != "" {
+			Skip(c.Skip)
+		}
+
+		secret := ""
+		if c.Private {
+			Expect(utils.CheckIfEnvironmentExists(constants.GITHUB_TOKEN_ENV)).Should(BeTrue(), "%s environment variable is not set", constants.GITHUB_TOKEN_ENV)
+			credentials := `{"access_token":"` + utils.GetEnv(constants.GITHUB_TOKEN_ENV, "") + `"}`
+			secret = fw.AsKubeDeveloper.SPIController.InjectManualSPIToken(testNamespace, c.URL, credentials, v1.SecretTypeBasicAuth, SPIGithubSecretName)
+		}
+
+		cdqName := fmt.Sprintf("cdq-%s", util.GenerateRandomString(6))
+		cdq, err := fw.AsKubeDeveloper.HasController.CreateComponentDetectionQuery(cdqName, testNamespace, c.URL, c.Revision, c.Context, secret, len(c.Components) > 1)
+		Expect(err).NotTo(HaveOccurred(), "ComponentDetectionQuery for %s did not complete successfully", c.URL)
+		DeferCleanup(fw.AsKubeDeveloper.HasController.DeleteHasComponentDetectionQuery, cdqName, testNamespace)
+
+		Expect(has.VerifyComponentDetection(cdq, c)).To(Succeed())
+	}}
+	for _, c := range corpus.Repositories {
+		entries = append(entries, Entry(c.Name, c))
+	}
+
+	DescribeTable("detects the expected components in", entries...)
+})
@@ -136,7 +136,7 @@ var _ = framework.HASSuiteDescribe("[test_id:02] private devfile source", Label(
 	})
 
 	It("creates Red Hat AppStudio Quarkus component", func() {
-		_, err := fw.AsKubeDeveloper.HasController.CreateComponentFromStub(compDetected, componentName, testNamespace, oauthSecretName, applicationName)
+		_, err := fw.AsKubeDeveloper.HasController.NewComponentBuilder(applicationName, compDetected.ComponentStub.ComponentName, testNamespace).FromStub(compDetected.ComponentStub).WithSecret(oauthSecretName).WithSkipInitialChecks(true).WithGeneratedImageRepository().Create()
 		Expect(err).NotTo(HaveOccurred())
 	})
 })
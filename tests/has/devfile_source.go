@@ -10,6 +10,7 @@ import (
 	appservice "github.com/redhat-appstudio/application-api/api/v1alpha1"
 	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils/has"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -86,12 +87,12 @@ var _ = framework.HASSuiteDescribe("[test_id:01] DEVHAS-62 devfile source", Labe
 	})
 
 	It("checks Red Hat AppStudio Application health", func() {
-		Eventually(func() string {
+		Eventually(func() *appservice.Application {
 			application, err = fw.AsKubeDeveloper.HasController.GetHasApplication(applicationName, testNamespace)
 			Expect(err).NotTo(HaveOccurred())
 
-			return application.Status.Devfile
-		}, 3*time.Minute, 100*time.Millisecond).Should(Not(BeEmpty()), "Error creating gitOps repository")
+			return application
+		}, 3*time.Minute, 100*time.Millisecond).Should(has.HaveGitOpsRepository(), "Error creating gitOps repository")
 
 		Eventually(func() bool {
 			// application info should be stored even after deleting the application in application variable
@@ -126,8 +127,23 @@ var _ = framework.HASSuiteDescribe("[test_id:01] DEVHAS-62 devfile source", Labe
 	})
 
 	It("creates Red Hat AppStudio Quarkus component", func() {
-		_, err := fw.AsKubeDeveloper.HasController.CreateComponentFromStub(compDetected, componentName, testNamespace, "", applicationName)
+		component, err := fw.AsKubeDeveloper.HasController.NewComponentBuilder(applicationName, compDetected.ComponentStub.ComponentName, testNamespace).FromStub(compDetected.ComponentStub).WithSkipInitialChecks(true).WithGeneratedImageRepository().Create()
 		Expect(err).NotTo(HaveOccurred())
+
+		// The status of the created component is empty, HAS fills it in once the component is reconciled
+		Eventually(func() *appservice.Component {
+			component, err := fw.AsKubeDeveloper.HasController.GetHasComponent(component.Name, testNamespace)
+			Expect(err).NotTo(HaveOccurred())
+
+			return component
+		}, 1*time.Minute, 1*time.Second).Should(has.HaveGitOpsRepository(), "Component status doesn't contain the gitops repository")
+
+		Eventually(func() *appservice.Application {
+			application, err = fw.AsKubeDeveloper.HasController.GetHasApplication(applicationName, testNamespace)
+			Expect(err).NotTo(HaveOccurred())
+
+			return application
+		}, 1*time.Minute, 1*time.Second).Should(has.HaveDevfileWithComponent(component.Spec.ComponentName), "Application devfile doesn't contain the created component")
 	})
 
 	It("gitops Repository should not be deleted when component gets deleted", func() {
@@ -136,8 +152,7 @@ var _ = framework.HASSuiteDescribe("[test_id:01] DEVHAS-62 devfile source", Labe
 		for _, comp2Detected = range cdq.Status.ComponentDetected {
 			comp2Detected.ComponentStub.ComponentName = "java-quarkus2"
 		}
-		component2Name := fmt.Sprintf(QuarkusComponentName+"-%s", util.GenerateRandomString(10))
-		component2, err := fw.AsKubeDeveloper.HasController.CreateComponentFromStub(comp2Detected, component2Name, testNamespace, "", applicationName)
+		component2, err := fw.AsKubeDeveloper.HasController.NewComponentBuilder(applicationName, comp2Detected.ComponentStub.ComponentName, testNamespace).FromStub(comp2Detected.ComponentStub).WithSkipInitialChecks(true).WithGeneratedImageRepository().Create()
 		Expect(err).NotTo(HaveOccurred())
 
 		err = fw.AsKubeDeveloper.HasController.DeleteHasComponent(component2.Name, testNamespace, false)
@@ -9,9 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/redhat-appstudio/e2e-tests/pkg/framework"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
-	"github.com/redhat-appstudio/e2e-tests/pkg/utils/tekton"
 	"k8s.io/apimachinery/pkg/api/meta"
-	"knative.dev/pkg/apis"
 
 	appstudioApi "github.com/redhat-appstudio/application-api/api/v1alpha1"
 
@@ -66,7 +64,7 @@ var _ = framework.IntegrationServiceSuiteDescribe("Integration Service E2E tests
 			timeout = time.Minute * 4
 			interval = time.Second * 1
 			// Create a component with Git Source URL being defined
-			originalComponent, err = f.AsKubeAdmin.HasController.CreateComponent(applicationName, componentName, appStudioE2EApplicationsNamespace, gitSourceURL, "", "", outputContainerImage, "", true)
+			originalComponent, err = f.AsKubeAdmin.HasController.NewComponentBuilder(applicationName, componentName, appStudioE2EApplicationsNamespace).WithGitSource(gitSourceURL, "").WithOutputImage(outputContainerImage).WithSkipInitialChecks(true).Create()
 			Expect(err).ShouldNot(HaveOccurred())
 			Expect(originalComponent).NotTo(BeNil())
 
@@ -107,19 +105,18 @@ var _ = framework.IntegrationServiceSuiteDescribe("Integration Service E2E tests
 				pipelineRun, err := f.AsKubeAdmin.IntegrationController.GetBuildPipelineRun(componentName, applicationName, appStudioE2EApplicationsNamespace, false, "")
 				Expect(err).ShouldNot(HaveOccurred())
 
-				for _, condition := range pipelineRun.Status.Conditions {
-					GinkgoWriter.Printf("PipelineRun %s Status.Conditions.Reason: %s\n", pipelineRun.Name, condition.Reason)
-
-					if !pipelineRun.IsDone() {
-						return false
-					}
+				if condition := pipelineRun.Condition(); condition != nil {
+					GinkgoWriter.Printf("PipelineRun %s Status.Conditions.Reason: %s\n", pipelineRun.Name(), condition.Reason)
+				}
+				if !pipelineRun.IsDone() {
+					return false
+				}
 
-					if !pipelineRun.GetStatusCondition().GetCondition(apis.ConditionSucceeded).IsTrue() {
-						failMessage := tekton.GetFailedPipelineRunLogs(f.AsKubeAdmin.CommonController, pipelineRun)
-						Fail(failMessage)
-					}
+				if !pipelineRun.Succeeded() {
+					failMessage := f.AsKubeAdmin.TektonController.GetFailedPipelineRunAccessorLogs(pipelineRun)
+					Fail(failMessage)
 				}
-				return pipelineRun.IsDone()
+				return true
 			}, timeout, interval).Should(BeTrue(), "timed out when waiting for the PipelineRun to finish")
 		}
 
@@ -232,18 +229,18 @@ var _ = framework.IntegrationServiceSuiteDescribe("Integration Service E2E tests
 							pipelineRun, err := f.AsKubeAdmin.IntegrationController.GetIntegrationPipelineRun(testScenario.Name, snapshot_push.Name, appStudioE2EApplicationsNamespace)
 							Expect(err).ShouldNot(HaveOccurred())
 
-							for _, condition := range pipelineRun.Status.Conditions {
-								GinkgoWriter.Printf("PipelineRun %s Status.Conditions.Reason: %s\n", pipelineRun.Name, condition.Reason)
-								if !pipelineRun.IsDone() {
-									return false
-								}
+							if condition := pipelineRun.Condition(); condition != nil {
+								GinkgoWriter.Printf("PipelineRun %s Status.Conditions.Reason: %s\n", pipelineRun.Name(), condition.Reason)
+							}
+							if !pipelineRun.IsDone() {
+								return false
+							}
 
-								if !pipelineRun.GetStatusCondition().GetCondition(apis.ConditionSucceeded).IsTrue() {
-									failMessage := tekton.GetFailedPipelineRunLogs(f.AsKubeAdmin.CommonController, pipelineRun)
-									Fail(failMessage)
-								}
+							if !pipelineRun.Succeeded() {
+								failMessage := f.AsKubeAdmin.TektonController.GetFailedPipelineRunAccessorLogs(pipelineRun)
+								Fail(failMessage)
 							}
-							return pipelineRun.IsDone()
+							return true
 						}, timeout, interval).Should(BeTrue(), "timed out when waiting for the PipelineRun to finish")
 					}
 				})
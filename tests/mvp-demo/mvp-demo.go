@@ -2,20 +2,16 @@ package mvp
 
 import (
 	"context"
+	"embed"
 	"fmt"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/devfile/library/pkg/util"
-	ecp "github.com/enterprise-contract/enterprise-contract-controller/api/v1alpha1"
-	"github.com/google/go-containerregistry/pkg/authn"
-	"github.com/google/go-containerregistry/pkg/name"
-	remoteimg "github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/google/go-github/v44/github"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
-	"knative.dev/pkg/apis"
 
 	appstudioApi "github.com/redhat-appstudio/application-api/api/v1alpha1"
 	buildservice "github.com/redhat-appstudio/build-service/api/v1alpha1"
@@ -23,18 +19,17 @@ import (
 	"github.com/redhat-appstudio/e2e-tests/pkg/framework"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils/build"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils/fixtures"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils/tekton"
+	"github.com/redhat-appstudio/e2e-tests/pkg/utils/tekton/bundle"
 	"github.com/redhat-appstudio/jvm-build-service/pkg/apis/jvmbuildservice/v1alpha1"
 	releaseApi "github.com/redhat-appstudio/release-service/api/v1alpha1"
 	tektonapi "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/pointer"
-
-	"sigs.k8s.io/yaml"
 )
 
 const (
@@ -43,8 +38,7 @@ const (
 	componentDefaultBranchName = "main"
 
 	// Kubernetes resource names
-	testNamespacePrefix = "mvp-demo-dev-namespace"
-	managedNamespace    = "mvp-demo-managed-namespace"
+	managedNamespace = "mvp-demo-managed-namespace"
 
 	appName       = "mvp-test-app"
 	componentName = "mvp-test-component"
@@ -65,6 +59,11 @@ const (
 	pipelineRunPollingInterval = time.Second * 10
 )
 
+// Release configuration of the managed namespace
+//
+//go:embed fixtures
+var releaseFixtures embed.FS
+
 var sampleRepoURL = fmt.Sprintf("https://github.com/%s/%s", utils.GetEnv(constants.GITHUB_E2E_ORGANIZATION_ENV, "redhat-appstudio-qe"), sampleRepoName)
 
 var _ = framework.MvpDemoSuiteDescribe("MVP Demo tests", Label("mvp-demo"), func() {
@@ -79,6 +78,7 @@ var _ = framework.MvpDemoSuiteDescribe("MVP Demo tests", Label("mvp-demo"), func
 	var kc tekton.KubeController
 
 	var pipelineRun *tektonapi.PipelineRun
+	var releasePipelineRun *tekton.PipelineRunAccessor
 	var release *releaseApi.Release
 	var snapshot *appstudioApi.Snapshot
 
@@ -89,7 +89,7 @@ var _ = framework.MvpDemoSuiteDescribe("MVP Demo tests", Label("mvp-demo"), func
 		untrustedPipelineBundle, err = createUntrustedPipelineBundle()
 		klog.Info(untrustedPipelineBundle)
 		Expect(err).NotTo(HaveOccurred())
-		f, err = framework.NewFramework(utils.GetGeneratedNamespace(testNamespacePrefix))
+		f, err = framework.NewFramework(utils.GetGeneratedNamespace(constants.MVP_DEMO_USER_PREFIX))
 		Expect(err).NotTo(HaveOccurred())
 		userNamespace = f.UserNamespace
 		Expect(userNamespace).NotTo(BeEmpty())
@@ -105,68 +105,29 @@ var _ = framework.MvpDemoSuiteDescribe("MVP Demo tests", Label("mvp-demo"), func
 			Tektonctrl: *f.AsKubeAdmin.TektonController,
 		}
 
-		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "release-pull-secret", Namespace: managedNamespace},
-			Data: map[string][]byte{".dockerconfigjson": sharedSecret.Data[".dockerconfigjson"]},
-			Type: corev1.SecretTypeDockerConfigJson,
-		}
-
 		// release stuff
 		_, err = f.AsKubeAdmin.CommonController.CreateTestNamespace(managedNamespace)
 		Expect(err).ShouldNot(HaveOccurred())
 
-		secret.Namespace = managedNamespace
-		secret.Name = "release-pull-secret"
-		secret.Type = corev1.SecretTypeDockerConfigJson
-		_, err = f.AsKubeAdmin.CommonController.CreateSecret(managedNamespace, secret)
-		Expect(err).ShouldNot(HaveOccurred())
-		_, err = f.AsKubeAdmin.CommonController.CreateServiceAccount("release-service-account", managedNamespace, []corev1.ObjectReference{{Name: secret.Name}})
-		Expect(err).NotTo(HaveOccurred())
-
 		publicKey, err := kc.GetTektonChainsPublicKey()
 		Expect(err).ToNot(HaveOccurred())
 
-		Expect(kc.CreateOrUpdateSigningSecret(publicKey, "cosign-public-key", managedNamespace)).To(Succeed())
-
-		_, err = f.AsKubeAdmin.ReleaseController.CreateReleasePlan("source-releaseplan", userNamespace, appName, managedNamespace, "")
-		Expect(err).NotTo(HaveOccurred())
-
-		_, err = f.AsKubeAdmin.ReleaseController.CreateReleaseStrategy("mvp-strategy", managedNamespace, "release", constants.ReleasePipelineImageRef, "mvp-policy", "release-service-account", []releaseApi.Params{
-			{Name: "extraConfigGitUrl", Value: "https://github.com/redhat-appstudio-qe/strategy-configs.git"},
-			{Name: "extraConfigPath", Value: "mvp.yaml"},
-			{Name: "extraConfigRevision", Value: "main"},
-		})
-		Expect(err).NotTo(HaveOccurred())
-
-		_, err = f.AsKubeAdmin.ReleaseController.CreateReleasePlanAdmission("demo", userNamespace, appName, managedNamespace, "", "", "mvp-strategy")
-		Expect(err).NotTo(HaveOccurred())
-
 		defaultEcPolicy, err := kc.GetEnterpriseContractPolicy("default", "enterprise-contract-service")
 		Expect(err).NotTo(HaveOccurred())
 
-		defaultEcPolicySpec := ecp.EnterpriseContractPolicySpec{
-			Description: "Red Hat's enterprise requirements",
-			PublicKey:   string(publicKey),
-			Sources:     defaultEcPolicy.Spec.Sources,
-			Configuration: &ecp.EnterpriseContractPolicyConfiguration{
-				Collections: []string{"minimal"},
-				Exclude:     []string{"cve"},
+		// The rest of the release configuration is applied from the fixtures directory
+		_, err = fixtures.NewApplier(f.AsKubeAdmin.CommonController.CustomClient).ApplyFS(releaseFixtures, "fixtures", fixtures.Values{
+			Namespace: managedNamespace,
+			Images:    map[string]string{"release-pipeline": constants.ReleasePipelineImageRef},
+			Config: map[string]interface{}{
+				"application":      appName,
+				"userNamespace":    userNamespace,
+				"dockerConfigJson": string(sharedSecret.Data[".dockerconfigjson"]),
+				"cosignPublicKey":  string(publicKey),
+				"ecSources":        defaultEcPolicy.Spec.Sources,
 			},
-		}
-		_, err = f.AsKubeAdmin.TektonController.CreateEnterpriseContractPolicy("mvp-policy", managedNamespace, defaultEcPolicySpec)
-		Expect(err).NotTo(HaveOccurred())
-
-		_, err = f.AsKubeAdmin.TektonController.CreatePVCInAccessMode("release-pvc", managedNamespace, corev1.ReadWriteOnce)
-		Expect(err).NotTo(HaveOccurred())
-
-		_, err = f.AsKubeAdmin.CommonController.CreateRole("role-release-service-account", managedNamespace, map[string][]string{
-			"apiGroupsList": {""},
-			"roleResources": {"secrets"},
-			"roleVerbs":     {"get", "list", "watch"},
 		})
 		Expect(err).NotTo(HaveOccurred())
-
-		_, err = f.AsKubeAdmin.CommonController.CreateRoleBinding("role-release-service-account-binding", managedNamespace, "ServiceAccount", "release-service-account", "Role", "role-release-service-account", "rbac.authorization.k8s.io")
-		Expect(err).NotTo(HaveOccurred())
 	})
 	AfterAll(func() {
 		err = f.AsKubeAdmin.CommonController.Github.DeleteRef(sampleRepoName, componentNewBaseBranch)
@@ -213,7 +174,7 @@ var _ = framework.MvpDemoSuiteDescribe("MVP Demo tests", Label("mvp-demo"), func
 		})
 
 		It("sample app can be built successfully", func() {
-			_, err = f.AsKubeAdmin.HasController.CreateComponent(appName, componentName, userNamespace, sampleRepoURL, componentNewBaseBranch, "", constants.DefaultImagePushRepo, "", true)
+			_, err = f.AsKubeAdmin.HasController.NewComponentBuilder(appName, componentName, userNamespace).WithGitSource(sampleRepoURL, componentNewBaseBranch).WithOutputImage(constants.DefaultImagePushRepo).WithSkipInitialChecks(true).Create()
 			Expect(err).ShouldNot(HaveOccurred())
 			Expect(f.AsKubeAdmin.HasController.WaitForComponentPipelineToBeFinished(f.AsKubeAdmin.CommonController, componentName, appName, userNamespace, "")).To(Succeed())
 		})
@@ -240,12 +201,12 @@ var _ = framework.MvpDemoSuiteDescribe("MVP Demo tests", Label("mvp-demo"), func
 
 		It("Release PipelineRun is triggered", func() {
 			Eventually(func() bool {
-				pipelineRun, err = f.AsKubeAdmin.ReleaseController.GetPipelineRunInNamespace(managedNamespace, release.Name, release.Namespace)
+				releasePipelineRun, err = f.AsKubeAdmin.ReleaseController.GetPipelineRunInNamespace(managedNamespace, release.Name, release.Namespace)
 				if err != nil {
 					GinkgoWriter.Printf("pipelineRun for component '%s' in namespace '%s' not created yet: %+v\n", componentName, managedNamespace, err)
 					return false
 				}
-				return pipelineRun.HasStarted()
+				return releasePipelineRun.HasStarted()
 			}, pipelineRunStartedTimeout, defaultPollingInterval).Should(BeTrue())
 		})
 
@@ -262,12 +223,12 @@ var _ = framework.MvpDemoSuiteDescribe("MVP Demo tests", Label("mvp-demo"), func
 
 		It("Release PipelineRun should eventually fail", func() {
 			Eventually(func() bool {
-				pipelineRun, err = f.AsKubeAdmin.ReleaseController.GetPipelineRunInNamespace(managedNamespace, release.Name, release.Namespace)
+				releasePipelineRun, err = f.AsKubeAdmin.ReleaseController.GetPipelineRunInNamespace(managedNamespace, release.Name, release.Namespace)
 				if err != nil {
 					GinkgoWriter.Printf("failed to get PipelineRun for a release '%s' in '%s' namespace: %+v\n", release.Name, managedNamespace, err)
 					return false
 				}
-				return pipelineRun.IsDone()
+				return releasePipelineRun.IsDone()
 			}, releasePipelineTimeout, pipelineRunPollingInterval).Should(BeTrue())
 		})
 
@@ -419,12 +380,12 @@ var _ = framework.MvpDemoSuiteDescribe("MVP Demo tests", Label("mvp-demo"), func
 			Expect(release.Name).ToNot(BeEmpty())
 
 			Eventually(func() bool {
-				pipelineRun, err = f.AsKubeAdmin.ReleaseController.GetPipelineRunInNamespace(managedNamespace, release.Name, release.Namespace)
+				releasePipelineRun, err = f.AsKubeAdmin.ReleaseController.GetPipelineRunInNamespace(managedNamespace, release.Name, release.Namespace)
 				if err != nil {
 					GinkgoWriter.Printf("pipelineRun for component '%s' in namespace '%s' not created yet: %+v\n", componentName, managedNamespace, err)
 					return false
 				}
-				return pipelineRun.HasStarted()
+				return releasePipelineRun.HasStarted()
 			}, pipelineRunStartedTimeout, defaultPollingInterval).Should(BeTrue())
 
 			Eventually(func() bool {
@@ -439,12 +400,12 @@ var _ = framework.MvpDemoSuiteDescribe("MVP Demo tests", Label("mvp-demo"), func
 
 		It("Release PipelineRun should eventually succeed and associated Release should be marked as succeeded", func() {
 			Eventually(func() bool {
-				pipelineRun, err = f.AsKubeAdmin.ReleaseController.GetPipelineRunInNamespace(managedNamespace, release.Name, release.Namespace)
+				releasePipelineRun, err = f.AsKubeAdmin.ReleaseController.GetPipelineRunInNamespace(managedNamespace, release.Name, release.Namespace)
 				if err != nil {
 					GinkgoWriter.Printf("failed to get PipelineRun for a release '%s' in '%s' namespace: %+v\n", release.Name, managedNamespace, err)
 					return false
 				}
-				return pipelineRun.IsDone() && pipelineRun.GetStatusCondition().GetCondition(apis.ConditionSucceeded).IsTrue()
+				return releasePipelineRun.IsDone() && releasePipelineRun.Succeeded()
 			}, releasePipelineTimeout, pipelineRunPollingInterval).Should(BeTrue())
 
 			Eventually(func() bool {
@@ -490,61 +451,14 @@ var _ = framework.MvpDemoSuiteDescribe("MVP Demo tests", Label("mvp-demo"), func
 })
 
 func createUntrustedPipelineBundle() (string, error) {
-	var err error
-	var defaultBundleRef string
-	var tektonObj runtime.Object
-
-	tag := fmt.Sprintf("%d-%s", time.Now().Unix(), util.GenerateRandomString(4))
-	var newBuildahTaskRef, _ = name.ParseReference(fmt.Sprintf("%s:task-bundle-%s", constants.DefaultImagePushRepo, tag))
-	var newDockerBuildPipelineRef, _ = name.ParseReference(fmt.Sprintf("%s:pipeline-bundle-%s", constants.DefaultImagePushRepo, tag))
-	var newBuildImage = "quay.io/containers/buildah:latest"
-	var newTaskYaml, newPipelineYaml []byte
-
-	if err = utils.CreateDockerConfigFile(os.Getenv("QUAY_TOKEN")); err != nil {
+	if err := utils.CreateDockerConfigFile(os.Getenv("QUAY_TOKEN")); err != nil {
 		return "", fmt.Errorf("failed to create docker config file: %+v", err)
 	}
-	if defaultBundleRef, err = utils.GetDefaultPipelineBundleRef(constants.BuildPipelineSelectorYamlURL, "Docker build"); err != nil {
-		return "", fmt.Errorf("failed to get the pipeline bundle ref: %+v", err)
-	}
-	if tektonObj, err = utils.ExtractTektonObjectFromBundle(defaultBundleRef, "pipeline", "docker-build"); err != nil {
-		return "", fmt.Errorf("failed to extract the Tekton Pipeline from bundle: %+v", err)
+	pipelines, err := bundle.ApplyOverrides([]bundle.Override{
+		{Selector: "Docker build", Task: "buildah", Step: "build", Image: "quay.io/containers/buildah:latest"},
+	}, bundle.OverrideOptions{})
+	if err != nil {
+		return "", err
 	}
-	dockerPipelineObj := tektonObj.(tektonapi.PipelineObject)
-
-	var currentBuildahTaskRef string
-	for _, t := range dockerPipelineObj.PipelineSpec().Tasks {
-		if t.TaskRef.Name == "buildah" {
-			currentBuildahTaskRef = t.TaskRef.Bundle
-			t.TaskRef.Bundle = newBuildahTaskRef.String()
-		}
-	}
-	if tektonObj, err = utils.ExtractTektonObjectFromBundle(currentBuildahTaskRef, "task", "buildah"); err != nil {
-		return "", fmt.Errorf("failed to extract the Tekton Task from bundle: %+v", err)
-	}
-	taskObj := tektonObj.(tektonapi.TaskObject)
-
-	for i, s := range taskObj.TaskSpec().Steps {
-		if s.Name == "build" {
-			taskObj.TaskSpec().Steps[i].Image = newBuildImage
-		}
-	}
-
-	if newTaskYaml, err = yaml.Marshal(taskObj); err != nil {
-		return "", fmt.Errorf("error when marshalling a new task to YAML: %v", err)
-	}
-	if newPipelineYaml, err = yaml.Marshal(dockerPipelineObj); err != nil {
-		return "", fmt.Errorf("error when marshalling a new pipeline to YAML: %v", err)
-	}
-
-	keychain := authn.NewMultiKeychain(authn.DefaultKeychain)
-	authOption := remoteimg.WithAuthFromKeychain(keychain)
-
-	if err = utils.BuildAndPushTektonBundle(newTaskYaml, newBuildahTaskRef, authOption); err != nil {
-		return "", fmt.Errorf("error when building/pushing a tekton task bundle: %v", err)
-	}
-	if err = utils.BuildAndPushTektonBundle(newPipelineYaml, newDockerBuildPipelineRef, authOption); err != nil {
-		return "", fmt.Errorf("error when building/pushing a tekton pipeline bundle: %v", err)
-	}
-
-	return newDockerBuildPipelineRef.Name(), nil
+	return pipelines[0].Bundle, nil
 }
@@ -25,7 +25,7 @@ var _ = framework.ReleaseSuiteDescribe("[HACBS-738]test-release-service-default-
 
 	BeforeAll(func() {
 		// Initialize the tests controllers
-		fw, err = framework.NewFramework("release-e2e-bundle")
+		fw, err = framework.NewFramework(constants.RELEASE_E2E_BUNDLE_USER)
 		Expect(err).NotTo(HaveOccurred())
 		kubeController := tekton.KubeController{
 			Commonctrl: *fw.AsKubeAdmin.CommonController,
@@ -91,7 +91,7 @@ var _ = framework.ReleaseSuiteDescribe("[HACBS-738]test-release-service-default-
 		_, err = fw.AsKubeAdmin.HasController.CreateHasApplication(applicationNameDefault, devNamespace)
 		Expect(err).NotTo(HaveOccurred())
 
-		_, err = fw.AsKubeAdmin.HasController.CreateComponent(applicationNameDefault, componentName, devNamespace, gitSourceComponentUrl, "", containerImageUrl, "", "", false)
+		_, err = fw.AsKubeAdmin.HasController.NewComponentBuilder(applicationNameDefault, componentName, devNamespace).WithGitSource(gitSourceComponentUrl, "").WithOutputImage(containerImageUrl).WithSkipInitialChecks(false).Create()
 		Expect(err).NotTo(HaveOccurred())
 
 	})
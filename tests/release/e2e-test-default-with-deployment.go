@@ -6,6 +6,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
 	"github.com/redhat-appstudio/e2e-tests/pkg/framework"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils"
 	"github.com/redhat-appstudio/e2e-tests/pkg/utils/tekton"
@@ -25,7 +26,7 @@ var _ = framework.ReleaseSuiteDescribe("[HACBS-1199]test-release-e2e-with-deploy
 	var managedNamespace = utils.GetGeneratedNamespace("release-managed")
 
 	BeforeAll(func() {
-		fw, err = framework.NewFramework("release-e2e-bundle")
+		fw, err = framework.NewFramework(constants.RELEASE_E2E_BUNDLE_USER)
 		Expect(err).NotTo(HaveOccurred())
 
 		kubeController := tekton.KubeController{
@@ -96,7 +97,7 @@ var _ = framework.ReleaseSuiteDescribe("[HACBS-1199]test-release-e2e-with-deploy
 		_, err = fw.AsKubeAdmin.HasController.CreateHasApplication(applicationNameDefault, devNamespace)
 		Expect(err).NotTo(HaveOccurred())
 
-		_, err = fw.AsKubeAdmin.HasController.CreateComponent(applicationNameDefault, componentName, devNamespace, gitSourceComponentUrl, "", containerImageUrl, "", "", true)
+		_, err = fw.AsKubeAdmin.HasController.NewComponentBuilder(applicationNameDefault, componentName, devNamespace).WithGitSource(gitSourceComponentUrl, "").WithOutputImage(containerImageUrl).WithSkipInitialChecks(true).Create()
 		Expect(err).NotTo(HaveOccurred())
 
 	})
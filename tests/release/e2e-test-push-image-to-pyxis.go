@@ -31,7 +31,7 @@ var _ = framework.ReleaseSuiteDescribe("[HACBS-1571]test-release-e2e-push-image-
 
 	BeforeAll(func() {
 
-		fw, err = framework.NewFramework("release-e2e-pyxis")
+		fw, err = framework.NewFramework(constants.RELEASE_E2E_PYXIS_USER)
 		Expect(err).NotTo(HaveOccurred())
 
 		kubeController = tekton.KubeController{
@@ -133,7 +133,7 @@ var _ = framework.ReleaseSuiteDescribe("[HACBS-1571]test-release-e2e-push-image-
 		_, err = fw.AsKubeAdmin.HasController.CreateHasApplication(applicationNameDefault, devNamespace)
 		Expect(err).NotTo(HaveOccurred())
 
-		_, err = fw.AsKubeAdmin.HasController.CreateComponent(applicationNameDefault, componentName, devNamespace, gitSourceComponentUrl, "", containerImageUrl, "", "", true)
+		_, err = fw.AsKubeAdmin.HasController.NewComponentBuilder(applicationNameDefault, componentName, devNamespace).WithGitSource(gitSourceComponentUrl, "").WithOutputImage(containerImageUrl).WithSkipInitialChecks(true).Create()
 		Expect(err).NotTo(HaveOccurred())
 
 	})
@@ -9,6 +9,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	applicationapiv1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	"github.com/redhat-appstudio/e2e-tests/pkg/constants"
 	"github.com/redhat-appstudio/e2e-tests/pkg/framework"
 
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
@@ -17,7 +18,7 @@ import (
 )
 
 const (
-	DEFAULT_RELEASE_SERVICE_USER = "release-service-e2e"
+	DEFAULT_RELEASE_SERVICE_USER = constants.RELEASE_SERVICE_E2E_USER
 )
 
 var snapshotComponents = []applicationapiv1alpha1.SnapshotComponent{
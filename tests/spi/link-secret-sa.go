@@ -42,7 +42,7 @@ var _ = framework.SPISuiteDescribe(Label("spi-suite", "link-secret-sa"), func()
 		Describe("SVPI-406 - "+test.TestName, Ordered, func() {
 			BeforeAll(func() {
 				// Initialize the tests controllers
-				fw, err = framework.NewFramework(utils.GetGeneratedNamespace("spi-demos"))
+				fw, err = framework.NewFramework(utils.GetGeneratedNamespace(constants.SPI_DEMOS_USER_PREFIX))
 				Expect(err).NotTo(HaveOccurred())
 				namespace = fw.UserNamespace
 				Expect(namespace).NotTo(BeEmpty())
@@ -43,7 +43,7 @@ var _ = framework.SPISuiteDescribe(Label("spi-suite", "token-upload-rest-endpoin
 		Describe("SVPI-398 - Token upload rest endpoint: "+test.TestName, Ordered, func() {
 			BeforeAll(func() {
 				// Initialize the tests controllers
-				fw, err = framework.NewFramework(utils.GetGeneratedNamespace("spi-demos"))
+				fw, err = framework.NewFramework(utils.GetGeneratedNamespace(constants.SPI_DEMOS_USER_PREFIX))
 				Expect(err).NotTo(HaveOccurred())
 				namespace = fw.UserNamespace
 				Expect(namespace).NotTo(BeEmpty())